@@ -0,0 +1,6 @@
+// Package avropb defines the protobuf options controlling how protobuf messages map to Avro.
+//
+// Import avropb/options.proto to set them on the fields of a message, e.g.
+//
+//	string secret = 2 [(avro.ignore) = true];
+package avropb
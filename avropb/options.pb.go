@@ -0,0 +1,85 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.32.1
+// source: avropb/options.proto
+
+package avropb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+var file_avropb_options_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         51200,
+		Name:          "avro.ignore",
+		Tag:           "varint,51200,opt,name=ignore",
+		Filename:      "avropb/options.proto",
+	},
+}
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// ignore excludes the field from Avro serialization. The Avro field of the
+	// same name is encoded as null or its default, and skipped when decoding.
+	//
+	// optional bool ignore = 51200;
+	E_Ignore = &file_avropb_options_proto_extTypes[0]
+)
+
+var File_avropb_options_proto protoreflect.FileDescriptor
+
+const file_avropb_options_proto_rawDesc = "" +
+	"\n" +
+	"\x14avropb/options.proto\x12\x04avro\x1a google/protobuf/descriptor.proto:7\n" +
+	"\x06ignore\x12\x1d.google.protobuf.FieldOptions\x18\x80\x90\x03 \x01(\bR\x06ignoreB(Z&github.com/hamba/avro/v2/avropb;avropbb\x06proto3"
+
+var file_avropb_options_proto_goTypes = []any{
+	(*descriptorpb.FieldOptions)(nil), // 0: google.protobuf.FieldOptions
+}
+var file_avropb_options_proto_depIdxs = []int32{
+	0, // 0: avro.ignore:extendee -> google.protobuf.FieldOptions
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	0, // [0:1] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_avropb_options_proto_init() }
+func file_avropb_options_proto_init() {
+	if File_avropb_options_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_avropb_options_proto_rawDesc), len(file_avropb_options_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 1,
+			NumServices:   0,
+		},
+		GoTypes:           file_avropb_options_proto_goTypes,
+		DependencyIndexes: file_avropb_options_proto_depIdxs,
+		ExtensionInfos:    file_avropb_options_proto_extTypes,
+	}.Build()
+	File_avropb_options_proto = out.File
+	file_avropb_options_proto_goTypes = nil
+	file_avropb_options_proto_depIdxs = nil
+}
@@ -3,9 +3,12 @@ package avro_test
 import (
 	"io"
 	"os"
+	"strconv"
+	"sync"
 	"testing"
 
 	"github.com/hamba/avro/v2"
+	testpb "github.com/hamba/avro/v2/testdata/protobuf"
 )
 
 type Superhero struct {
@@ -79,6 +82,33 @@ func BenchmarkSuperheroEncode(b *testing.B) {
 	}
 }
 
+func BenchmarkSuperheroMarshalTo(b *testing.B) {
+	schema, err := avro.ParseFiles("testdata/superhero.avsc")
+	if err != nil {
+		panic(err)
+	}
+
+	super := &Superhero{
+		ID:            234765,
+		AffiliationID: 9867,
+		Name:          "Wolverine",
+		Life:          85.25,
+		Energy:        32.75,
+		Powers: []*Superpower{
+			{ID: 2345, Name: "Bone Claws", Damage: 5, Energy: 1.15, Passive: false},
+			{ID: 2346, Name: "Regeneration", Damage: -2, Energy: 0.55, Passive: true},
+			{ID: 2347, Name: "Adamant skeleton", Damage: -10, Energy: 0, Passive: true},
+		},
+	}
+	buf := make([]byte, 0, 512)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf, _ = avro.MarshalTo(schema, super, buf[:0])
+	}
+}
+
 func BenchmarkPartialSuperheroDecode(b *testing.B) {
 	data, err := os.ReadFile("testdata/superhero.bin")
 	if err != nil {
@@ -172,3 +202,202 @@ func BenchmarkSuperheroWriteFlush(b *testing.B) {
 		_ = w.Flush()
 	}
 }
+
+var nestedMessageSchema = avro.MustParse(`{
+	"type": "record",
+	"name": "NestedMessage",
+	"fields": [
+		{"name": "id", "type": "int"},
+		{"name": "title", "type": "string"},
+		{
+			"name": "author",
+			"type": {
+				"type": "record",
+				"name": "BasicMessage",
+				"fields": [
+					{"name": "id", "type": "int"},
+					{"name": "name", "type": "string"},
+					{"name": "active", "type": "boolean"},
+					{"name": "score", "type": "double"}
+				]
+			}
+		}
+	]
+}`)
+
+func BenchmarkProtobufNestedMessageDecode(b *testing.B) {
+	data, err := avro.Marshal(nestedMessageSchema, &testpb.NestedMessage{
+		Id:     1,
+		Title:  "My Article",
+		Author: &testpb.BasicMessage{Id: 42, Name: "Author Name", Active: true, Score: 99.9},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		msg := &testpb.NestedMessage{}
+		_ = avro.Unmarshal(nestedMessageSchema, data, msg)
+	}
+}
+
+func BenchmarkProtobufNestedMessageEncode(b *testing.B) {
+	msg := &testpb.NestedMessage{
+		Id:     1,
+		Title:  "My Article",
+		Author: &testpb.BasicMessage{Id: 42, Name: "Author Name", Active: true, Score: 99.9},
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = avro.Marshal(nestedMessageSchema, msg)
+	}
+}
+
+func BenchmarkProtobufNestedMessageMarshalTo(b *testing.B) {
+	msg := &testpb.NestedMessage{
+		Id:     1,
+		Title:  "My Article",
+		Author: &testpb.BasicMessage{Id: 42, Name: "Author Name", Active: true, Score: 99.9},
+	}
+	buf := make([]byte, 0, 512)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf, _ = avro.MarshalTo(nestedMessageSchema, msg, buf[:0])
+	}
+}
+
+var deepMessage = &testpb.DeepMessage{
+	Id: 1,
+	Child: &testpb.DeepLevel2{
+		Id: 2,
+		Child: &testpb.DeepLevel3{
+			Id: 3,
+			Child: &testpb.DeepLevel4{
+				Id:    4,
+				Child: &testpb.DeepLevel5{Id: 5, Name: "leaf"},
+			},
+		},
+	},
+}
+
+func BenchmarkProtobufDeepMessageDecode(b *testing.B) {
+	schema := avro.MustParse(deepMessageSchema)
+	data, err := avro.Marshal(schema, deepMessage)
+	if err != nil {
+		panic(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		msg := &testpb.DeepMessage{}
+		_ = avro.Unmarshal(schema, data, msg)
+	}
+}
+
+func BenchmarkProtobufDeepMessageEncode(b *testing.B) {
+	schema := avro.MustParse(deepMessageSchema)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = avro.Marshal(schema, deepMessage)
+	}
+}
+
+var lineItemListSchema = avro.MustParse(`{
+	"type": "record",
+	"name": "LineItemList",
+	"fields": [
+		{"name": "items", "type": {"type": "array", "items": {
+			"type": "record",
+			"name": "LineItem",
+			"fields": [
+				{"name": "sku", "type": "string"},
+				{"name": "quantity", "type": "int"}
+			]
+		}}}
+	]
+}`)
+
+func newLineItemList(n int) *testpb.LineItemList {
+	list := &testpb.LineItemList{Items: make([]*testpb.LineItem, n)}
+	for i := range list.Items {
+		list.Items[i] = &testpb.LineItem{Sku: "SKU-" + strconv.Itoa(i), Quantity: int32(i)}
+	}
+	return list
+}
+
+func BenchmarkProtobufNestedArrayDecode(b *testing.B) {
+	data, err := avro.Marshal(lineItemListSchema, newLineItemList(10000))
+	if err != nil {
+		panic(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		msg := &testpb.LineItemList{}
+		_ = avro.Unmarshal(lineItemListSchema, data, msg)
+	}
+}
+
+func BenchmarkProtobufNestedArrayEncode(b *testing.B) {
+	msg := newLineItemList(10000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = avro.Marshal(lineItemListSchema, msg)
+	}
+}
+
+func BenchmarkSuperheroEncodePooledWriter(b *testing.B) {
+	schema, err := avro.ParseFiles("testdata/superhero.avsc")
+	if err != nil {
+		panic(err)
+	}
+
+	super := &Superhero{
+		ID:            234765,
+		AffiliationID: 9867,
+		Name:          "Wolverine",
+		Life:          85.25,
+		Energy:        32.75,
+		Powers: []*Superpower{
+			{ID: 2345, Name: "Bone Claws", Damage: 5, Energy: 1.15, Passive: false},
+			{ID: 2346, Name: "Regeneration", Damage: -2, Energy: 0.55, Passive: true},
+			{ID: 2347, Name: "Adamant skeleton", Damage: -10, Energy: 0, Passive: true},
+		},
+	}
+
+	b.Run("New", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			w := avro.NewWriter(nil, 512)
+			w.WriteVal(schema, super)
+			_ = w.Buffer()
+		}
+	})
+
+	b.Run("Pooled", func(b *testing.B) {
+		pool := sync.Pool{New: func() any { return avro.NewWriter(nil, 512) }}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			w := pool.Get().(*avro.Writer)
+			w.Reset(nil)
+			w.WriteVal(schema, super)
+			_ = w.Buffer()
+			pool.Put(w)
+		}
+	})
+}
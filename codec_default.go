@@ -8,7 +8,18 @@ import (
 )
 
 func createDefaultDecoder(d *decoderContext, field *Field, typ reflect2.Type) ValDecoder {
-	cfg := d.cfg
+	b, err := encodeFieldDefault(d.cfg, field)
+	if err != nil {
+		return &errorDecoder{err: fmt.Errorf("decode default: %w", err)}
+	}
+	return &defaultDecoder{
+		data:    b,
+		decoder: decoderOfType(d, field.Type(), typ),
+	}
+}
+
+// encodeFieldDefault returns the Avro encoding of the default value of the field.
+func encodeFieldDefault(cfg *frozenConfig, field *Field) ([]byte, error) {
 	fn := func(def any) ([]byte, error) {
 		defaultType := reflect2.TypeOf(def)
 		if defaultType == nil {
@@ -32,14 +43,7 @@ func createDefaultDecoder(d *decoderContext, field *Field, typ reflect2.Type) Va
 		return data, nil
 	}
 
-	b, err := field.encodeDefault(fn)
-	if err != nil {
-		return &errorDecoder{err: fmt.Errorf("decode default: %w", err)}
-	}
-	return &defaultDecoder{
-		data:    b,
-		decoder: decoderOfType(d, field.Type(), typ),
-	}
+	return field.encodeDefault(fn)
 }
 
 type defaultDecoder struct {
@@ -155,37 +155,43 @@ func (c *fixedDecimalCodec) Encode(ptr unsafe.Pointer, w *Writer) {
 		return
 	}
 
+	b := fixedDecimalBytes(i, c.size)
+	if len(b) != c.size {
+		w.Error = fmt.Errorf(
+			"avro: cannot encode %v as Avro fixed.decimal with size=%d, encodes to %d bytes",
+			r.FloatString(c.scale),
+			c.size,
+			len(b),
+		)
+		return
+	}
+
+	_, _ = w.Write(b)
+}
+
+// fixedDecimalBytes returns the big-endian two's complement encoding of the unscaled
+// decimal value i, padded to size. The encoding is longer than size if i does not fit.
+func fixedDecimalBytes(i *big.Int, size int) []byte {
 	var b []byte
 	switch i.Sign() {
 	case 0:
-		b = make([]byte, c.size)
+		b = make([]byte, size)
 
 	case 1:
 		b = i.Bytes()
 		if b[0]&0x80 > 0 {
 			b = append([]byte{0}, b...)
 		}
-		if len(b) < c.size {
-			padded := make([]byte, c.size)
-			copy(padded[c.size-len(b):], b)
+		if len(b) < size {
+			padded := make([]byte, size)
+			copy(padded[size-len(b):], b)
 			b = padded
 		}
 
 	case -1:
-		b = i.Add(i, (&big.Int{}).Lsh(one, uint(c.size*8))).Bytes()
+		b = (&big.Int{}).Add(i, (&big.Int{}).Lsh(one, uint(size*8))).Bytes()
 	}
-
-	if len(b) != c.size {
-		w.Error = fmt.Errorf(
-			"avro: cannot encode %v as Avro fixed.decimal with size=%d, encodes to %d bytes",
-			r.FloatString(c.scale),
-			c.size,
-			len(b),
-		)
-		return
-	}
-
-	_, _ = w.Write(b)
+	return b
 }
 
 type fixedDurationCodec struct{}
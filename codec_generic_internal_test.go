@@ -234,7 +234,7 @@ func TestGenericDecode(t *testing.T) {
 
 			typ, err := genericReceiver(schema)
 			require.NoError(t, err)
-			dec := decoderOfType(newDecoderContext(DefaultConfig.(*frozenConfig)), schema, typ)
+			dec := decoderOfType(newDecoderContext(mustFrozenConfig(DefaultConfig)), schema, typ)
 
 			got := genericDecode(typ, dec, r)
 
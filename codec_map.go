@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"slices"
+	"strings"
 	"unsafe"
 
 	"github.com/modern-go/reflect2"
@@ -149,6 +151,7 @@ func encoderOfMap(e *encoderContext, m *MapSchema, typ reflect2.Type) ValEncoder
 
 	return &mapEncoder{
 		blockLength: e.cfg.getBlockLength(),
+		sortKeys:    e.cfg.config.SortMapKeys,
 		mapType:     mapType,
 		encoder:     encoder,
 	}
@@ -156,6 +159,7 @@ func encoderOfMap(e *encoderContext, m *MapSchema, typ reflect2.Type) ValEncoder
 
 type mapEncoder struct {
 	blockLength int
+	sortKeys    bool
 	mapType     *reflect2.UnsafeMapType
 	encoder     ValEncoder
 }
@@ -165,6 +169,20 @@ func (e *mapEncoder) Encode(ptr unsafe.Pointer, w *Writer) {
 
 	iter := e.mapType.UnsafeIterate(ptr)
 
+	if e.sortKeys {
+		var entries []sortedMapEntry
+		for iter.HasNext() {
+			keyPtr, elemPtr := iter.UnsafeNext()
+			entries = append(entries, sortedMapEntry{key: *((*string)(keyPtr)), elem: elemPtr})
+		}
+		writeSortedMapEntries(w, entries, blockLength, e.encoder)
+
+		if w.Error != nil && !errors.Is(w.Error, io.EOF) {
+			w.Error = fmt.Errorf("%v: %w", e.mapType, w.Error)
+		}
+		return
+	}
+
 	for {
 		wrote := w.WriteBlockCB(func(w *Writer) int64 {
 			var i int
@@ -193,6 +211,7 @@ func encoderOfMapMarshaler(e *encoderContext, m *MapSchema, typ reflect2.Type) V
 
 	return &mapEncoderMarshaller{
 		blockLength: e.cfg.getBlockLength(),
+		sortKeys:    e.cfg.config.SortMapKeys,
 		mapType:     mapType,
 		keyType:     mapType.Key(),
 		encoder:     encoder,
@@ -201,6 +220,7 @@ func encoderOfMapMarshaler(e *encoderContext, m *MapSchema, typ reflect2.Type) V
 
 type mapEncoderMarshaller struct {
 	blockLength int
+	sortKeys    bool
 	mapType     *reflect2.UnsafeMapType
 	keyType     reflect2.Type
 	encoder     ValEncoder
@@ -211,24 +231,37 @@ func (e *mapEncoderMarshaller) Encode(ptr unsafe.Pointer, w *Writer) {
 
 	iter := e.mapType.UnsafeIterate(ptr)
 
+	if e.sortKeys {
+		var entries []sortedMapEntry
+		for iter.HasNext() {
+			keyPtr, elemPtr := iter.UnsafeNext()
+			key, err := e.marshalKey(keyPtr)
+			if err != nil {
+				w.Error = err
+				return
+			}
+			entries = append(entries, sortedMapEntry{key: key, elem: elemPtr})
+		}
+		writeSortedMapEntries(w, entries, blockLength, e.encoder)
+
+		if w.Error != nil && !errors.Is(w.Error, io.EOF) {
+			w.Error = fmt.Errorf("%v: %w", e.mapType, w.Error)
+		}
+		return
+	}
+
 	for {
 		wrote := w.WriteBlockCB(func(w *Writer) int64 {
 			var i int
 			for i = 0; iter.HasNext() && i < blockLength; i++ {
 				keyPtr, elemPtr := iter.UnsafeNext()
 
-				obj := e.keyType.UnsafeIndirect(keyPtr)
-				if e.keyType.IsNullable() && reflect2.IsNil(obj) {
-					w.Error = errors.New("avro: mapEncoderMarshaller: encoding nil TextMarshaller")
-					return int64(0)
-				}
-				marshaler := (obj).(encoding.TextMarshaler)
-				b, err := marshaler.MarshalText()
+				key, err := e.marshalKey(keyPtr)
 				if err != nil {
 					w.Error = err
 					return int64(0)
 				}
-				w.WriteString(string(b))
+				w.WriteString(key)
 
 				e.encoder.Encode(elemPtr, w)
 			}
@@ -244,3 +277,45 @@ func (e *mapEncoderMarshaller) Encode(ptr unsafe.Pointer, w *Writer) {
 		w.Error = fmt.Errorf("%v: %w", e.mapType, w.Error)
 	}
 }
+
+func (e *mapEncoderMarshaller) marshalKey(keyPtr unsafe.Pointer) (string, error) {
+	obj := e.keyType.UnsafeIndirect(keyPtr)
+	if e.keyType.IsNullable() && reflect2.IsNil(obj) {
+		return "", errors.New("avro: mapEncoderMarshaller: encoding nil TextMarshaller")
+	}
+	marshaler := (obj).(encoding.TextMarshaler)
+	b, err := marshaler.MarshalText()
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+type sortedMapEntry struct {
+	key  string
+	elem unsafe.Pointer
+}
+
+// writeSortedMapEntries writes the map entries in key order, in blocks of at most blockLength entries.
+func writeSortedMapEntries(w *Writer, entries []sortedMapEntry, blockLength int, encoder ValEncoder) {
+	slices.SortFunc(entries, func(a, b sortedMapEntry) int {
+		return strings.Compare(a.key, b.key)
+	})
+
+	for {
+		wrote := w.WriteBlockCB(func(w *Writer) int64 {
+			n := min(blockLength, len(entries))
+			for _, entry := range entries[:n] {
+				w.WriteString(entry.key)
+				encoder.Encode(entry.elem, w)
+			}
+			entries = entries[n:]
+
+			return int64(n)
+		})
+
+		if wrote == 0 {
+			break
+		}
+	}
+}
@@ -2,16 +2,22 @@ package avro
 
 import (
 	"encoding"
+	"encoding/hex"
+	"fmt"
+	"reflect"
 	"unsafe"
 
 	"github.com/modern-go/reflect2"
 )
 
 var (
-	textMarshalerType   = reflect2.TypeOfPtr((*encoding.TextMarshaler)(nil)).Elem()
-	textUnmarshalerType = reflect2.TypeOfPtr((*encoding.TextUnmarshaler)(nil)).Elem()
-	avroMarshalerType   = reflect2.TypeOfPtr((*RecordMarshaler)(nil)).Elem()
-	avroUnmarshalerType = reflect2.TypeOfPtr((*RecordUnmarshaler)(nil)).Elem()
+	textMarshalerType     = reflect2.TypeOfPtr((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType   = reflect2.TypeOfPtr((*encoding.TextUnmarshaler)(nil)).Elem()
+	avroMarshalerType     = reflect2.TypeOfPtr((*RecordMarshaler)(nil)).Elem()
+	avroUnmarshalerType   = reflect2.TypeOfPtr((*RecordUnmarshaler)(nil)).Elem()
+	schemaMarshalerType   = reflect2.TypeOfPtr((*SchemaMarshaler)(nil)).Elem()
+	schemaUnmarshalerType = reflect2.TypeOfPtr((*SchemaUnmarshaler)(nil)).Elem()
+	uuidMarshalerType     = reflect2.TypeOfPtr((*UUIDMarshaler)(nil)).Elem()
 )
 
 func createDecoderOfMarshaler(schema Schema, typ reflect2.Type) ValDecoder {
@@ -24,6 +30,12 @@ func createDecoderOfMarshaler(schema Schema, typ reflect2.Type) ValDecoder {
 			&textMarshalerCodec{ptrType},
 		}
 	}
+	if isUUIDSchema(schema) && isUUIDArray(typ) {
+		return &uuidCodec{}
+	}
+	if isAvroMarshalerSchema(schema) {
+		return createDecoderOfAvroMarshaler(schema, typ)
+	}
 	return nil
 }
 
@@ -33,9 +45,33 @@ func createEncoderOfMarshaler(schema Schema, typ reflect2.Type) ValEncoder {
 			typ: typ,
 		}
 	}
+	if isUUIDSchema(schema) {
+		if typ.Implements(uuidMarshalerType) {
+			return &uuidCodec{typ: typ}
+		}
+		if isUUIDArray(typ) {
+			return &uuidCodec{}
+		}
+	}
+	if isAvroMarshalerSchema(schema) {
+		return createEncoderOfAvroMarshaler(schema, typ)
+	}
 	return nil
 }
 
+// isAvroMarshalerSchema determines if the Avro marshaler interfaces are checked
+// before creating the codec of the schema. Records check them once the type is
+// known not to be a protobuf message, and unions and references check them on the
+// schema they resolve to.
+func isAvroMarshalerSchema(schema Schema) bool {
+	switch schema.Type() {
+	case Record, Union, Ref:
+		return false
+	default:
+		return true
+	}
+}
+
 type textMarshalerCodec struct {
 	typ reflect2.Type
 }
@@ -50,6 +86,10 @@ func (c textMarshalerCodec) Decode(ptr unsafe.Pointer, r *Reader) {
 	}
 	unmarshaler := (obj).(encoding.TextUnmarshaler)
 	b := r.ReadBytes()
+	if r.Error != nil {
+		// The text was not read, e.g. as its length exceeds Config.MaxByteSliceSize.
+		return
+	}
 	err := unmarshaler.UnmarshalText(b)
 	if err != nil {
 		r.ReportError("textMarshalerCodec", err.Error())
@@ -71,6 +111,86 @@ func (c textMarshalerCodec) Encode(ptr unsafe.Pointer, w *Writer) {
 	w.WriteBytes(b)
 }
 
+// UUIDMarshaler is the interface implemented by types that can be encoded
+// as a string with the uuid logical type.
+type UUIDMarshaler interface {
+	UUID() [16]byte
+}
+
+func isUUIDSchema(schema Schema) bool {
+	s, ok := schema.(*PrimitiveSchema)
+	return ok && s.Type() == String && s.Logical() != nil && s.Logical().Type() == UUID
+}
+
+func isUUIDArray(typ reflect2.Type) bool {
+	arrTyp, ok := typ.(reflect2.ArrayType)
+	return ok && arrTyp.Len() == 16 && arrTyp.Elem().Kind() == reflect.Uint8
+}
+
+// uuidCodec encodes [16]byte arrays, or types implementing UUIDMarshaler when typ is set,
+// as uuid strings in the 8-4-4-4-12 hex format.
+type uuidCodec struct {
+	typ reflect2.Type
+}
+
+func (c *uuidCodec) Decode(ptr unsafe.Pointer, r *Reader) {
+	s := r.ReadString()
+	if r.Error != nil {
+		return
+	}
+	id, err := parseUUID(s)
+	if err != nil {
+		r.ReportError("uuidCodec", err.Error())
+		return
+	}
+	*(*[16]byte)(ptr) = id
+}
+
+func (c *uuidCodec) Encode(ptr unsafe.Pointer, w *Writer) {
+	var id [16]byte
+	if c.typ != nil {
+		obj := c.typ.UnsafeIndirect(ptr)
+		if c.typ.IsNullable() && reflect2.IsNil(obj) {
+			w.Error = fmt.Errorf("avro: cannot encode nil %s as uuid", c.typ)
+			return
+		}
+		id = obj.(UUIDMarshaler).UUID()
+	} else {
+		id = *(*[16]byte)(ptr)
+	}
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], id[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], id[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], id[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], id[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], id[10:])
+	w.WriteBytes(buf[:])
+}
+
+// parseUUID parses a uuid in the 8-4-4-4-12 hex format.
+func parseUUID(s string) ([16]byte, error) {
+	var id [16]byte
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return id, fmt.Errorf("invalid uuid %q", s)
+	}
+
+	var b [32]byte
+	n := copy(b[:], s[0:8])
+	n += copy(b[n:], s[9:13])
+	n += copy(b[n:], s[14:18])
+	n += copy(b[n:], s[19:23])
+	copy(b[n:], s[24:])
+	if _, err := hex.Decode(id[:], b[:]); err != nil {
+		return id, fmt.Errorf("invalid uuid %q", s)
+	}
+	return id, nil
+}
+
 // RecordMarshaler is the interface implemented by types that can marshal themselves to Avro.
 type RecordMarshaler interface {
 	MarshalAvro(w *Writer) error
@@ -82,15 +202,31 @@ type RecordUnmarshaler interface {
 	UnmarshalAvro(r *Reader) error
 }
 
+// SchemaMarshaler is the interface implemented by types that can marshal themselves
+// to Avro using the schema they are written with. It takes precedence over RecordMarshaler.
+type SchemaMarshaler interface {
+	MarshalAvroSchema(w *Writer, s Schema) error
+}
+
+// SchemaUnmarshaler is the interface implemented by types that can unmarshal an Avro
+// description of themselves using the schema they are read with. It takes precedence
+// over RecordUnmarshaler.
+type SchemaUnmarshaler interface {
+	UnmarshalAvroSchema(r *Reader, s Schema) error
+}
+
 func createDecoderOfAvroMarshaler(schema Schema, typ reflect2.Type) ValDecoder {
-	if schema.Type() != Record {
-		return nil
-	}
-	if typ.Implements(avroUnmarshalerType) {
-		return &avroMarshalerCodec{typ: typ}
-	}
 	ptrType := reflect2.PtrTo(typ)
-	if ptrType.Implements(avroUnmarshalerType) {
+	switch {
+	case typ.Implements(schemaUnmarshalerType):
+		return &avroMarshalerCodec{typ: typ, schema: schema}
+	case ptrType.Implements(schemaUnmarshalerType):
+		return &referenceDecoder{
+			&avroMarshalerCodec{typ: ptrType, schema: schema},
+		}
+	case typ.Implements(avroUnmarshalerType):
+		return &avroMarshalerCodec{typ: typ}
+	case ptrType.Implements(avroUnmarshalerType):
 		return &referenceDecoder{
 			&avroMarshalerCodec{typ: ptrType},
 		}
@@ -99,21 +235,24 @@ func createDecoderOfAvroMarshaler(schema Schema, typ reflect2.Type) ValDecoder {
 }
 
 func createEncoderOfAvroMarshaler(schema Schema, typ reflect2.Type) ValEncoder {
-	if schema.Type() != Record {
-		return nil
-	}
-	if typ.Implements(avroMarshalerType) {
-		return &avroMarshalerCodec{typ: typ}
-	}
 	ptrType := reflect2.PtrTo(typ)
-	if ptrType.Implements(avroMarshalerType) {
+	switch {
+	case typ.Implements(schemaMarshalerType):
+		return &avroMarshalerCodec{typ: typ, schema: schema}
+	case ptrType.Implements(schemaMarshalerType):
+		return &avroMarshalerPtrCodec{typ: ptrType, elemTyp: typ, schema: schema}
+	case typ.Implements(avroMarshalerType):
+		return &avroMarshalerCodec{typ: typ}
+	case ptrType.Implements(avroMarshalerType):
 		return &avroMarshalerPtrCodec{typ: ptrType, elemTyp: typ}
 	}
 	return nil
 }
 
+// avroMarshalerCodec uses the schema interfaces when schema is set.
 type avroMarshalerCodec struct {
-	typ reflect2.Type
+	typ    reflect2.Type
+	schema Schema
 }
 
 func (c *avroMarshalerCodec) Decode(ptr unsafe.Pointer, r *Reader) {
@@ -124,8 +263,12 @@ func (c *avroMarshalerCodec) Decode(ptr unsafe.Pointer, r *Reader) {
 		*((*unsafe.Pointer)(ptr)) = newPtr
 		obj = c.typ.UnsafeIndirect(ptr)
 	}
-	unmarshaler := (obj).(RecordUnmarshaler)
-	err := unmarshaler.UnmarshalAvro(r)
+	var err error
+	if c.schema != nil {
+		err = (obj).(SchemaUnmarshaler).UnmarshalAvroSchema(r, c.schema)
+	} else {
+		err = (obj).(RecordUnmarshaler).UnmarshalAvro(r)
+	}
 	if err != nil {
 		r.ReportError("avroMarshalerCodec", err.Error())
 	}
@@ -137,8 +280,12 @@ func (c *avroMarshalerCodec) Encode(ptr unsafe.Pointer, w *Writer) {
 		w.Error = nil
 		return
 	}
-	marshaler := (obj).(RecordMarshaler)
-	err := marshaler.MarshalAvro(w)
+	var err error
+	if c.schema != nil {
+		err = (obj).(SchemaMarshaler).MarshalAvroSchema(w, c.schema)
+	} else {
+		err = (obj).(RecordMarshaler).MarshalAvro(w)
+	}
 	if err != nil {
 		w.Error = err
 	}
@@ -148,13 +295,19 @@ func (c *avroMarshalerCodec) Encode(ptr unsafe.Pointer, w *Writer) {
 type avroMarshalerPtrCodec struct {
 	typ     reflect2.Type // pointer type that implements AvroMarshaler
 	elemTyp reflect2.Type // element type (the actual struct)
+	schema  Schema        // set when the pointer type implements SchemaMarshaler
 }
 
 func (c *avroMarshalerPtrCodec) Encode(ptr unsafe.Pointer, w *Writer) {
 	// ptr points to the struct value, we need to pass the pointer (ptr itself)
 	// to the marshaler since it expects a pointer receiver
-	marshaler := c.typ.UnsafeIndirect(unsafe.Pointer(&ptr)).(RecordMarshaler)
-	err := marshaler.MarshalAvro(w)
+	obj := c.typ.UnsafeIndirect(unsafe.Pointer(&ptr))
+	var err error
+	if c.schema != nil {
+		err = obj.(SchemaMarshaler).MarshalAvroSchema(w, c.schema)
+	} else {
+		err = obj.(RecordMarshaler).MarshalAvro(w)
+	}
 	if err != nil {
 		w.Error = err
 	}
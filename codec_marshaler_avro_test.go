@@ -159,6 +159,27 @@ func TestAvroMarshaler_ErrorHandling(t *testing.T) {
 	})
 }
 
+func TestAvroMarshaler_MarshalTo(t *testing.T) {
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "CustomRecord",
+		"fields": [
+			{"name": "name", "type": "string"},
+			{"name": "value", "type": "int"}
+		]
+	}`)
+
+	want, err := avro.Marshal(schema, CustomRecord{Name: "test", Value: 42})
+	require.NoError(t, err)
+
+	got, err := avro.MarshalTo(schema, CustomRecord{Name: "test", Value: 42}, make([]byte, 0, 64))
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	_, err = avro.MarshalTo(avro.MustParse(`{"type": "record", "name": "ErrorMarshaler", "fields": [{"name": "value", "type": "int"}]}`), ErrorMarshaler{Value: -1}, nil)
+	assert.ErrorContains(t, err, "marshaling error")
+}
+
 func TestAvroMarshaler_NilPointer(t *testing.T) {
 	schema := `{
 		"type": "record",
@@ -217,17 +238,16 @@ func TestAvroMarshaler_RoundTrip(t *testing.T) {
 	assert.Equal(t, data, data2)
 }
 
-func TestAvroMarshaler_OnlyWorksWithRecords(t *testing.T) {
-	// Test that AvroMarshaler is only used for record schemas, not primitives
+func TestAvroMarshaler_NotImplementedUsesNativeCodec(t *testing.T) {
+	// Test that types not implementing AvroMarshaler use the standard codecs
 
-	// Define a custom type that implements AvroMarshaler
+	// Define a custom type that does not implement AvroMarshaler
 	type CustomString string
 
-	// This won't use MarshalAvro because it's not a record schema
 	schema := `"string"`
 	value := CustomString("test")
 
-	// Should work but won't use custom marshaler
+	// Should work without a custom marshaler
 	data, err := avro.Marshal(avro.MustParse(schema), value)
 	require.NoError(t, err)
 	assert.NotEmpty(t, data)
@@ -721,3 +741,561 @@ func TestNullableFieldsCustomMarshaling(t *testing.T) {
 		assert.Nil(t, decoded.Phone)
 	})
 }
+
+// Contact with nullable fields using the nullable union helpers
+type Contact struct {
+	Name  string
+	Email *string // nullable
+}
+
+func (c Contact) MarshalAvro(w *avro.Writer) error {
+	w.WriteString(c.Name)
+
+	w.WriteNullableIndex(c.Email == nil)
+	if c.Email != nil {
+		w.WriteString(*c.Email)
+	}
+
+	return nil
+}
+
+func (c *Contact) UnmarshalAvro(r *avro.Reader) error {
+	c.Name = r.ReadString()
+
+	c.Email = nil
+	if !r.ReadNullableIndex() {
+		email := r.ReadString()
+		c.Email = &email
+	}
+
+	return r.Error
+}
+
+// TestNullableIndexHelpersCustomMarshaling tests custom marshaling with the nullable union helpers
+func TestNullableIndexHelpersCustomMarshaling(t *testing.T) {
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "Contact",
+		"fields": [
+			{"name": "name", "type": "string"},
+			{"name": "email", "type": ["null", "string"], "default": null}
+		]
+	}`)
+
+	t.Run("value", func(t *testing.T) {
+		email := "user@example.com"
+		contact := Contact{Name: "John", Email: &email}
+
+		data, err := avro.Marshal(schema, contact)
+		require.NoError(t, err)
+		assert.Equal(t, []byte{0x08, 'J', 'o', 'h', 'n', 0x02, 0x20}, data[:7])
+
+		var decoded Contact
+		err = avro.Unmarshal(schema, data, &decoded)
+		require.NoError(t, err)
+
+		assert.Equal(t, contact.Name, decoded.Name)
+		require.NotNil(t, decoded.Email)
+		assert.Equal(t, email, *decoded.Email)
+	})
+
+	t.Run("null", func(t *testing.T) {
+		contact := Contact{Name: "John"}
+
+		data, err := avro.Marshal(schema, contact)
+		require.NoError(t, err)
+		assert.Equal(t, []byte{0x08, 'J', 'o', 'h', 'n', 0x00}, data)
+
+		var decoded Contact
+		err = avro.Unmarshal(schema, data, &decoded)
+		require.NoError(t, err)
+
+		assert.Equal(t, contact.Name, decoded.Name)
+		assert.Nil(t, decoded.Email)
+	})
+
+	t.Run("compatible with generic decoding", func(t *testing.T) {
+		email := "user@example.com"
+		data, err := avro.Marshal(schema, Contact{Name: "John", Email: &email})
+		require.NoError(t, err)
+
+		var decoded map[string]any
+		err = avro.Unmarshal(schema, data, &decoded)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{"name": "John", "email": "user@example.com"}, decoded)
+	})
+}
+
+// Memo writes both branches of its nullable note explicitly
+type Memo struct {
+	Note *string // nullable
+}
+
+func (m Memo) MarshalAvro(w *avro.Writer) error {
+	if m.Note == nil {
+		w.WriteNullableIndex(true)
+		w.WriteNull()
+		return nil
+	}
+	w.WriteNullableIndex(false)
+	w.WriteString(*m.Note)
+	return nil
+}
+
+func (m *Memo) UnmarshalAvro(r *avro.Reader) error {
+	if r.ReadNullableIndex() {
+		r.ReadNull()
+		m.Note = nil
+		return r.Error
+	}
+	note := r.ReadString()
+	m.Note = &note
+	return r.Error
+}
+
+func TestNullCustomMarshaling(t *testing.T) {
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "Memo",
+		"fields": [
+			{"name": "note", "type": ["null", "string"]}
+		]
+	}`)
+
+	t.Run("null", func(t *testing.T) {
+		data, err := avro.Marshal(schema, Memo{})
+		require.NoError(t, err)
+		assert.Equal(t, []byte{0x00}, data)
+
+		note := "stale"
+		decoded := Memo{Note: &note}
+		err = avro.Unmarshal(schema, data, &decoded)
+		require.NoError(t, err)
+		assert.Nil(t, decoded.Note)
+	})
+
+	t.Run("value", func(t *testing.T) {
+		note := "hi"
+		data, err := avro.Marshal(schema, Memo{Note: &note})
+		require.NoError(t, err)
+		assert.Equal(t, []byte{0x02, 0x04, 'h', 'i'}, data)
+
+		var decoded Memo
+		err = avro.Unmarshal(schema, data, &decoded)
+		require.NoError(t, err)
+		require.NotNil(t, decoded.Note)
+		assert.Equal(t, note, *decoded.Note)
+	})
+}
+
+// Tagged has array and map fields written with the block helpers
+type Tagged struct {
+	Tags   []string
+	Counts map[string]int
+}
+
+func (t Tagged) MarshalAvro(w *avro.Writer) error {
+	w.WriteArrayStart(len(t.Tags))
+	for _, tag := range t.Tags {
+		w.WriteString(tag)
+	}
+	w.WriteArrayEnd()
+
+	w.WriteMapStart(len(t.Counts))
+	for k, v := range t.Counts {
+		w.WriteString(k)
+		w.WriteInt(int32(v))
+	}
+	w.WriteMapEnd()
+
+	return nil
+}
+
+func (t *Tagged) UnmarshalAvro(r *avro.Reader) error {
+	t.Tags = nil
+	err := r.ReadArrayCallback(func(r *avro.Reader) error {
+		t.Tags = append(t.Tags, r.ReadString())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	t.Counts = map[string]int{}
+	return r.ReadMapCallback(func(r *avro.Reader, key string) error {
+		t.Counts[key] = int(r.ReadInt())
+		return nil
+	})
+}
+
+// TestBlockHelpersCustomMarshaling tests custom marshaling with the array and map block helpers
+func TestBlockHelpersCustomMarshaling(t *testing.T) {
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "Tagged",
+		"fields": [
+			{"name": "tags", "type": {"type": "array", "items": "string"}},
+			{"name": "counts", "type": {"type": "map", "values": "int"}}
+		]
+	}`)
+
+	t.Run("round trip", func(t *testing.T) {
+		tagged := Tagged{Tags: []string{"a", "b"}, Counts: map[string]int{"x": 1, "y": 2}}
+
+		data, err := avro.Marshal(schema, tagged)
+		require.NoError(t, err)
+
+		var decoded Tagged
+		err = avro.Unmarshal(schema, data, &decoded)
+		require.NoError(t, err)
+
+		assert.Equal(t, tagged, decoded)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		data, err := avro.Marshal(schema, Tagged{})
+		require.NoError(t, err)
+		assert.Equal(t, []byte{0x00, 0x00}, data)
+	})
+
+	t.Run("compatible with generic decoding", func(t *testing.T) {
+		data, err := avro.Marshal(schema, Tagged{Tags: []string{"a"}, Counts: map[string]int{"x": 1}})
+		require.NoError(t, err)
+
+		var decoded map[string]any
+		err = avro.Unmarshal(schema, data, &decoded)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{"tags": []any{"a"}, "counts": map[string]any{"x": 1}}, decoded)
+	})
+
+	t.Run("blocks with sizes", func(t *testing.T) {
+		api := avro.Config{BlockLength: 1}.Freeze()
+		data, err := api.Marshal(schema, map[string]any{
+			"tags":   []any{"a", "b"},
+			"counts": map[string]any{"x": 1},
+		})
+		require.NoError(t, err)
+
+		var decoded Tagged
+		err = avro.Unmarshal(schema, data, &decoded)
+		require.NoError(t, err)
+
+		assert.Equal(t, Tagged{Tags: []string{"a", "b"}, Counts: map[string]int{"x": 1}}, decoded)
+	})
+}
+
+// Point adapts to the field order of the schema it is written with
+type Point struct {
+	X int32
+	Y int32
+}
+
+func (p Point) MarshalAvroSchema(w *avro.Writer, s avro.Schema) error {
+	for _, f := range s.(*avro.RecordSchema).Fields() {
+		switch f.Name() {
+		case "x":
+			w.WriteInt(p.X)
+		case "y":
+			w.WriteInt(p.Y)
+		default:
+			return errors.New("unknown field " + f.Name())
+		}
+	}
+	return nil
+}
+
+func (p *Point) UnmarshalAvroSchema(r *avro.Reader, s avro.Schema) error {
+	for _, f := range s.(*avro.RecordSchema).Fields() {
+		switch f.Name() {
+		case "x":
+			p.X = r.ReadInt()
+		case "y":
+			p.Y = r.ReadInt()
+		default:
+			return errors.New("unknown field " + f.Name())
+		}
+	}
+	return nil
+}
+
+// Also implements RecordMarshaler with a fixed x, y order, which must not be used
+func (p Point) MarshalAvro(w *avro.Writer) error {
+	w.WriteInt(p.X)
+	w.WriteInt(p.Y)
+	return nil
+}
+
+func (p *Point) UnmarshalAvro(r *avro.Reader) error {
+	p.X = r.ReadInt()
+	p.Y = r.ReadInt()
+	return nil
+}
+
+// PointPtr implements the schema interfaces with pointer receivers
+type PointPtr struct {
+	X int32
+	Y int32
+}
+
+func (p *PointPtr) MarshalAvroSchema(w *avro.Writer, s avro.Schema) error {
+	return Point(*p).MarshalAvroSchema(w, s)
+}
+
+func (p *PointPtr) UnmarshalAvroSchema(r *avro.Reader, s avro.Schema) error {
+	return (*Point)(p).UnmarshalAvroSchema(r, s)
+}
+
+func TestSchemaMarshaler_FieldOrder(t *testing.T) {
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "Point",
+		"fields": [
+			{"name": "y", "type": "int"},
+			{"name": "x", "type": "int"}
+		]
+	}`)
+
+	data, err := avro.Marshal(schema, Point{X: 1, Y: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x04, 0x02}, data)
+
+	var got Point
+	err = avro.Unmarshal(schema, data, &got)
+	require.NoError(t, err)
+	assert.Equal(t, Point{X: 1, Y: 2}, got)
+}
+
+func TestSchemaMarshaler_PointerReceiver(t *testing.T) {
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "Point",
+		"fields": [
+			{"name": "y", "type": "int"},
+			{"name": "x", "type": "int"}
+		]
+	}`)
+
+	data, err := avro.Marshal(schema, PointPtr{X: 1, Y: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x04, 0x02}, data)
+
+	var got PointPtr
+	err = avro.Unmarshal(schema, data, &got)
+	require.NoError(t, err)
+	assert.Equal(t, PointPtr{X: 1, Y: 2}, got)
+}
+
+func TestSchemaMarshaler_NestedField(t *testing.T) {
+	type Shape struct {
+		Name   string `avro:"name"`
+		Center Point  `avro:"center"`
+	}
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "Shape",
+		"fields": [
+			{"name": "name", "type": "string"},
+			{
+				"name": "center",
+				"type": {
+					"type": "record",
+					"name": "Point",
+					"fields": [
+						{"name": "y", "type": "int"},
+						{"name": "x", "type": "int"}
+					]
+				}
+			}
+		]
+	}`)
+
+	data, err := avro.Marshal(schema, Shape{Name: "a", Center: Point{X: 1, Y: 2}})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x02, 0x61, 0x04, 0x02}, data)
+
+	var got Shape
+	err = avro.Unmarshal(schema, data, &got)
+	require.NoError(t, err)
+	assert.Equal(t, Shape{Name: "a", Center: Point{X: 1, Y: 2}}, got)
+}
+
+// Celsius is stored in Avro as a double in Kelvin
+type Celsius struct {
+	Degrees float64
+}
+
+func (c Celsius) MarshalAvro(w *avro.Writer) error {
+	w.WriteDouble(c.Degrees + 273.15)
+	return nil
+}
+
+func (c *Celsius) UnmarshalAvro(r *avro.Reader) error {
+	c.Degrees = r.ReadDouble() - 273.15
+	return nil
+}
+
+func TestAvroMarshaler_Primitive(t *testing.T) {
+	schema := avro.MustParse(`"double"`)
+
+	data, err := avro.Marshal(schema, Celsius{Degrees: 26.85})
+	require.NoError(t, err)
+
+	var kelvin float64
+	err = avro.Unmarshal(schema, data, &kelvin)
+	require.NoError(t, err)
+	assert.InDelta(t, 300.0, kelvin, 1e-9)
+
+	var got Celsius
+	err = avro.Unmarshal(schema, data, &got)
+	require.NoError(t, err)
+	assert.InDelta(t, 26.85, got.Degrees, 1e-9)
+}
+
+func TestAvroMarshaler_ArrayItem(t *testing.T) {
+	schema := avro.MustParse(`{"type": "array", "items": "double"}`)
+	temps := []Celsius{{Degrees: 0}, {Degrees: 100}}
+
+	data, err := avro.Marshal(schema, temps)
+	require.NoError(t, err)
+
+	var kelvin []float64
+	err = avro.Unmarshal(schema, data, &kelvin)
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{273.15, 373.15}, kelvin, 1e-9)
+
+	var got []Celsius
+	err = avro.Unmarshal(schema, data, &got)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.InDelta(t, 0.0, got[0].Degrees, 1e-9)
+	assert.InDelta(t, 100.0, got[1].Degrees, 1e-9)
+}
+
+func TestAvroMarshaler_UnionBranch(t *testing.T) {
+	type Reading struct {
+		Sensor string   `avro:"sensor"`
+		Temp   *Celsius `avro:"temp"`
+	}
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "Reading",
+		"fields": [
+			{"name": "sensor", "type": "string"},
+			{"name": "temp", "type": ["null", "double"]}
+		]
+	}`)
+
+	t.Run("value", func(t *testing.T) {
+		data, err := avro.Marshal(schema, Reading{Sensor: "a", Temp: &Celsius{Degrees: 100}})
+		require.NoError(t, err)
+
+		var generic map[string]any
+		err = avro.Unmarshal(schema, data, &generic)
+		require.NoError(t, err)
+		assert.InDelta(t, 373.15, generic["temp"], 1e-9)
+
+		var got Reading
+		err = avro.Unmarshal(schema, data, &got)
+		require.NoError(t, err)
+		assert.Equal(t, "a", got.Sensor)
+		require.NotNil(t, got.Temp)
+		assert.InDelta(t, 100.0, got.Temp.Degrees, 1e-9)
+	})
+
+	t.Run("null", func(t *testing.T) {
+		data, err := avro.Marshal(schema, Reading{Sensor: "a"})
+		require.NoError(t, err)
+		assert.Equal(t, []byte{0x02, 0x61, 0x00}, data)
+
+		var got Reading
+		err = avro.Unmarshal(schema, data, &got)
+		require.NoError(t, err)
+		assert.Nil(t, got.Temp)
+	})
+}
+
+// Customer is encoded by the native struct codec.
+type Customer struct {
+	ID   int64  `avro:"id"`
+	Name string `avro:"name"`
+}
+
+// Order has a custom marshaler that delegates its customer field to the native codec.
+type Order struct {
+	Number   string
+	Customer Customer
+}
+
+func (o Order) MarshalAvroSchema(w *avro.Writer, s avro.Schema) error {
+	for _, f := range s.(*avro.RecordSchema).Fields() {
+		switch f.Name() {
+		case "number":
+			w.WriteString("#" + o.Number)
+		case "customer":
+			w.WriteVal(f.Type(), o.Customer)
+		}
+	}
+	return w.Error
+}
+
+func (o *Order) UnmarshalAvroSchema(r *avro.Reader, s avro.Schema) error {
+	for _, f := range s.(*avro.RecordSchema).Fields() {
+		switch f.Name() {
+		case "number":
+			o.Number = r.ReadString()[1:]
+		case "customer":
+			r.ReadVal(f.Type(), &o.Customer)
+		}
+	}
+	return r.Error
+}
+
+func TestCustomMarshaling_DelegatesToNativeCodec(t *testing.T) {
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "Order",
+		"fields": [
+			{"name": "number", "type": "string"},
+			{
+				"name": "customer",
+				"type": {
+					"type": "record",
+					"name": "Customer",
+					"fields": [
+						{"name": "id", "type": "long"},
+						{"name": "name", "type": "string"}
+					]
+				}
+			}
+		]
+	}`)
+	original := Order{Number: "42", Customer: Customer{ID: 7, Name: "Jane"}}
+
+	data, err := avro.Marshal(schema, original)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x06, '#', '4', '2', 0x0e, 0x08, 'J', 'a', 'n', 'e'}, data)
+
+	var got Order
+	err = avro.Unmarshal(schema, data, &got)
+	require.NoError(t, err)
+	assert.Equal(t, original, got)
+}
+
+func TestCustomMarshaling_DelegatedError(t *testing.T) {
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "Order",
+		"fields": [
+			{"name": "number", "type": "string"},
+			{"name": "customer", "type": "string"}
+		]
+	}`)
+
+	_, err := avro.Marshal(schema, Order{Number: "42"})
+
+	assert.Error(t, err)
+}
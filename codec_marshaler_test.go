@@ -2,6 +2,7 @@ package avro_test
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"testing"
 	"time"
@@ -58,6 +59,19 @@ func TestDecoder_TextUnmarshalerError(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestDecoder_TextUnmarshalerLargerThanMaxByteSliceSize(t *testing.T) {
+	defer ConfigTeardown()
+
+	api := avro.Config{MaxByteSliceSize: 16}.Freeze()
+	// A string length of 2^40, with no data following it.
+	data := []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x40}
+
+	var ts *TestTimestampPtr
+	err := api.Unmarshal(avro.MustParse("string"), data, &ts)
+
+	assert.EqualError(t, err, "avro: ReadBYTES: size is greater than `Config.MaxByteSliceSize`")
+}
+
 func TestEncoder_TextMarshaler(t *testing.T) {
 	defer ConfigTeardown()
 
@@ -142,3 +156,93 @@ func (t *TestTimestampError) UnmarshalText(data []byte) error {
 func (t *TestTimestampError) MarshalText() ([]byte, error) {
 	return nil, errors.New("test")
 }
+
+func TestEncoder_UUIDArray(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{"type": "string", "logicalType": "uuid"}`)
+	id := [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+
+	got, err := avro.Marshal(schema, id)
+
+	require.NoError(t, err)
+	want, err := avro.Marshal(avro.MustParse("string"), "123e4567-e89b-12d3-a456-426614174000")
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestEncoder_UUIDMarshaler(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "Entity",
+		"fields": [
+			{"name": "id", "type": {"type": "string", "logicalType": "uuid"}}
+		]
+	}`)
+	v := struct {
+		ID TestUUID `avro:"id"`
+	}{ID: TestUUID{hi: 0x123e4567e89b12d3, lo: 0xa456426614174000}}
+
+	data, err := avro.Marshal(schema, v)
+	require.NoError(t, err)
+
+	var got map[string]any
+	err = avro.Unmarshal(schema, data, &got)
+	require.NoError(t, err)
+	assert.Equal(t, "123e4567-e89b-12d3-a456-426614174000", got["id"])
+}
+
+func TestDecoder_UUIDArray(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{"type": "string", "logicalType": "uuid"}`)
+	data, err := avro.Marshal(avro.MustParse("string"), "123E4567-e89b-12d3-a456-426614174000")
+	require.NoError(t, err)
+
+	var id [16]byte
+	err = avro.Unmarshal(schema, data, &id)
+
+	require.NoError(t, err)
+	want := [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+	assert.Equal(t, want, id)
+}
+
+func TestDecoder_UUIDArrayMalformed(t *testing.T) {
+	defer ConfigTeardown()
+
+	tests := []string{
+		"",
+		"123e4567e89b12d3a456426614174000",
+		"123e4567-e89b-12d3-a456-42661417400",
+		"123e4567-e89b-12d3-a456-4266141740000",
+		"123e4567-e89b-12d3-a456_426614174000",
+		"123e4567-e89b-12d3-a456-42661417400g",
+		"{23e4567-e89b-12d3-a456-426614174000",
+	}
+
+	schema := avro.MustParse(`{"type": "string", "logicalType": "uuid"}`)
+	for _, test := range tests {
+		t.Run(test, func(t *testing.T) {
+			data, err := avro.Marshal(avro.MustParse("string"), test)
+			require.NoError(t, err)
+
+			var id [16]byte
+			err = avro.Unmarshal(schema, data, &id)
+
+			assert.ErrorContains(t, err, "invalid uuid")
+		})
+	}
+}
+
+type TestUUID struct {
+	hi, lo uint64
+}
+
+func (u TestUUID) UUID() [16]byte {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], u.hi)
+	binary.BigEndian.PutUint64(b[8:], u.lo)
+	return b
+}
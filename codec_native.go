@@ -471,7 +471,12 @@ func (c *dateCodec) Decode(ptr unsafe.Pointer, r *Reader) {
 
 func (c *dateCodec) Encode(ptr unsafe.Pointer, w *Writer) {
 	t := *((*time.Time)(ptr))
-	days := t.Unix() / int64(24*time.Hour/time.Second)
+	sec := t.Unix()
+	days := sec / int64(24*time.Hour/time.Second)
+	// Floor the days, so times before the epoch fall on their UTC day.
+	if sec%int64(24*time.Hour/time.Second) < 0 {
+		days--
+	}
 	w.WriteInt(int32(days))
 }
 
@@ -620,22 +625,7 @@ func (c *bytesDecimalCodec) Encode(ptr unsafe.Pointer, w *Writer) {
 		return
 	}
 
-	var b []byte
-	switch i.Sign() {
-	case 0:
-		b = []byte{0}
-
-	case 1:
-		b = i.Bytes()
-		if b[0]&0x80 > 0 {
-			b = append([]byte{0}, b...)
-		}
-
-	case -1:
-		length := uint(i.BitLen()/8+1) * 8
-		b = i.Add(i, (&big.Int{}).Lsh(one, length)).Bytes()
-	}
-	w.WriteBytes(b)
+	w.WriteBytes(bytesDecimalBytes(i))
 }
 
 type bytesDecimalPtrCodec struct {
@@ -667,20 +657,5 @@ func (c *bytesDecimalPtrCodec) Encode(ptr unsafe.Pointer, w *Writer) {
 		return
 	}
 
-	var b []byte
-	switch i.Sign() {
-	case 0:
-		b = []byte{0}
-
-	case 1:
-		b = i.Bytes()
-		if b[0]&0x80 > 0 {
-			b = append([]byte{0}, b...)
-		}
-
-	case -1:
-		length := uint(i.BitLen()/8+1) * 8
-		b = i.Add(i, (&big.Int{}).Lsh(one, length)).Bytes()
-	}
-	w.WriteBytes(b)
+	w.WriteBytes(bytesDecimalBytes(i))
 }
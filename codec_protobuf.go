@@ -1,82 +1,293 @@
 package avro
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
 	"unsafe"
 
+	"github.com/ettle/strcase"
+	"github.com/hamba/avro/v2/avropb"
 	"github.com/modern-go/reflect2"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
 )
 
 var protoMessageType = reflect2.TypeOfPtr((*proto.Message)(nil)).Elem()
 
 // createDecoderOfProtobuf creates a decoder for protobuf messages.
 // Returns nil if the type does not implement proto.Message or if schema is not a Record.
-func createDecoderOfProtobuf(schema Schema, typ reflect2.Type) ValDecoder {
-	if schema.Type() != Record {
+func createDecoderOfProtobuf(d *decoderContext, schema Schema, typ reflect2.Type) ValDecoder {
+	if schema.Type() != Record || d.cfg.config.DisableProtobufCodec {
 		return nil
 	}
 	if typ.Implements(protoMessageType) {
-		return &protobufCodec{typ: typ, schema: schema.(*RecordSchema)}
+		codec, err := newProtobufCodec(d.cfg, typ, schema.(*RecordSchema))
+		if err != nil {
+			return &errorDecoder{err: err}
+		}
+		return codec
 	}
 	ptrType := reflect2.PtrTo(typ)
 	if ptrType.Implements(protoMessageType) {
-		return &referenceDecoder{
-			&protobufCodec{typ: ptrType, schema: schema.(*RecordSchema)},
+		codec, err := newProtobufCodec(d.cfg, ptrType, schema.(*RecordSchema))
+		if err != nil {
+			return &errorDecoder{err: err}
 		}
+		return &referenceDecoder{codec}
 	}
 	return nil
 }
 
+// protoMessageTypeOf returns the registered protobuf message type with the full name
+// of the record schema, if it implements the interface type, or nil.
+func protoMessageTypeOf(schema *RecordSchema, typ reflect2.Type) protoreflect.MessageType {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(schema.FullName()))
+	if err != nil {
+		return nil
+	}
+	if !reflect.TypeOf(mt.Zero().Interface()).Implements(typ.Type1()) {
+		return nil
+	}
+	return mt
+}
+
 // createEncoderOfProtobuf creates an encoder for protobuf messages.
 // Returns nil if the type does not implement proto.Message or if schema is not a Record.
-func createEncoderOfProtobuf(schema Schema, typ reflect2.Type) ValEncoder {
-	if schema.Type() != Record {
+func createEncoderOfProtobuf(e *encoderContext, schema Schema, typ reflect2.Type) ValEncoder {
+	if schema.Type() != Record || e.cfg.config.DisableProtobufCodec {
 		return nil
 	}
 	if typ.Implements(protoMessageType) {
-		return &protobufCodec{typ: typ, schema: schema.(*RecordSchema)}
+		codec, err := newProtobufCodec(e.cfg, typ, schema.(*RecordSchema))
+		if err != nil {
+			return &errorEncoder{err: err}
+		}
+		return codec
 	}
 	ptrType := reflect2.PtrTo(typ)
 	if ptrType.Implements(protoMessageType) {
-		return &protobufPtrCodec{typ: ptrType, elemTyp: typ, schema: schema.(*RecordSchema)}
+		codec, err := newProtobufCodec(e.cfg, ptrType, schema.(*RecordSchema))
+		if err != nil {
+			return &errorEncoder{err: err}
+		}
+		return &protobufPtrCodec{codec: codec}
 	}
 	return nil
 }
 
 type protobufCodec struct {
+	cfg    *frozenConfig
 	typ    reflect2.Type
 	schema *RecordSchema
+	desc   protoreflect.MessageDescriptor
+	plan   *protoMessagePlan
+
+	nested sync.Map // map[protoNestedKey]*protobufCodec
 }
 
-func (c *protobufCodec) Decode(ptr unsafe.Pointer, r *Reader) {
-	obj := c.typ.UnsafeIndirect(ptr)
-	if reflect2.IsNil(obj) {
-		ptrType := c.typ.(*reflect2.UnsafePtrType)
-		newPtr := ptrType.Elem().UnsafeNew()
-		*((*unsafe.Pointer)(ptr)) = newPtr
-		obj = c.typ.UnsafeIndirect(ptr)
+func newProtobufCodec(cfg *frozenConfig, typ reflect2.Type, schema *RecordSchema) (*protobufCodec, error) {
+	msg := typ.(*reflect2.UnsafePtrType).Elem().New().(proto.Message)
+	desc := msg.ProtoReflect().Descriptor()
+
+	plan, err := cfg.protoMessagePlanOf(schema, desc)
+	if err != nil {
+		return nil, err
 	}
+	return &protobufCodec{
+		cfg:    cfg,
+		typ:    typ,
+		schema: schema,
+		desc:   desc,
+		plan:   plan,
+	}, nil
+}
 
-	msg := (obj).(proto.Message)
-	msgReflect := msg.ProtoReflect()
+type protoNestedKey struct {
+	schema *RecordSchema
+	desc   protoreflect.MessageDescriptor
+}
+
+// nestedCodec returns the codec for a nested message with the given descriptor.
+// Nested codecs are cached by the codec, so they are only built once for each
+// nested record, and a recursive message reuses the codec itself.
+func (c *protobufCodec) nestedCodec(schema *RecordSchema, desc protoreflect.MessageDescriptor) (*protobufCodec, error) {
+	if schema == c.schema && desc == c.desc {
+		return c, nil
+	}
+	key := protoNestedKey{schema: schema, desc: desc}
+	if codec, ok := c.nested.Load(key); ok {
+		return codec.(*protobufCodec), nil
+	}
+
+	plan, err := c.cfg.protoMessagePlanOf(schema, desc)
+	if err != nil {
+		return nil, err
+	}
+	codec, _ := c.nested.LoadOrStore(key, &protobufCodec{
+		cfg:    c.cfg,
+		schema: schema,
+		desc:   desc,
+		plan:   plan,
+	})
+	return codec.(*protobufCodec), nil
+}
+
+// listItemCodec returns the nested codec for the record items of a repeated message
+// field, so it is resolved once for the whole list instead of once per element.
+// It returns false if the items are not decoded with a nested codec.
+func (c *protobufCodec) listItemCodec(field protoreflect.FieldDescriptor, items Schema) (*protobufCodec, bool, error) {
+	if items.Type() == Ref {
+		items = items.(*RefSchema).Schema()
+	}
+	rec, ok := items.(*RecordSchema)
+	if !ok || !isProtoMessageKind(field.Kind()) || c.isResolvedAny(field, rec) {
+		return nil, false, nil
+	}
+	codec, err := c.nestedCodec(rec, field.Message())
+	if err != nil {
+		return nil, false, err
+	}
+	return codec, true, nil
+}
+
+// OneofBinding describes how a protobuf oneof maps to an Avro union field.
+type OneofBinding struct {
+	// Oneof is the name of the protobuf oneof.
+	Oneof string
+	// Field is the name of the Avro union field the oneof maps to.
+	Field string
+	// Branches maps the name of each oneof member field to the index of the
+	// union branch it is encoded as, or -1 if no branch matches it.
+	Branches map[string]int
+}
+
+// DescribeOneofBindings returns how the oneofs of msg map to the union fields
+// of the record schema, in schema field order. Only the oneofs of msg itself
+// are described, not those of nested messages.
+func DescribeOneofBindings(schema Schema, msg proto.Message) ([]OneofBinding, error) {
+	return DescribeOneofBindingsWithAPI(DefaultConfig, schema, msg)
+}
+
+// DescribeOneofBindingsWithAPI is like DescribeOneofBindings, but binds the oneofs
+// as the protobuf codec of api does.
+func DescribeOneofBindingsWithAPI(api API, schema Schema, msg proto.Message) ([]OneofBinding, error) {
+	cfg, ok := frozenConfigOf(api)
+	if !ok {
+		return nil, errors.New("avro: api must be created with Config.Freeze")
+	}
+	rec, ok := schema.(*RecordSchema)
+	if !ok {
+		return nil, fmt.Errorf("avro: expected record schema, got %s", schema.Type())
+	}
+
+	plan, err := cfg.protoMessagePlanOf(rec, msg.ProtoReflect().Descriptor())
+	if err != nil {
+		return nil, err
+	}
+	c := &protobufCodec{cfg: cfg, schema: rec, plan: plan}
+
+	var bindings []OneofBinding
+	for _, fp := range plan.fields {
+		if fp.binding != protoFieldOneof {
+			continue
+		}
 
-	if err := c.decodeMessage(msgReflect, r); err != nil {
-		r.ReportError("protobufCodec", err.Error())
+		union := fp.avro.Type().(*UnionSchema)
+		members := fp.oneof.Fields()
+		branches := make(map[string]int, members.Len())
+		for i := 0; i < members.Len(); i++ {
+			member := members.Get(i)
+			branches[string(member.Name())] = c.oneofBranchOf(member, union)
+		}
+		bindings = append(bindings, OneofBinding{
+			Oneof:    string(fp.oneof.Name()),
+			Field:    fp.avro.Name(),
+			Branches: branches,
+		})
 	}
+	return bindings, nil
+}
+
+// protoFieldBinding describes how an Avro record field binds to a protobuf message.
+type protoFieldBinding int
+
+const (
+	// protoFieldUnmapped is an Avro field with no protobuf counterpart.
+	protoFieldUnmapped protoFieldBinding = iota
+	// protoFieldValue is an Avro field mapped to a protobuf field.
+	protoFieldValue
+	// protoFieldOneof is an Avro field mapped to a protobuf oneof.
+	protoFieldOneof
+	// protoFieldOneofMember is an Avro field named after a member of a oneof, which is ignored.
+	protoFieldOneofMember
+	// protoFieldPresence is an Avro boolean field holding whether a protobuf field is set.
+	protoFieldPresence
+	// protoFieldDerived is an Avro field computed from the protobuf message, which is skipped on decode.
+	protoFieldDerived
+	// protoFieldUnknown is an Avro bytes field holding the unknown fields of the protobuf message.
+	protoFieldUnknown
+)
+
+// protoPresenceProp is the Avro field property naming the protobuf field whose
+// presence the boolean field holds, e.g. `"protoPresence": "name"` on `has_name`.
+const protoPresenceProp = "protoPresence"
+
+// protoUnknownFieldsProp is the Avro field property marking a bytes field as holding the
+// unknown fields of the protobuf message in the wire format, e.g. `"protoUnknownFields": true`.
+const protoUnknownFieldsProp = "protoUnknownFields"
+
+type protoFieldPlan struct {
+	binding protoFieldBinding
+	avro    *Field
+	field   protoreflect.FieldDescriptor
+	oneof   protoreflect.OneofDescriptor
+	skip    ValDecoder
+	def     []byte // The encoded Avro default, if the field is missing from the written data.
+	missing []byte // The encoded value of an unmapped field, if ProtoWriteDefaultsForMissing is set.
+	derive  func(proto.Message) (any, error)
+}
+
+// protoMessagePlan is the resolved mapping between an Avro record schema and
+// a protobuf message descriptor, in Avro field order.
+type protoMessagePlan struct {
+	fields []protoFieldPlan
+	mapped map[protoreflect.Name]struct{} // The protobuf field and oneof names bound to Avro fields.
 }
 
-func (c *protobufCodec) decodeMessage(msgReflect protoreflect.Message, r *Reader) error {
-	msgDesc := msgReflect.Descriptor()
-	fields := msgDesc.Fields()
-	oneofs := msgDesc.Oneofs()
+func newProtoMessagePlan(cfg *frozenConfig, schema *RecordSchema, desc protoreflect.MessageDescriptor) (*protoMessagePlan, error) {
+	fields := desc.Fields()
+	oneofs := desc.Oneofs()
+
+	plan := &protoMessagePlan{
+		fields: make([]protoFieldPlan, 0, len(schema.Fields())),
+		mapped: make(map[protoreflect.Name]struct{}, len(schema.Fields())),
+	}
 
 	// Track which oneofs we've processed
 	processedOneofs := make(map[protoreflect.OneofDescriptor]bool)
 
-	// Iterate through Avro schema fields in order
-	for _, avroField := range c.schema.Fields() {
+	for _, avroField := range schema.Fields() {
+		// A field only in the writer schema is not read by the reader schema.
+		if avroField.action == FieldIgnore {
+			plan.fields = append(plan.fields, protoFieldPlan{
+				binding: protoFieldUnmapped,
+				avro:    avroField,
+				skip:    createSkipDecoder(avroField.Type()),
+			})
+			continue
+		}
+
 		// Check if this Avro field maps to a real oneof (not a synthetic one used for optional fields)
 		var oneofDesc protoreflect.OneofDescriptor
 		for i := 0; i < oneofs.Len(); i++ {
@@ -92,47 +303,383 @@ func (c *protobufCodec) decodeMessage(msgReflect protoreflect.Message, r *Reader
 		}
 
 		if oneofDesc != nil && !processedOneofs[oneofDesc] {
-			// Handle oneof field
+			// A oneof can always be unset, so its union must be able to represent that.
+			union, ok := avroField.Type().(*UnionSchema)
+			if !ok {
+				return nil, fmt.Errorf("avro: oneof %s of %s must map to a union, got %s",
+					oneofDesc.Name(), desc.FullName(), avroField.Type().Type())
+			}
+			if _, pos := union.Types().Get(string(Null)); pos == -1 {
+				return nil, fmt.Errorf("avro: oneof %s of %s must map to a union with a null branch",
+					oneofDesc.Name(), desc.FullName())
+			}
+			if err := checkOneofBranches(cfg, oneofDesc, union); err != nil {
+				return nil, fmt.Errorf("avro: oneof %s of %s: %w", oneofDesc.Name(), desc.FullName(), err)
+			}
+
+			def, err := protoFieldDefault(cfg, avroField)
+			if err != nil {
+				return nil, err
+			}
+
 			processedOneofs[oneofDesc] = true
-			if err := c.decodeOneofField(msgReflect, oneofDesc, avroField.Type(), r); err != nil {
-				return err
+			plan.mapped[oneofDesc.Name()] = struct{}{}
+			plan.fields = append(plan.fields, protoFieldPlan{binding: protoFieldOneof, avro: avroField, oneof: oneofDesc, def: def})
+			continue
+		}
+
+		if name, ok := avroField.Prop(protoPresenceProp).(string); ok {
+			protoField, err := protoPresenceField(avroField, fields.ByName(protoreflect.Name(name)), desc)
+			if err != nil {
+				return nil, err
+			}
+			// A field missing from the written data has nothing to read.
+			if avroField.action != FieldSetDefault {
+				plan.fields = append(plan.fields, protoFieldPlan{binding: protoFieldPresence, avro: avroField, field: protoField})
+			}
+			continue
+		}
+
+		if unknown, _ := avroField.Prop(protoUnknownFieldsProp).(bool); unknown {
+			if avroField.Type().Type() != Bytes {
+				return nil, fmt.Errorf("avro: field %s of %s holding unknown fields must be bytes, got %s",
+					avroField.Name(), desc.FullName(), avroField.Type().Type())
+			}
+			// A field missing from the written data has nothing to read.
+			if avroField.action != FieldSetDefault {
+				plan.fields = append(plan.fields, protoFieldPlan{binding: protoFieldUnknown, avro: avroField})
 			}
-			if r.Error != nil {
-				return r.Error
+			continue
+		}
+
+		if derive, ok := cfg.config.ProtoDerivedFields[string(desc.FullName())+"."+avroField.Name()]; ok {
+			// A field missing from the written data has nothing to skip.
+			if avroField.action != FieldSetDefault {
+				plan.fields = append(plan.fields, protoFieldPlan{
+					binding: protoFieldDerived,
+					avro:    avroField,
+					skip:    createSkipDecoder(avroField.Type()),
+					derive:  derive,
+				})
 			}
 			continue
 		}
 
 		// Find corresponding protobuf field by name
 		protoField := fields.ByName(protoreflect.Name(avroField.Name()))
+		if protoField == nil && cfg.config.ProtoMatchJSONName {
+			protoField = fields.ByJSONName(avroField.Name())
+		}
+		// A renamed protobuf field can be matched by a name in the field aliases.
+		for _, alias := range avroField.Aliases() {
+			if protoField != nil {
+				break
+			}
+			protoField = fields.ByName(protoreflect.Name(alias))
+		}
 		if protoField == nil {
-			// Field not in protobuf message, skip it in the Avro data
-			skipDecoder := createSkipDecoder(avroField.Type())
-			skipDecoder.Decode(nil, r)
-			if r.Error != nil {
-				return r.Error
+			// A field missing from the written data has nothing to skip.
+			if avroField.action == FieldSetDefault {
+				continue
+			}
+			fp := protoFieldPlan{
+				binding: protoFieldUnmapped,
+				avro:    avroField,
+				skip:    createSkipDecoder(avroField.Type()),
+			}
+			if cfg.config.ProtoWriteDefaultsForMissing {
+				missing, err := protoMissingFieldValue(cfg, avroField)
+				if err != nil {
+					return nil, err
+				}
+				fp.missing = missing
+			}
+			plan.fields = append(plan.fields, fp)
+			continue
+		}
+
+		if isProtoIgnored(protoField) {
+			// An ignored field is never read or written, so it is handled as missing.
+			if avroField.action == FieldSetDefault {
+				continue
+			}
+			missing, err := protoMissingFieldValue(cfg, avroField)
+			if err != nil {
+				return nil, err
 			}
+			plan.fields = append(plan.fields, protoFieldPlan{
+				binding: protoFieldUnmapped,
+				avro:    avroField,
+				skip:    createSkipDecoder(avroField.Type()),
+				missing: missing,
+			})
 			continue
 		}
 
-		// Skip if field is part of a real oneof (not synthetic - already handled above)
+		// Skip if field is part of a real oneof (not synthetic - handled through the oneof)
 		containingOneof := protoField.ContainingOneof()
 		if containingOneof != nil && !containingOneof.IsSynthetic() {
+			plan.fields = append(plan.fields, protoFieldPlan{binding: protoFieldOneofMember, avro: avroField, field: protoField})
 			continue
 		}
 
-		// Read value from Avro and set it in protobuf message
-		if err := c.decodeField(msgReflect, protoField, avroField.Type(), r); err != nil {
-			return err
+		def, err := protoFieldDefault(cfg, avroField)
+		if err != nil {
+			return nil, err
+		}
+
+		plan.mapped[protoField.Name()] = struct{}{}
+		plan.fields = append(plan.fields, protoFieldPlan{binding: protoFieldValue, avro: avroField, field: protoField, def: def})
+	}
+	return plan, nil
+}
+
+// isProtoIgnored reports whether the field is marked with the (avro.ignore) option,
+// excluding it from Avro serialization.
+func isProtoIgnored(field protoreflect.FieldDescriptor) bool {
+	ignore, _ := proto.GetExtension(field.Options(), avropb.E_Ignore).(bool)
+	return ignore
+}
+
+// protoPresenceField validates the protobuf field a presence Avro field refers to.
+func protoPresenceField(avroField *Field, field protoreflect.FieldDescriptor, desc protoreflect.MessageDescriptor) (protoreflect.FieldDescriptor, error) {
+	if avroField.Type().Type() != Boolean {
+		return nil, fmt.Errorf("avro: presence field %s must be a boolean, got %s", avroField.Name(), avroField.Type().Type())
+	}
+	name := avroField.Prop(protoPresenceProp)
+	if field == nil {
+		return nil, fmt.Errorf("avro: presence field %s refers to unknown field %s of %s", avroField.Name(), name, desc.FullName())
+	}
+	if !field.HasPresence() || field.IsList() || field.IsMap() {
+		return nil, fmt.Errorf("avro: presence field %s refers to field %s of %s, which has no presence",
+			avroField.Name(), name, desc.FullName())
+	}
+	return field, nil
+}
+
+// checkOneofBranches ensures each union branch is used by at most one member of
+// the oneof, as the member could not be told apart on decode otherwise.
+func checkOneofBranches(cfg *frozenConfig, oneof protoreflect.OneofDescriptor, union *UnionSchema) error {
+	c := &protobufCodec{cfg: cfg}
+
+	members := make([]protoreflect.FieldDescriptor, len(union.Types()))
+	fields := oneof.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		idx := c.oneofBranchOf(field, union)
+		if idx == -1 {
+			continue
+		}
+		if other := members[idx]; other != nil {
+			return fmt.Errorf("fields %s and %s both map to union branch %d, name the branches after the fields",
+				other.Name(), field.Name(), idx)
+		}
+		members[idx] = field
+	}
+	return nil
+}
+
+// protoFieldDefault returns the encoded default of an Avro field that is missing from
+// the written data, as indicated by schema resolution, or nil if the field is written.
+func protoFieldDefault(cfg *frozenConfig, field *Field) ([]byte, error) {
+	if field.action != FieldSetDefault || !field.hasDef {
+		return nil, nil
+	}
+	def, err := encodeFieldDefault(cfg, field)
+	if err != nil {
+		return nil, fmt.Errorf("avro: encode default of field %s: %w", field.Name(), err)
+	}
+	return def, nil
+}
+
+// protoMissingFieldValue returns the encoded value written for an Avro field with no
+// protobuf counterpart: null for a nullable union, otherwise the field default, or
+// the zero value of the field type if it has none. It returns nil if the field type
+// has no zero value.
+func protoMissingFieldValue(cfg *frozenConfig, field *Field) ([]byte, error) {
+	w := cfg.borrowWriter()
+	defer cfg.returnWriter(w)
+
+	typ := field.Type()
+	if union, ok := typ.(*UnionSchema); ok {
+		if _, nullIdx := union.Types().Get(string(Null)); nullIdx != -1 {
+			w.WriteLong(int64(nullIdx))
+			return slices.Clone(w.Buffer()), nil
+		}
+	}
+	if field.HasDefault() {
+		def, err := encodeFieldDefault(cfg, field)
+		if err != nil {
+			return nil, fmt.Errorf("avro: encode default of field %s: %w", field.Name(), err)
+		}
+		return def, nil
+	}
+	if !writeAvroZeroValue(w, typ) {
+		return nil, nil
+	}
+	return slices.Clone(w.Buffer()), nil
+}
+
+// writeAvroZeroValue writes the zero value of the schema, matching the protobuf
+// default of the corresponding field kind. It reports false for records, which
+// have no zero value.
+func writeAvroZeroValue(w *Writer, schema Schema) bool {
+	switch schema.Type() {
+	case Ref:
+		return writeAvroZeroValue(w, schema.(*RefSchema).Schema())
+	case Null:
+	case Boolean:
+		w.WriteBool(false)
+	case Int, Enum:
+		w.WriteInt(0)
+	case Long:
+		w.WriteLong(0)
+	case Float:
+		w.WriteFloat(0)
+	case Double:
+		w.WriteDouble(0)
+	case String, Bytes:
+		w.WriteBytes(nil)
+	case Fixed:
+		_, _ = w.Write(make([]byte, schema.(*FixedSchema).Size()))
+	case Array, Map:
+		w.WriteLong(0)
+	case Union:
+		w.WriteLong(0)
+		return writeAvroZeroValue(w, schema.(*UnionSchema).Types()[0])
+	default:
+		return false
+	}
+	return true
+}
+
+type protoPlanKey struct {
+	fingerprint [32]byte
+	desc        protoreflect.MessageDescriptor
+}
+
+// protoMessagePlanOf returns the cached plan for the schema and descriptor pair,
+// creating it if needed.
+func (c *frozenConfig) protoMessagePlanOf(schema *RecordSchema, desc protoreflect.MessageDescriptor) (*protoMessagePlan, error) {
+	key := protoPlanKey{fingerprint: schema.CacheFingerprint(), desc: desc}
+	if plan, ok := c.protoPlanCache.Load(key); ok {
+		return plan.(*protoMessagePlan), nil
+	}
+
+	plan, err := newProtoMessagePlan(c, schema, desc)
+	if err != nil {
+		return nil, err
+	}
+	if !c.config.DisableCaching {
+		c.protoPlanCache.Store(key, plan)
+	}
+	return plan, nil
+}
+
+func (c *protobufCodec) Decode(ptr unsafe.Pointer, r *Reader) {
+	obj := c.typ.UnsafeIndirect(ptr)
+	if reflect2.IsNil(obj) {
+		ptrType := c.typ.(*reflect2.UnsafePtrType)
+		newPtr := ptrType.Elem().UnsafeNew()
+		*((*unsafe.Pointer)(ptr)) = newPtr
+		obj = c.typ.UnsafeIndirect(ptr)
+	}
+
+	msg := (obj).(proto.Message)
+	msgReflect := msg.ProtoReflect()
+
+	if err := c.decodeMessage(msgReflect, r, 0); err != nil {
+		//nolint:errorlint // Only the path of the outermost record is reported.
+		if pathErr, ok := err.(*protoPathError); ok {
+			r.reportError("protobufCodec", pathErr.path, pathErr.err)
+			return
+		}
+		r.reportError("protobufCodec", "", err)
+	}
+}
+
+func (c *protobufCodec) decodeMessage(msgReflect protoreflect.Message, r *Reader, depth int) error {
+	if err := c.checkDepth(depth); err != nil {
+		return err
+	}
+
+	// Fields marked absent are cleared once their values have been decoded.
+	var absent []protoreflect.FieldDescriptor
+	for _, fp := range c.plan.fields {
+		if fp.def != nil {
+			if err := c.decodeFieldDefault(msgReflect, fp, depth); err != nil {
+				return withProtoFieldPath(fp.avro.Name(), err)
+			}
+			continue
+		}
+
+		switch fp.binding {
+		case protoFieldOneof:
+			if err := c.decodeOneofField(msgReflect, fp.oneof, fp.avro.Type(), r, depth); err != nil {
+				return withProtoFieldPath(fp.avro.Name(), err)
+			}
+
+		case protoFieldUnmapped:
+			// Field not in protobuf message, skip it in the Avro data
+			fp.skip.Decode(nil, r)
+			if c.cfg.config.OnSkippedField != nil {
+				c.cfg.config.OnSkippedField(fp.avro.Name())
+			}
+
+		case protoFieldOneofMember:
+			continue
+
+		case protoFieldDerived:
+			fp.skip.Decode(nil, r)
+
+		case protoFieldPresence:
+			if !r.ReadBool() {
+				absent = append(absent, fp.field)
+			}
+
+		case protoFieldUnknown:
+			if unknown := r.ReadBytes(); len(unknown) > 0 {
+				msgReflect.SetUnknown(unknown)
+			}
+
+		case protoFieldValue:
+			// Read value from Avro and set it in protobuf message
+			if err := c.decodeField(msgReflect, fp.field, fp.avro.Type(), r, depth); err != nil {
+				return withProtoFieldPath(fp.avro.Name(), err)
+			}
 		}
 		if r.Error != nil {
 			return r.Error
 		}
 	}
+	for _, field := range absent {
+		msgReflect.Clear(field)
+	}
 	return nil
 }
 
-func (c *protobufCodec) decodeOneofField(msg protoreflect.Message, oneof protoreflect.OneofDescriptor, avroSchema Schema, r *Reader) error {
+// decodeFieldDefault sets the protobuf field or oneof of fp from its Avro default.
+func (c *protobufCodec) decodeFieldDefault(msg protoreflect.Message, fp protoFieldPlan, depth int) error {
+	r := c.cfg.borrowReader(fp.def)
+	defer c.cfg.returnReader(r)
+
+	var err error
+	switch fp.binding {
+	case protoFieldOneof:
+		err = c.decodeOneofField(msg, fp.oneof, fp.avro.Type(), r, depth)
+	case protoFieldValue:
+		err = c.decodeField(msg, fp.field, fp.avro.Type(), r, depth)
+	}
+	if err != nil {
+		return fmt.Errorf("decode default of field %s: %w", fp.avro.Name(), err)
+	}
+	if r.Error != nil && !errors.Is(r.Error, io.EOF) {
+		return fmt.Errorf("decode default of field %s: %w", fp.avro.Name(), r.Error)
+	}
+	return nil
+}
+
+func (c *protobufCodec) decodeOneofField(msg protoreflect.Message, oneof protoreflect.OneofDescriptor, avroSchema Schema, r *Reader, depth int) error {
 	if avroSchema.Type() != Union {
 		return fmt.Errorf("expected union schema for oneof %s, got %s", oneof.Name(), avroSchema.Type())
 	}
@@ -149,6 +696,11 @@ func (c *protobufCodec) decodeOneofField(msg protoreflect.Message, oneof protore
 
 	// Handle null case - oneof fields are always nullable
 	if selectedSchema.Type() == Null {
+		// A null is held by the NullValue member, as in google.protobuf.Value.
+		if nullField := protoNullValueField(oneof); nullField != nil {
+			msg.Set(nullField, protoreflect.ValueOfEnum(0))
+			return nil
+		}
 		// Clear any field that might be set in the oneof
 		whichField := msg.WhichOneof(oneof)
 		if whichField != nil {
@@ -163,7 +715,7 @@ func (c *protobufCodec) decodeOneofField(msg protoreflect.Message, oneof protore
 
 	for i := 0; i < oneofFields.Len(); i++ {
 		field := oneofFields.Get(i)
-		if c.fieldMatchesSchema(field, selectedSchema) {
+		if c.oneofBranchOf(field, unionSchema) == int(index) {
 			selectedField = field
 			break
 		}
@@ -174,7 +726,7 @@ func (c *protobufCodec) decodeOneofField(msg protoreflect.Message, oneof protore
 	}
 
 	// Decode the value for the selected field
-	val, err := c.decodeValue(msg, selectedField, selectedSchema, r)
+	val, err := c.decodeValue(msg, selectedField, oneofMemberSchema(selectedField, selectedSchema), r, depth)
 	if err != nil {
 		return err
 	}
@@ -188,13 +740,26 @@ func (c *protobufCodec) decodeOneofField(msg protoreflect.Message, oneof protore
 
 func (c *protobufCodec) fieldMatchesSchema(field protoreflect.FieldDescriptor, schema Schema) bool {
 	kind := field.Kind()
+	if schema.Type() == Ref {
+		schema = schema.(*RefSchema).Schema()
+	}
+	if isProtoNullValue(field) {
+		return schema.Type() == Null
+	}
 
 	switch schema.Type() {
 	case Int:
+		if isProtoTimestamp(field) {
+			return isDateSchema(schema)
+		}
 		return kind == protoreflect.Int32Kind || kind == protoreflect.Sint32Kind ||
 			kind == protoreflect.Sfixed32Kind || kind == protoreflect.Uint32Kind ||
 			kind == protoreflect.Fixed32Kind || kind == protoreflect.EnumKind
 	case Long:
+		if isProtoTimestamp(field) {
+			_, ok := protoTimestampUnit(schema)
+			return ok
+		}
 		return kind == protoreflect.Int64Kind || kind == protoreflect.Sint64Kind ||
 			kind == protoreflect.Sfixed64Kind || kind == protoreflect.Uint64Kind ||
 			kind == protoreflect.Fixed64Kind
@@ -205,59 +770,77 @@ func (c *protobufCodec) fieldMatchesSchema(field protoreflect.FieldDescriptor, s
 	case Boolean:
 		return kind == protoreflect.BoolKind
 	case String:
+		if c.cfg.config.ProtoInt64AsString && isProtoInt64Kind(kind) {
+			return true
+		}
 		return kind == protoreflect.StringKind || kind == protoreflect.EnumKind
+	case Enum:
+		if isProtoOrdinalEnum(schema.(*EnumSchema)) && kind == protoreflect.Int32Kind {
+			return true
+		}
+		return kind == protoreflect.EnumKind
 	case Bytes:
+		return kind == protoreflect.BytesKind || kind == protoreflect.MessageKind
+	case Fixed:
+		if _, ok := fixedDecimalOf(schema.(*FixedSchema)); ok && kind == protoreflect.StringKind {
+			return true
+		}
 		return kind == protoreflect.BytesKind
 	case Record:
-		if kind != protoreflect.MessageKind {
+		if !isProtoMessageKind(kind) {
 			return false
 		}
 		// For Record types, also check that the message type name matches
 		recordSchema := schema.(*RecordSchema)
+		if isProtoTimestamp(field) && isProtoTimestampRecord(recordSchema) {
+			return true
+		}
+		if isProtoAny(field) && isProtoAnyRecord(recordSchema) {
+			return true
+		}
 		msgDesc := field.Message()
 		return string(msgDesc.Name()) == recordSchema.Name()
+	case Array:
+		return kind == protoreflect.MessageKind && protoListWrapperField(field.Message()) != nil
+	case Map:
+		return isProtoStruct(field)
 	default:
 		return false
 	}
 }
 
-func (c *protobufCodec) decodeField(msg protoreflect.Message, field protoreflect.FieldDescriptor, avroSchema Schema, r *Reader) error {
+func (c *protobufCodec) decodeField(msg protoreflect.Message, field protoreflect.FieldDescriptor, avroSchema Schema, r *Reader, depth int) error {
 	if field.IsList() {
-		return c.decodeListField(msg, field, avroSchema, r)
+		return c.decodeListField(msg, field, avroSchema, r, depth)
 	}
 	if field.IsMap() {
-		return c.decodeMapField(msg, field, avroSchema, r)
+		return c.decodeMapField(msg, field, avroSchema, r, depth)
 	}
 
-	// Handle optional fields with nullable unions
-	if field.HasPresence() && avroSchema.Type() == Union {
+	// Handle unions, where null clears the field. A Value is itself a union.
+	if avroSchema.Type() == Union && !isProtoValue(field) {
 		unionSchema := avroSchema.(*UnionSchema)
-		if unionSchema.Nullable() {
-			// Read union index
-			index := r.ReadLong()
-			if index == 0 {
-				// Null value - clear the field (don't set it)
-				msg.Clear(field)
-				return nil
-			}
-			// Non-null value - read the actual value
-			if index >= int64(len(unionSchema.Types())) {
-				return fmt.Errorf("invalid union index %d", index)
-			}
-			actualSchema := unionSchema.Types()[index]
-			val, err := c.decodeValue(msg, field, actualSchema, r)
-			if err != nil {
-				return err
-			}
-			if val.IsValid() {
-				msg.Set(field, val)
-			}
+		index := r.ReadLong()
+		if index < 0 || index >= int64(len(unionSchema.Types())) {
+			return fmt.Errorf("invalid union index %d", index)
+		}
+		actualSchema := unionSchema.Types()[index]
+		if actualSchema.Type() == Null {
+			msg.Clear(field)
 			return nil
 		}
+		val, err := c.decodeValue(msg, field, actualSchema, r, depth)
+		if err != nil {
+			return err
+		}
+		if val.IsValid() {
+			msg.Set(field, val)
+		}
+		return nil
 	}
 
 	// Handle regular fields
-	val, err := c.decodeValue(msg, field, avroSchema, r)
+	val, err := c.decodeValue(msg, field, avroSchema, r, depth)
 	if err != nil {
 		return err
 	}
@@ -267,7 +850,7 @@ func (c *protobufCodec) decodeField(msg protoreflect.Message, field protoreflect
 	return nil
 }
 
-func (c *protobufCodec) decodeListField(msg protoreflect.Message, field protoreflect.FieldDescriptor, avroSchema Schema, r *Reader) error {
+func (c *protobufCodec) decodeListField(msg protoreflect.Message, field protoreflect.FieldDescriptor, avroSchema Schema, r *Reader, depth int) error {
 	if avroSchema.Type() != Array {
 		return fmt.Errorf("expected array schema for repeated field %s, got %s", field.Name(), avroSchema.Type())
 	}
@@ -281,12 +864,30 @@ func (c *protobufCodec) decodeListField(msg protoreflect.Message, field protoref
 		_ = r.ReadLong() // block size, ignored
 	}
 
+	itemCodec, ok, err := c.listItemCodec(field, arraySchema.Items())
+	if err != nil {
+		return err
+	}
+
+	fn := c.cfg.config.ProtoListElementFunc
 	for length > 0 {
 		for i := int64(0); i < length; i++ {
-			val, err := c.decodeValue(msg, field, arraySchema.Items(), r)
+			var val protoreflect.Value
+			if ok {
+				val = list.NewElement()
+				err = itemCodec.decodeMessage(val.Message(), r, depth+1)
+			} else {
+				val, err = c.decodeValue(msg, field, arraySchema.Items(), r, depth)
+			}
 			if err != nil {
 				return err
 			}
+			if fn != nil {
+				if err = fn(string(field.FullName()), protoListElement(field, val)); err != nil {
+					return err
+				}
+				continue
+			}
 			list.Append(val)
 		}
 		length = r.ReadLong()
@@ -298,7 +899,7 @@ func (c *protobufCodec) decodeListField(msg protoreflect.Message, field protoref
 	return nil
 }
 
-func (c *protobufCodec) decodeMapField(msg protoreflect.Message, field protoreflect.FieldDescriptor, avroSchema Schema, r *Reader) error {
+func (c *protobufCodec) decodeMapField(msg protoreflect.Message, field protoreflect.FieldDescriptor, avroSchema Schema, r *Reader, depth int) error {
 	if avroSchema.Type() != Map {
 		return fmt.Errorf("expected map schema for map field %s, got %s", field.Name(), avroSchema.Type())
 	}
@@ -318,12 +919,15 @@ func (c *protobufCodec) decodeMapField(msg protoreflect.Message, field protorefl
 
 	for length > 0 {
 		for i := int64(0); i < length; i++ {
-			key := protoreflect.ValueOfString(r.ReadString())
-			val, err := c.decodeValue(msg, field.MapValue(), mapSchema.Values(), r)
+			key, err := protoMapKeyOf(field.MapKey(), r.ReadString())
+			if err != nil {
+				return err
+			}
+			val, err := c.decodeValue(msg, field.MapValue(), mapSchema.Values(), r, depth)
 			if err != nil {
 				return err
 			}
-			mapVal.Set(key.MapKey(), val)
+			mapVal.Set(key, val)
 		}
 		length = r.ReadLong()
 		if length < 0 {
@@ -334,8 +938,92 @@ func (c *protobufCodec) decodeMapField(msg protoreflect.Message, field protorefl
 	return nil
 }
 
-func (c *protobufCodec) decodeValue(msg protoreflect.Message, field protoreflect.FieldDescriptor, avroSchema Schema, r *Reader) (protoreflect.Value, error) {
+// protoMapKeyOf parses an Avro map key into a protobuf map key of the given key field kind.
+func protoMapKeyOf(keyField protoreflect.FieldDescriptor, key string) (protoreflect.MapKey, error) {
+	var (
+		val protoreflect.Value
+		err error
+	)
+	switch keyField.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(key).MapKey(), nil
+	case protoreflect.BoolKind:
+		var b bool
+		b, err = strconv.ParseBool(key)
+		val = protoreflect.ValueOfBool(b)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		var i int64
+		i, err = strconv.ParseInt(key, 10, 32)
+		val = protoreflect.ValueOfInt32(int32(i))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		var i int64
+		i, err = strconv.ParseInt(key, 10, 64)
+		val = protoreflect.ValueOfInt64(i)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		var u uint64
+		u, err = strconv.ParseUint(key, 10, 32)
+		val = protoreflect.ValueOfUint32(uint32(u))
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		var u uint64
+		u, err = strconv.ParseUint(key, 10, 64)
+		val = protoreflect.ValueOfUint64(u)
+	default:
+		return protoreflect.MapKey{}, fmt.Errorf("unsupported protobuf map key type %s", keyField.Kind())
+	}
+	if err != nil {
+		return protoreflect.MapKey{}, fmt.Errorf("invalid %s map key %q", keyField.Kind(), key)
+	}
+	return val.MapKey(), nil
+}
+
+// protoMapKeyString formats a protobuf map key as an Avro map key.
+func protoMapKeyString(keyField protoreflect.FieldDescriptor, key protoreflect.MapKey) string {
+	switch keyField.Kind() {
+	case protoreflect.BoolKind:
+		return strconv.FormatBool(key.Bool())
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return strconv.FormatInt(key.Int(), 10)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return strconv.FormatUint(key.Uint(), 10)
+	default:
+		return key.String()
+	}
+}
+
+// readPromotedLong reads a long, promoting the value written by the writer schema
+// of a resolved schema if it has another type.
+func readPromotedLong(schema Schema, r *Reader) int64 {
+	if conv := createLongConverter(schema.(*PrimitiveSchema).encodedType); conv != nil {
+		return conv(r)
+	}
+	return r.ReadLong()
+}
+
+// readPromotedFloat reads a float, promoting the value written by the writer schema
+// of a resolved schema if it has another type.
+func readPromotedFloat(schema Schema, r *Reader) float32 {
+	if conv := createFloatConverter(schema.(*PrimitiveSchema).encodedType); conv != nil {
+		return conv(r)
+	}
+	return r.ReadFloat()
+}
+
+// readPromotedDouble reads a double, promoting the value written by the writer schema
+// of a resolved schema if it has another type.
+func readPromotedDouble(schema Schema, r *Reader) float64 {
+	if conv := createDoubleConverter(schema.(*PrimitiveSchema).encodedType); conv != nil {
+		return conv(r)
+	}
+	return r.ReadDouble()
+}
+
+func (c *protobufCodec) decodeValue(msg protoreflect.Message, field protoreflect.FieldDescriptor, avroSchema Schema, r *Reader, depth int) (protoreflect.Value, error) {
 	kind := field.Kind()
+	if avroSchema.Type() == Ref {
+		avroSchema = avroSchema.(*RefSchema).Schema()
+	}
 
 	switch avroSchema.Type() {
 	case Int:
@@ -347,34 +1035,74 @@ func (c *protobufCodec) decodeValue(msg protoreflect.Message, field protoreflect
 			return protoreflect.ValueOfUint32(uint32(val)), nil
 		case protoreflect.EnumKind:
 			return protoreflect.ValueOfEnum(protoreflect.EnumNumber(val)), nil
-		default:
-			return protoreflect.Value{}, fmt.Errorf("cannot decode int to protobuf field %s of type %s", field.Name(), kind)
-		}
+		case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+			return protoreflect.ValueOfInt64(int64(val)), nil
+		case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+			// Unlike the 32 bit kinds, the encoder never wraps 64 bit values
+			// into an int, so a negative value cannot be represented.
+			if val < 0 {
+				return protoreflect.Value{}, fmt.Errorf("protobuf field %s value %d overflows %s", field.Name(), val, kind)
+			}
+			return protoreflect.ValueOfUint64(uint64(val)), nil
+		case protoreflect.MessageKind:
+			if !isProtoTimestamp(field) || !isDateSchema(avroSchema) {
+				return protoreflect.Value{}, fmt.Errorf("cannot decode int to protobuf field %s of type %s", field.Name(), kind)
+			}
+			ts := newProtoMessageOf(msg, field)
+			setProtoTimestamp(ts, int64(val)*secondsPerDay, 1)
+			return protoreflect.ValueOfMessage(ts), nil
+		default:
+			return protoreflect.Value{}, fmt.Errorf("cannot decode int to protobuf field %s of type %s", field.Name(), kind)
+		}
 
 	case Long:
-		val := r.ReadLong()
+		val := readPromotedLong(avroSchema, r)
 		switch kind {
 		case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+			if unit, ok := protoTimestampUnit(avroSchema); ok {
+				val += c.epochOffset(unit)
+			}
 			return protoreflect.ValueOfInt64(val), nil
 		case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+			// Values above math.MaxInt64 are written as their two's complement
+			// long, but an int promoted to a long was never wrapped.
+			if val < 0 && avroSchema.(*PrimitiveSchema).encodedType == Int {
+				return protoreflect.Value{}, fmt.Errorf("protobuf field %s value %d overflows %s", field.Name(), val, kind)
+			}
 			return protoreflect.ValueOfUint64(uint64(val)), nil
+		case protoreflect.MessageKind:
+			unit, ok := protoTimestampUnit(avroSchema)
+			if !isProtoTimestamp(field) || !ok {
+				return protoreflect.Value{}, fmt.Errorf("cannot decode long to protobuf field %s of type %s", field.Name(), kind)
+			}
+			ts := newProtoMessageOf(msg, field)
+			setProtoTimestamp(ts, val+c.epochOffset(unit), unit)
+			return protoreflect.ValueOfMessage(ts), nil
 		default:
 			return protoreflect.Value{}, fmt.Errorf("cannot decode long to protobuf field %s of type %s", field.Name(), kind)
 		}
 
 	case Float:
-		val := r.ReadFloat()
+		val := readPromotedFloat(avroSchema, r)
 		if kind != protoreflect.FloatKind {
 			return protoreflect.Value{}, fmt.Errorf("cannot decode float to protobuf field %s of type %s", field.Name(), kind)
 		}
-		return protoreflect.ValueOfFloat32(val), nil
+		return protoreflect.ValueOfFloat32(float32(c.finiteFloat(float64(val)))), nil
 
 	case Double:
-		val := r.ReadDouble()
-		if kind != protoreflect.DoubleKind {
+		val := c.finiteFloat(readPromotedDouble(avroSchema, r))
+		switch {
+		case kind == protoreflect.DoubleKind:
+			return protoreflect.ValueOfFloat64(val), nil
+		case kind == protoreflect.FloatKind && c.cfg.config.ProtoNarrowDoubleToFloat:
+			f := float32(val)
+			if c.cfg.config.ProtoStrictFloatNarrowing && float64(f) != val && !math.IsNaN(val) {
+				return protoreflect.Value{}, fmt.Errorf("double value %v cannot be narrowed to float without loss for protobuf field %s", val, field.Name())
+			}
+			return protoreflect.ValueOfFloat32(f), nil
+		default:
 			return protoreflect.Value{}, fmt.Errorf("cannot decode double to protobuf field %s of type %s", field.Name(), kind)
 		}
-		return protoreflect.ValueOfFloat64(val), nil
 
 	case Boolean:
 		val := r.ReadBool()
@@ -387,43 +1115,235 @@ func (c *protobufCodec) decodeValue(msg protoreflect.Message, field protoreflect
 		val := r.ReadString()
 		switch kind {
 		case protoreflect.StringKind:
+			if c.cfg.config.ProtoValidateUTF8 && !utf8.ValidString(val) {
+				return protoreflect.Value{}, fmt.Errorf("protobuf field %s value is not valid UTF-8", field.Name())
+			}
+			if intern := c.cfg.config.ProtoStringInterner; intern != nil {
+				val = intern(val)
+			}
 			return protoreflect.ValueOfString(val), nil
 		case protoreflect.EnumKind:
-			enumVal := field.Enum().Values().ByName(protoreflect.Name(val))
-			if enumVal == nil {
-				return protoreflect.Value{}, fmt.Errorf("unknown enum value %s for field %s", val, field.Name())
+			return c.decodeEnumSymbol(field, val)
+		case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+			if !c.cfg.config.ProtoCoerceNumericStrings {
+				break
 			}
-			return protoreflect.ValueOfEnum(enumVal.Number()), nil
-		default:
-			return protoreflect.Value{}, fmt.Errorf("cannot decode string to protobuf field %s of type %s", field.Name(), kind)
+			i, err := strconv.ParseInt(val, 10, 32)
+			if err != nil {
+				return protoreflect.Value{}, fmt.Errorf("protobuf field %s value %q is not a valid %s", field.Name(), val, kind)
+			}
+			return protoreflect.ValueOfInt32(int32(i)), nil
+		case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+			if !c.cfg.config.ProtoCoerceNumericStrings {
+				break
+			}
+			u, err := strconv.ParseUint(val, 10, 32)
+			if err != nil {
+				return protoreflect.Value{}, fmt.Errorf("protobuf field %s value %q is not a valid %s", field.Name(), val, kind)
+			}
+			return protoreflect.ValueOfUint32(uint32(u)), nil
+		case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+			if !c.cfg.config.ProtoInt64AsString && !c.cfg.config.ProtoCoerceNumericStrings {
+				break
+			}
+			i, err := strconv.ParseInt(val, 10, 64)
+			if err != nil {
+				return protoreflect.Value{}, fmt.Errorf("protobuf field %s value %q is not a valid %s", field.Name(), val, kind)
+			}
+			return protoreflect.ValueOfInt64(i), nil
+		case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+			if !c.cfg.config.ProtoInt64AsString && !c.cfg.config.ProtoCoerceNumericStrings {
+				break
+			}
+			u, err := strconv.ParseUint(val, 10, 64)
+			if err != nil {
+				return protoreflect.Value{}, fmt.Errorf("protobuf field %s value %q is not a valid %s", field.Name(), val, kind)
+			}
+			return protoreflect.ValueOfUint64(u), nil
+		case protoreflect.FloatKind:
+			if !c.cfg.config.ProtoCoerceNumericStrings {
+				break
+			}
+			f, err := strconv.ParseFloat(val, 32)
+			if err != nil {
+				return protoreflect.Value{}, fmt.Errorf("protobuf field %s value %q is not a valid %s", field.Name(), val, kind)
+			}
+			return protoreflect.ValueOfFloat32(float32(f)), nil
+		case protoreflect.DoubleKind:
+			if !c.cfg.config.ProtoCoerceNumericStrings {
+				break
+			}
+			f, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				return protoreflect.Value{}, fmt.Errorf("protobuf field %s value %q is not a valid %s", field.Name(), val, kind)
+			}
+			return protoreflect.ValueOfFloat64(f), nil
 		}
+		return protoreflect.Value{}, fmt.Errorf("cannot decode string to protobuf field %s of type %s", field.Name(), kind)
+
+	case Enum:
+		idx := int(r.ReadInt())
+		enum := avroSchema.(*EnumSchema)
+		if kind == protoreflect.Int32Kind && isProtoOrdinalEnum(enum) {
+			if idx < 0 || idx >= len(enum.Symbols()) {
+				return protoreflect.Value{}, fmt.Errorf("invalid enum index %d for field %s", idx, field.Name())
+			}
+			return protoreflect.ValueOfInt32(int32(idx)), nil
+		}
+		if kind != protoreflect.EnumKind {
+			return protoreflect.Value{}, fmt.Errorf("cannot decode enum to protobuf field %s of type %s", field.Name(), kind)
+		}
+		sym, ok := enum.Symbol(idx)
+		if !ok {
+			return protoreflect.Value{}, fmt.Errorf("invalid enum index %d for field %s", idx, field.Name())
+		}
+		return c.decodeEnumSymbol(field, sym)
 
 	case Bytes:
-		val := r.ReadBytes()
-		if kind != protoreflect.BytesKind {
-			return protoreflect.Value{}, fmt.Errorf("cannot decode bytes to protobuf field %s of type %s", field.Name(), kind)
+		var val []byte
+		if c.cfg.config.ProtoZeroCopyBytes {
+			val = r.readBytesNoCopy()
+		} else {
+			val = r.ReadBytes()
+		}
+		switch kind {
+		case protoreflect.BytesKind:
+			return protoreflect.ValueOfBytes(val), nil
+		case protoreflect.MessageKind:
+			// The bytes hold the message in the protobuf wire format, which
+			// must parse as the field message type.
+			nestedMsg := newProtoMessageOf(msg, field)
+			if err := proto.Unmarshal(val, nestedMsg.Interface()); err != nil {
+				return protoreflect.Value{}, fmt.Errorf("protobuf field %s bytes are not a valid %s message: %w",
+					field.Name(), field.Message().FullName(), err)
+			}
+			return protoreflect.ValueOfMessage(nestedMsg), nil
+		}
+		return protoreflect.Value{}, fmt.Errorf("cannot decode bytes to protobuf field %s of type %s", field.Name(), kind)
+
+	case Fixed:
+		fixed := avroSchema.(*FixedSchema)
+		val := make([]byte, fixed.Size())
+		r.Read(val)
+		switch kind {
+		case protoreflect.BytesKind:
+			return protoreflect.ValueOfBytes(val), nil
+		case protoreflect.StringKind:
+			dec, ok := fixedDecimalOf(fixed)
+			if !ok {
+				break
+			}
+			return protoreflect.ValueOfString(ratFromBytes(val, dec.Scale()).FloatString(dec.Scale())), nil
 		}
-		return protoreflect.ValueOfBytes(val), nil
+		return protoreflect.Value{}, fmt.Errorf("cannot decode fixed to protobuf field %s of type %s", field.Name(), kind)
 
 	case Record:
-		if kind != protoreflect.MessageKind {
+		if !isProtoMessageKind(kind) {
 			return protoreflect.Value{}, fmt.Errorf("cannot decode record to protobuf field %s of type %s", field.Name(), kind)
 		}
-		nestedMsg := msg.NewField(field).Message()
-		nestedCodec := &protobufCodec{
-			typ:    nil, // Not needed for message-based decoding
-			schema: avroSchema.(*RecordSchema),
+		if c.isResolvedAny(field, avroSchema) {
+			return c.decodeAny(msg, field, avroSchema.(*RecordSchema), r, depth)
 		}
-		if err := nestedCodec.decodeMessage(nestedMsg, r); err != nil {
+		nestedMsg := newProtoMessageOf(msg, field)
+		nestedCodec, err := c.nestedCodec(avroSchema.(*RecordSchema), field.Message())
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		if err = nestedCodec.decodeMessage(nestedMsg, r, depth+1); err != nil {
 			return protoreflect.Value{}, err
 		}
 		return protoreflect.ValueOfMessage(nestedMsg), nil
 
+	case Array:
+		if kind != protoreflect.MessageKind || protoListWrapperField(field.Message()) == nil {
+			return protoreflect.Value{}, fmt.Errorf("cannot decode array to protobuf field %s of type %s", field.Name(), kind)
+		}
+		wrapper := newProtoMessageOf(msg, field)
+		if err := c.decodeListField(wrapper, protoListWrapperField(field.Message()), avroSchema, r, depth+1); err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfMessage(wrapper), nil
+
+	case Map:
+		if !isProtoStruct(field) {
+			return protoreflect.Value{}, fmt.Errorf("cannot decode map to protobuf field %s of type %s", field.Name(), kind)
+		}
+		structMsg := newProtoMessageOf(msg, field)
+		if err := c.decodeMapField(structMsg, structMsg.Descriptor().Fields().ByName("fields"), avroSchema, r, depth+1); err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfMessage(structMsg), nil
+
+	case Union:
+		if kind == protoreflect.MessageKind && protoOneofWrapper(field.Message()) != nil {
+			wrapper := newProtoMessageOf(msg, field)
+			if err := c.decodeOneofField(wrapper, protoOneofWrapper(field.Message()), avroSchema, r, depth+1); err != nil {
+				return protoreflect.Value{}, err
+			}
+			return protoreflect.ValueOfMessage(wrapper), nil
+		}
+		// Array items and map values of other types cannot be null.
+		unionSchema := avroSchema.(*UnionSchema)
+		index := r.ReadLong()
+		if index < 0 || index >= int64(len(unionSchema.Types())) {
+			return protoreflect.Value{}, fmt.Errorf("invalid union index %d", index)
+		}
+		actualSchema := unionSchema.Types()[index]
+		if actualSchema.Type() == Null {
+			return protoreflect.Value{}, fmt.Errorf("cannot decode null to protobuf field %s of type %s", field.Name(), kind)
+		}
+		return c.decodeValue(msg, field, actualSchema, r, depth)
+
 	default:
 		return protoreflect.Value{}, fmt.Errorf("unsupported avro type %s for protobuf field %s", avroSchema.Type(), field.Name())
 	}
 }
 
+// protoListWrapperField returns the repeated field of a message that wraps a single
+// list, or nil if the message is not a list wrapper. A list wrapper maps to an Avro array,
+// allowing arrays to be nested where protobuf does not allow repeated fields, such as map values.
+func protoListWrapperField(desc protoreflect.MessageDescriptor) protoreflect.FieldDescriptor {
+	if desc.Fields().Len() != 1 {
+		return nil
+	}
+	field := desc.Fields().Get(0)
+	if !field.IsList() {
+		return nil
+	}
+	return field
+}
+
+// protoOneofWrapper returns the oneof of a message holding a single oneof and no other
+// fields, or nil if the message is not a oneof wrapper. Like google.protobuf.Value, a oneof
+// wrapper maps to an Avro union, allowing unions as array items and map values.
+func protoOneofWrapper(desc protoreflect.MessageDescriptor) protoreflect.OneofDescriptor {
+	if desc.Oneofs().Len() != 1 {
+		return nil
+	}
+	oneof := desc.Oneofs().Get(0)
+	if oneof.IsSynthetic() || oneof.Fields().Len() != desc.Fields().Len() {
+		return nil
+	}
+	return oneof
+}
+
+// newProtoMessageOf returns a new message for the message-typed field of msg.
+// The field may also be a repeated field or the value field of a map.
+func newProtoMessageOf(msg protoreflect.Message, field protoreflect.FieldDescriptor) protoreflect.Message {
+	switch {
+	case field.IsList():
+		return msg.Mutable(field).List().NewElement().Message()
+	case field.ContainingMessage().IsMapEntry():
+		fields := msg.Descriptor().Fields()
+		for i := 0; i < fields.Len(); i++ {
+			if f := fields.Get(i); f.IsMap() && f.MapValue() == field {
+				return msg.Mutable(f).Map().NewValue().Message()
+			}
+		}
+	}
+	return msg.NewField(field).Message()
+}
+
 func (c *protobufCodec) Encode(ptr unsafe.Pointer, w *Writer) {
 	obj := c.typ.UnsafeIndirect(ptr)
 	if c.typ.IsNullable() && reflect2.IsNil(obj) {
@@ -434,51 +1354,31 @@ func (c *protobufCodec) Encode(ptr unsafe.Pointer, w *Writer) {
 	msg := (obj).(proto.Message)
 	msgReflect := msg.ProtoReflect()
 
-	if err := c.encodeMessage(msgReflect, w); err != nil {
+	if err := c.encodeMessage(msgReflect, w, 0); err != nil {
 		w.Error = err
 	}
 }
 
-func (c *protobufCodec) encodeMessage(msgReflect protoreflect.Message, w *Writer) error {
-	msgDesc := msgReflect.Descriptor()
-	fields := msgDesc.Fields()
-	oneofs := msgDesc.Oneofs()
-
-	// Track which oneofs we've processed
-	processedOneofs := make(map[protoreflect.OneofDescriptor]bool)
+func (c *protobufCodec) encodeMessage(msgReflect protoreflect.Message, w *Writer, depth int) error {
+	if err := c.checkDepth(depth); err != nil {
+		return err
+	}
 
-	// Iterate through Avro schema fields in order
-	for _, avroField := range c.schema.Fields() {
-		// Check if this Avro field maps to a real oneof (not a synthetic one used for optional fields)
-		var oneofDesc protoreflect.OneofDescriptor
-		for i := 0; i < oneofs.Len(); i++ {
-			oneof := oneofs.Get(i)
-			// Skip synthetic oneofs (used for optional fields in proto3)
-			if oneof.IsSynthetic() {
-				continue
+	for _, fp := range c.plan.fields {
+		switch fp.binding {
+		case protoFieldOneof:
+			if err := c.encodeOneofField(msgReflect, fp.oneof, fp.avro.Type(), w, depth); err != nil {
+				return withProtoFieldPath(fp.avro.Name(), err)
 			}
-			if string(oneof.Name()) == avroField.Name() {
-				oneofDesc = oneof
-				break
-			}
-		}
 
-		if oneofDesc != nil && !processedOneofs[oneofDesc] {
-			// Handle oneof field
-			processedOneofs[oneofDesc] = true
-			if err := c.encodeOneofField(msgReflect, oneofDesc, avroField.Type(), w); err != nil {
-				return err
-			}
-			if w.Error != nil {
-				return w.Error
+		case protoFieldUnmapped:
+			if fp.missing != nil {
+				_, _ = w.Write(fp.missing)
+				continue
 			}
-			continue
-		}
 
-		// Find corresponding protobuf field by name
-		protoField := fields.ByName(protoreflect.Name(avroField.Name()))
-		if protoField == nil {
 			// Field not in protobuf message, use default value if available
+			avroField := fp.avro
 			if avroField.HasDefault() {
 				def := avroField.Default()
 				if def == nil {
@@ -493,26 +1393,95 @@ func (c *protobufCodec) encodeMessage(msgReflect protoreflect.Message, w *Writer
 				return fmt.Errorf("field %s not found in protobuf message and no null default", avroField.Name())
 			}
 			return fmt.Errorf("required field %s not found in protobuf message", avroField.Name())
-		}
 
-		// Skip if field is part of a real oneof (not synthetic - already handled above)
-		containingOneof := protoField.ContainingOneof()
-		if containingOneof != nil && !containingOneof.IsSynthetic() {
+		case protoFieldOneofMember:
 			continue
-		}
 
-		// Encode the field value
-		if err := c.encodeField(msgReflect, protoField, avroField.Type(), w); err != nil {
-			return err
+		case protoFieldPresence:
+			w.WriteBool(msgReflect.Has(fp.field))
+
+		case protoFieldUnknown:
+			w.WriteBytes(msgReflect.GetUnknown())
+
+		case protoFieldDerived:
+			val, err := fp.derive(msgReflect.Interface())
+			if err != nil {
+				return fmt.Errorf("derive field %s: %w", fp.avro.Name(), err)
+			}
+			w.WriteVal(fp.avro.Type(), val)
+
+		case protoFieldValue:
+			// Encode the field value
+			if err := c.encodeField(msgReflect, fp.field, fp.avro.Type(), w, depth); err != nil {
+				return withProtoFieldPath(fp.avro.Name(), err)
+			}
 		}
 		if w.Error != nil {
 			return w.Error
 		}
 	}
+
+	if c.cfg.config.DisallowUnmappedProtoFields {
+		return c.checkUnmappedFields(msgReflect)
+	}
 	return nil
 }
 
-func (c *protobufCodec) encodeOneofField(msg protoreflect.Message, oneof protoreflect.OneofDescriptor, avroSchema Schema, w *Writer) error {
+// protoPathError is an error encoding or decoding a field of a protobuf message,
+// with the path of the Avro field from the root record (e.g. `author.address.zip_code`).
+type protoPathError struct {
+	path string
+	err  error
+}
+
+func (e *protoPathError) Error() string {
+	return e.path + ": " + e.err.Error()
+}
+
+func (e *protoPathError) Unwrap() error {
+	return e.err
+}
+
+// withProtoFieldPath prepends the Avro field name to the path of err, as it is
+// returned from each enclosing record.
+func withProtoFieldPath(name string, err error) error {
+	//nolint:errorlint // Only direct path errors are extended, wrapped ones are kept as is.
+	if pathErr, ok := err.(*protoPathError); ok {
+		return &protoPathError{path: name + "." + pathErr.path, err: pathErr.err}
+	}
+	return &protoPathError{path: name, err: err}
+}
+
+// checkDepth returns an error if depth exceeds the configured maximum recursion depth.
+func (c *protobufCodec) checkDepth(depth int) error {
+	if maxDepth := c.cfg.getProtoMaxRecursionDepth(); maxDepth > 0 && depth > maxDepth {
+		return fmt.Errorf("max protobuf recursion depth %d exceeded in %s", maxDepth, c.schema.FullName())
+	}
+	return nil
+}
+
+// checkUnmappedFields returns an error if msg has a populated field
+// that is not covered by a field in the Avro schema.
+func (c *protobufCodec) checkUnmappedFields(msg protoreflect.Message) error {
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		if !msg.Has(field) || isProtoIgnored(field) {
+			continue
+		}
+
+		name := field.Name()
+		if oneof := field.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() {
+			name = oneof.Name()
+		}
+		if _, ok := c.plan.mapped[name]; !ok {
+			return fmt.Errorf("protobuf field %s is set but not mapped in avro schema %s", field.Name(), c.schema.FullName())
+		}
+	}
+	return nil
+}
+
+func (c *protobufCodec) encodeOneofField(msg protoreflect.Message, oneof protoreflect.OneofDescriptor, avroSchema Schema, w *Writer, depth int) error {
 	if avroSchema.Type() != Union {
 		return fmt.Errorf("expected union schema for oneof %s, got %s", oneof.Name(), avroSchema.Type())
 	}
@@ -535,68 +1504,216 @@ func (c *protobufCodec) encodeOneofField(msg protoreflect.Message, oneof protore
 	}
 
 	// Find which union type corresponds to the set field
-	var unionIndex int = -1
-	var selectedSchema Schema
-
-	for i, schema := range unionSchema.Types() {
-		if schema.Type() == Null {
-			continue
-		}
-		if c.fieldMatchesSchema(whichField, schema) {
-			unionIndex = i
-			selectedSchema = schema
-			break
-		}
-	}
-
+	unionIndex := c.oneofBranchOf(whichField, unionSchema)
 	if unionIndex == -1 {
 		return fmt.Errorf("no matching union type found for oneof field %s", whichField.Name())
 	}
 
 	// Write the union index
-	w.WriteLong(int64(unionIndex))
+	if err := c.writeUnionIndex(w, whichField, unionSchema, unionIndex); err != nil {
+		return err
+	}
 
 	// Encode the value
 	val := msg.Get(whichField)
-	return c.encodeValue(msg, whichField, val, selectedSchema, w)
+	return c.encodeValue(msg, whichField, val, oneofMemberSchema(whichField, unionSchema.Types()[unionIndex]), w, depth)
+}
+
+// oneofBranchOf returns the index of the union branch the oneof member field
+// is encoded as, or -1 if no branch matches. A branch named after the field is
+// preferred, otherwise the first branch matching the field type is used.
+func (c *protobufCodec) oneofBranchOf(field protoreflect.FieldDescriptor, schema *UnionSchema) int {
+	index := -1
+	for i, t := range schema.Types() {
+		switch {
+		case t.Type() == Null:
+			if isProtoNullValue(field) {
+				return i
+			}
+			continue
+		case isOneofBranchNamed(field, t) && c.fieldMatchesSchema(field, oneofMemberSchema(field, t)):
+			return i
+		case index == -1 && c.fieldMatchesSchema(field, t):
+			index = i
+		}
+	}
+	return index
+}
+
+// isOneofBranchNamed reports whether the union branch schema is a named type
+// with the name of the oneof member field.
+func isOneofBranchNamed(field protoreflect.FieldDescriptor, schema Schema) bool {
+	if ref, ok := schema.(*RefSchema); ok {
+		schema = ref.Schema()
+	}
+	named, ok := schema.(NamedSchema)
+	return ok && named.Name() == string(field.Name())
+}
+
+// oneofMemberSchema returns the schema the oneof member field value is encoded as
+// in the union branch schema. A record named after the field, with a single field
+// of the same name, wraps the value so that members of the same type can be told
+// apart, in which case the schema of its field is returned.
+func oneofMemberSchema(field protoreflect.FieldDescriptor, schema Schema) Schema {
+	if ref, ok := schema.(*RefSchema); ok {
+		schema = ref.Schema()
+	}
+	rec, ok := schema.(*RecordSchema)
+	if !ok || rec.Name() != string(field.Name()) || len(rec.Fields()) != 1 || rec.Fields()[0].Name() != rec.Name() {
+		return schema
+	}
+	// The record is the message itself, not a wrapper.
+	if isProtoMessageKind(field.Kind()) && string(field.Message().Name()) == rec.Name() {
+		return schema
+	}
+	return rec.Fields()[0].Type()
 }
 
-func (c *protobufCodec) encodeField(msg protoreflect.Message, field protoreflect.FieldDescriptor, avroSchema Schema, w *Writer) error {
+func (c *protobufCodec) encodeField(msg protoreflect.Message, field protoreflect.FieldDescriptor, avroSchema Schema, w *Writer, depth int) error {
 	if field.IsList() {
-		return c.encodeListField(msg, field, avroSchema, w)
+		return c.encodeListField(msg, field, avroSchema, w, depth)
 	}
 	if field.IsMap() {
-		return c.encodeMapField(msg, field, avroSchema, w)
+		return c.encodeMapField(msg, field, avroSchema, w, depth)
 	}
 
-	// Handle optional fields with nullable unions
-	if field.HasPresence() && avroSchema.Type() == Union {
+	// A proto2 required field must be set, unless the Avro field is nullable.
+	if field.Cardinality() == protoreflect.Required && !msg.Has(field) {
+		nullIdx := -1
+		if u, ok := avroSchema.(*UnionSchema); ok {
+			_, nullIdx = u.Types().Get(string(Null))
+		}
+		if nullIdx == -1 {
+			return fmt.Errorf("required protobuf field %s is not set", field.Name())
+		}
+	}
+
+	if avroSchema.Type() == Union && !isProtoValue(field) {
 		unionSchema := avroSchema.(*UnionSchema)
-		if unionSchema.Nullable() {
-			// Check if the field is set
-			if !msg.Has(field) {
+
+		// Handle unset optional fields with nullable unions. Fields without presence
+		// are unset when they hold their zero value.
+		if !msg.Has(field) && (field.HasPresence() || c.cfg.config.ProtoImplicitZeroAsNull) {
+			if _, nullIdx := unionSchema.Types().Get(string(Null)); nullIdx != -1 {
 				// Field not set - write null
-				w.WriteLong(0)
+				w.WriteLong(int64(nullIdx))
 				return nil
 			}
-			// Field is set - write non-null index and value
-			// Find the non-null type in the union
-			for i, t := range unionSchema.Types() {
-				if t.Type() != Null {
-					w.WriteLong(int64(i))
-					val := msg.Get(field)
-					return c.encodeValue(msg, field, val, t, w)
-				}
+		}
+
+		val := msg.Get(field)
+		if c.cfg.config.ProtoNonFiniteFloatAsNull && isProtoNonFiniteFloat(field, val) {
+			if _, nullIdx := unionSchema.Types().Get(string(Null)); nullIdx != -1 {
+				w.WriteLong(int64(nullIdx))
+				return nil
 			}
-			return fmt.Errorf("no non-null type found in union for field %s", field.Name())
 		}
+
+		index, err := c.unionBranchOf(field, val, unionSchema)
+		if err != nil {
+			return err
+		}
+		if err = c.writeUnionIndex(w, field, unionSchema, index); err != nil {
+			return err
+		}
+		return c.encodeValue(msg, field, val, unionSchema.Types()[index], w, depth)
 	}
 
 	val := msg.Get(field)
-	return c.encodeValue(msg, field, val, avroSchema, w)
+	return c.encodeValue(msg, field, val, avroSchema, w, depth)
+}
+
+// unionBranchOf returns the index of the non-null union branch used to encode val.
+// When a 64-bit integer value fits in 32 bits and the union has an int branch,
+// the int branch is chosen over long. Otherwise, the first matching branch is used.
+func (c *protobufCodec) unionBranchOf(field protoreflect.FieldDescriptor, val protoreflect.Value, schema *UnionSchema) (int, error) {
+	index := -1
+	for i, t := range schema.Types() {
+		switch {
+		case t.Type() == Null:
+			continue
+		case t.Type() == Int && isProtoInt64Kind(field.Kind()) && protoValueFitsInt32(field.Kind(), val):
+			return i, nil
+		case c.isResolvedAny(field, t) && isProtoAnyOf(val.Message(), t):
+			return i, nil
+		case index == -1 && c.fieldMatchesSchema(field, t):
+			index = i
+		}
+	}
+	if index == -1 {
+		return 0, fmt.Errorf("no matching union type found for protobuf field %s", field.Name())
+	}
+	return index, nil
 }
 
-func (c *protobufCodec) encodeListField(msg protoreflect.Message, field protoreflect.FieldDescriptor, avroSchema Schema, w *Writer) error {
+// writeUnionIndex writes the index of the union branch the value of field is encoded as,
+// failing if the index is out of range or the branch does not match the field.
+func (c *protobufCodec) writeUnionIndex(w *Writer, field protoreflect.FieldDescriptor, schema *UnionSchema, index int) error {
+	types := schema.Types()
+	if index < 0 || index >= len(types) {
+		return fmt.Errorf("union index %d out of range for protobuf field %s", index, field.Name())
+	}
+	branch := types[index]
+	// 64 bit integers can be narrowed to an int branch, checked against overflow when encoded.
+	narrowed := branch.Type() == Int && isProtoInt64Kind(field.Kind())
+	if !narrowed && !c.isResolvedAny(field, branch) && !c.fieldMatchesSchema(field, oneofMemberSchema(field, branch)) {
+		return fmt.Errorf("union branch %d of type %s does not match protobuf field %s of type %s", index, branch.Type(), field.Name(), field.Kind())
+	}
+	w.WriteLong(int64(index))
+	return nil
+}
+
+// finiteFloat returns the value a decoded float is set to, mapping NaN and infinities
+// with Config.ProtoNonFiniteFloatFunc if it is set.
+func (c *protobufCodec) finiteFloat(f float64) float64 {
+	if fn := c.cfg.config.ProtoNonFiniteFloatFunc; fn != nil && (math.IsNaN(f) || math.IsInf(f, 0)) {
+		return fn(f)
+	}
+	return f
+}
+
+// isProtoNonFiniteFloat reports whether val is a NaN or infinite float or double.
+func isProtoNonFiniteFloat(field protoreflect.FieldDescriptor, val protoreflect.Value) bool {
+	if field.Kind() != protoreflect.FloatKind && field.Kind() != protoreflect.DoubleKind {
+		return false
+	}
+	f := val.Float()
+	return math.IsNaN(f) || math.IsInf(f, 0)
+}
+
+func isProtoInt64Kind(kind protoreflect.Kind) bool {
+	switch kind {
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return true
+	default:
+		return false
+	}
+}
+
+func protoValueFitsInt32(kind protoreflect.Kind, val protoreflect.Value) bool {
+	if kind == protoreflect.Uint64Kind || kind == protoreflect.Fixed64Kind {
+		return val.Uint() <= math.MaxInt32
+	}
+	return val.Int() >= math.MinInt32 && val.Int() <= math.MaxInt32
+}
+
+// protoListElement returns the Go value of a repeated field element, as passed to
+// Config.ProtoListElementFunc.
+func protoListElement(field protoreflect.FieldDescriptor, val protoreflect.Value) any {
+	if isProtoMessageKind(field.Kind()) {
+		return val.Message().Interface()
+	}
+	return val.Interface()
+}
+
+// isProtoMessageKind returns true for message fields, including proto2 groups, which
+// are nested messages with a different wire encoding.
+func isProtoMessageKind(kind protoreflect.Kind) bool {
+	return kind == protoreflect.MessageKind || kind == protoreflect.GroupKind
+}
+
+func (c *protobufCodec) encodeListField(msg protoreflect.Message, field protoreflect.FieldDescriptor, avroSchema Schema, w *Writer, depth int) error {
 	if avroSchema.Type() != Array {
 		return fmt.Errorf("expected array schema for repeated field %s, got %s", field.Name(), avroSchema.Type())
 	}
@@ -604,40 +1721,56 @@ func (c *protobufCodec) encodeListField(msg protoreflect.Message, field protoref
 	list := msg.Get(field).List()
 	length := list.Len()
 
-	if length == 0 {
-		w.WriteLong(0)
-		return nil
+	itemCodec, ok, err := c.listItemCodec(field, arraySchema.Items())
+	if err != nil {
+		return err
 	}
 
-	w.WriteLong(int64(length))
+	w.WriteArrayStart(length)
 	for i := 0; i < length; i++ {
 		val := list.Get(i)
-		if err := c.encodeValue(msg, field, val, arraySchema.Items(), w); err != nil {
+		if ok {
+			err = itemCodec.encodeMessage(val.Message(), w, depth+1)
+		} else {
+			err = c.encodeValue(msg, field, val, arraySchema.Items(), w, depth)
+		}
+		if err != nil {
 			return err
 		}
 	}
-	w.WriteLong(0)
+	w.WriteArrayEnd()
 	return nil
 }
 
-func (c *protobufCodec) encodeMapField(msg protoreflect.Message, field protoreflect.FieldDescriptor, avroSchema Schema, w *Writer) error {
+func (c *protobufCodec) encodeMapField(msg protoreflect.Message, field protoreflect.FieldDescriptor, avroSchema Schema, w *Writer, depth int) error {
 	if avroSchema.Type() != Map {
 		return fmt.Errorf("expected map schema for map field %s, got %s", field.Name(), avroSchema.Type())
 	}
 	mapSchema := avroSchema.(*MapSchema)
 	mapVal := msg.Get(field).Map()
-	length := mapVal.Len()
-
-	if length == 0 {
-		w.WriteLong(0)
+	w.WriteMapStart(mapVal.Len())
+	if c.cfg.config.SortMapKeys {
+		keys := make([]protoreflect.MapKey, 0, mapVal.Len())
+		mapVal.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+			keys = append(keys, k)
+			return true
+		})
+		slices.SortFunc(keys, func(a, b protoreflect.MapKey) int {
+			return compareProtoMapKeys(field.MapKey(), a, b)
+		})
+		for _, k := range keys {
+			w.WriteString(protoMapKeyString(field.MapKey(), k))
+			if err := c.encodeValue(msg, field.MapValue(), mapVal.Get(k), mapSchema.Values(), w, depth); err != nil {
+				return err
+			}
+		}
+		w.WriteMapEnd()
 		return nil
 	}
-
-	w.WriteLong(int64(length))
 	var encodeErr error
 	mapVal.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
-		w.WriteString(k.String())
-		if err := c.encodeValue(msg, field.MapValue(), v, mapSchema.Values(), w); err != nil {
+		w.WriteString(protoMapKeyString(field.MapKey(), k))
+		if err := c.encodeValue(msg, field.MapValue(), v, mapSchema.Values(), w, depth); err != nil {
 			encodeErr = err
 			return false
 		}
@@ -646,12 +1779,39 @@ func (c *protobufCodec) encodeMapField(msg protoreflect.Message, field protorefl
 	if encodeErr != nil {
 		return encodeErr
 	}
-	w.WriteLong(0)
+	w.WriteMapEnd()
 	return nil
 }
 
-func (c *protobufCodec) encodeValue(msg protoreflect.Message, field protoreflect.FieldDescriptor, val protoreflect.Value, avroSchema Schema, w *Writer) error {
+// compareProtoMapKeys compares two keys of a protobuf map in the natural order of
+// the key kind, so integer keys sort numerically (2 before 10) and false before true.
+func compareProtoMapKeys(keyField protoreflect.FieldDescriptor, a, b protoreflect.MapKey) int {
+	switch keyField.Kind() {
+	case protoreflect.BoolKind:
+		switch {
+		case a.Bool() == b.Bool():
+			return 0
+		case b.Bool():
+			return -1
+		default:
+			return 1
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return cmp.Compare(a.Int(), b.Int())
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return cmp.Compare(a.Uint(), b.Uint())
+	default:
+		return strings.Compare(a.String(), b.String())
+	}
+}
+
+func (c *protobufCodec) encodeValue(msg protoreflect.Message, field protoreflect.FieldDescriptor, val protoreflect.Value, avroSchema Schema, w *Writer, depth int) error {
 	kind := field.Kind()
+	if avroSchema.Type() == Ref {
+		avroSchema = avroSchema.(*RefSchema).Schema()
+	}
 
 	switch avroSchema.Type() {
 	case Int:
@@ -662,6 +1822,27 @@ func (c *protobufCodec) encodeValue(msg protoreflect.Message, field protoreflect
 			w.WriteInt(int32(val.Uint()))
 		case protoreflect.EnumKind:
 			w.WriteInt(int32(val.Enum()))
+		case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+			protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+			if !protoValueFitsInt32(kind, val) {
+				return fmt.Errorf("protobuf field %s value %v overflows int", field.Name(), val.Interface())
+			}
+			if kind == protoreflect.Uint64Kind || kind == protoreflect.Fixed64Kind {
+				w.WriteInt(int32(val.Uint()))
+				break
+			}
+			w.WriteInt(int32(val.Int()))
+		case protoreflect.MessageKind:
+			if !isProtoTimestamp(field) || !isDateSchema(avroSchema) {
+				return fmt.Errorf("cannot encode protobuf field %s of type %s to int", field.Name(), kind)
+			}
+			// Days are floored, so times before the epoch fall on the previous day.
+			secs := protoTimestampValue(val.Message(), 1)
+			days := secs / secondsPerDay
+			if secs%secondsPerDay < 0 {
+				days--
+			}
+			w.WriteInt(int32(days))
 		default:
 			return fmt.Errorf("cannot encode protobuf field %s of type %s to int", field.Name(), kind)
 		}
@@ -669,9 +1850,19 @@ func (c *protobufCodec) encodeValue(msg protoreflect.Message, field protoreflect
 	case Long:
 		switch kind {
 		case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
-			w.WriteLong(val.Int())
+			v := val.Int()
+			if unit, ok := protoTimestampUnit(avroSchema); ok {
+				v -= c.epochOffset(unit)
+			}
+			w.WriteLong(v)
 		case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
 			w.WriteLong(int64(val.Uint()))
+		case protoreflect.MessageKind:
+			unit, ok := protoTimestampUnit(avroSchema)
+			if !isProtoTimestamp(field) || !ok {
+				return fmt.Errorf("cannot encode protobuf field %s of type %s to long", field.Name(), kind)
+			}
+			w.WriteLong(protoTimestampValue(val.Message(), unit) - c.epochOffset(unit))
 		default:
 			return fmt.Errorf("cannot encode protobuf field %s of type %s to long", field.Name(), kind)
 		}
@@ -680,12 +1871,18 @@ func (c *protobufCodec) encodeValue(msg protoreflect.Message, field protoreflect
 		if kind != protoreflect.FloatKind {
 			return fmt.Errorf("cannot encode protobuf field %s of type %s to float", field.Name(), kind)
 		}
+		if c.cfg.config.RejectNonFiniteFloats && isProtoNonFiniteFloat(field, val) {
+			return fmt.Errorf("protobuf field %s value %v is not finite", field.Name(), val.Float())
+		}
 		w.WriteFloat(float32(val.Float()))
 
 	case Double:
 		if kind != protoreflect.DoubleKind {
 			return fmt.Errorf("cannot encode protobuf field %s of type %s to double", field.Name(), kind)
 		}
+		if c.cfg.config.RejectNonFiniteFloats && isProtoNonFiniteFloat(field, val) {
+			return fmt.Errorf("protobuf field %s value %v is not finite", field.Name(), val.Float())
+		}
 		w.WriteDouble(val.Float())
 
 	case Boolean:
@@ -699,34 +1896,139 @@ func (c *protobufCodec) encodeValue(msg protoreflect.Message, field protoreflect
 		case protoreflect.StringKind:
 			w.WriteString(val.String())
 		case protoreflect.EnumKind:
-			enumVal := field.Enum().Values().ByNumber(val.Enum())
-			if enumVal == nil {
-				return fmt.Errorf("invalid enum number %d for field %s", val.Enum(), field.Name())
+			sym, err := c.encodeEnumSymbol(field, val)
+			if err != nil {
+				return err
+			}
+			w.WriteString(sym)
+		case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+			if !c.cfg.config.ProtoInt64AsString {
+				return fmt.Errorf("cannot encode protobuf field %s of type %s to string", field.Name(), kind)
 			}
-			w.WriteString(string(enumVal.Name()))
+			w.WriteString(strconv.FormatInt(val.Int(), 10))
+		case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+			if !c.cfg.config.ProtoInt64AsString {
+				return fmt.Errorf("cannot encode protobuf field %s of type %s to string", field.Name(), kind)
+			}
+			w.WriteString(strconv.FormatUint(val.Uint(), 10))
 		default:
 			return fmt.Errorf("cannot encode protobuf field %s of type %s to string", field.Name(), kind)
 		}
 
+	case Enum:
+		if kind == protoreflect.Int32Kind && isProtoOrdinalEnum(avroSchema.(*EnumSchema)) {
+			idx := val.Int()
+			if idx < 0 || idx >= int64(len(avroSchema.(*EnumSchema).Symbols())) {
+				return fmt.Errorf("protobuf field %s value %d is not a valid enum index", field.Name(), idx)
+			}
+			w.WriteInt(int32(idx))
+			break
+		}
+		if kind != protoreflect.EnumKind {
+			return fmt.Errorf("cannot encode protobuf field %s of type %s to enum", field.Name(), kind)
+		}
+		sym, err := c.encodeEnumSymbol(field, val)
+		if err != nil {
+			return err
+		}
+		idx := slices.Index(avroSchema.(*EnumSchema).Symbols(), sym)
+		if idx < 0 {
+			return fmt.Errorf("unknown enum symbol %s for field %s", sym, field.Name())
+		}
+		w.WriteInt(int32(idx))
+
 	case Bytes:
-		if kind != protoreflect.BytesKind {
+		switch kind {
+		case protoreflect.BytesKind:
+			w.WriteBytes(val.Bytes())
+		case protoreflect.MessageKind:
+			b, err := proto.MarshalOptions{Deterministic: true}.Marshal(val.Message().Interface())
+			if err != nil {
+				return fmt.Errorf("marshal protobuf field %s: %w", field.Name(), err)
+			}
+			w.WriteBytes(b)
+		default:
 			return fmt.Errorf("cannot encode protobuf field %s of type %s to bytes", field.Name(), kind)
 		}
-		w.WriteBytes(val.Bytes())
+
+	case Fixed:
+		fixed := avroSchema.(*FixedSchema)
+		size := fixed.Size()
+		switch kind {
+		case protoreflect.BytesKind:
+			if len(val.Bytes()) != size {
+				return fmt.Errorf("protobuf field %s has %d bytes, expected fixed size %d", field.Name(), len(val.Bytes()), size)
+			}
+			_, _ = w.Write(val.Bytes())
+		case protoreflect.StringKind:
+			dec, ok := fixedDecimalOf(fixed)
+			if !ok {
+				return fmt.Errorf("cannot encode protobuf field %s of type %s to fixed", field.Name(), kind)
+			}
+			b, err := protoFixedDecimalBytes(field, val.String(), dec, size)
+			if err != nil {
+				return err
+			}
+			_, _ = w.Write(b)
+		default:
+			return fmt.Errorf("cannot encode protobuf field %s of type %s to fixed", field.Name(), kind)
+		}
 
 	case Record:
-		if kind != protoreflect.MessageKind {
+		if !isProtoMessageKind(kind) {
 			return fmt.Errorf("cannot encode protobuf field %s of type %s to record", field.Name(), kind)
 		}
 		nestedMsgReflect := val.Message()
-		nestedCodec := &protobufCodec{
-			typ:    nil, // Will be set when needed
-			schema: avroSchema.(*RecordSchema),
+		if c.isResolvedAny(field, avroSchema) {
+			var err error
+			if nestedMsgReflect, err = unpackProtoAny(nestedMsgReflect, avroSchema.(*RecordSchema)); err != nil {
+				return err
+			}
+		}
+		nestedCodec, err := c.nestedCodec(avroSchema.(*RecordSchema), nestedMsgReflect.Descriptor())
+		if err != nil {
+			return err
 		}
 		// Encode the nested message directly using its reflection
-		if err := nestedCodec.encodeMessage(nestedMsgReflect, w); err != nil {
+		if err := nestedCodec.encodeMessage(nestedMsgReflect, w, depth+1); err != nil {
+			return err
+		}
+
+	case Array:
+		if kind != protoreflect.MessageKind || protoListWrapperField(field.Message()) == nil {
+			return fmt.Errorf("cannot encode protobuf field %s of type %s to array", field.Name(), kind)
+		}
+		if err := c.encodeListField(val.Message(), protoListWrapperField(field.Message()), avroSchema, w, depth+1); err != nil {
+			return err
+		}
+
+	case Map:
+		if !isProtoStruct(field) {
+			return fmt.Errorf("cannot encode protobuf field %s of type %s to map", field.Name(), kind)
+		}
+		structMsg := val.Message()
+		if err := c.encodeMapField(structMsg, structMsg.Descriptor().Fields().ByName("fields"), avroSchema, w, depth+1); err != nil {
+			return err
+		}
+
+	case Null:
+		if !isProtoNullValue(field) {
+			return fmt.Errorf("cannot encode protobuf field %s of type %s to null", field.Name(), kind)
+		}
+
+	case Union:
+		if kind == protoreflect.MessageKind && protoOneofWrapper(field.Message()) != nil {
+			return c.encodeOneofField(val.Message(), protoOneofWrapper(field.Message()), avroSchema, w, depth+1)
+		}
+		unionSchema := avroSchema.(*UnionSchema)
+		index, err := c.unionBranchOf(field, val, unionSchema)
+		if err != nil {
+			return err
+		}
+		if err = c.writeUnionIndex(w, field, unionSchema, index); err != nil {
 			return err
 		}
+		return c.encodeValue(msg, field, val, unionSchema.Types()[index], w, depth)
 
 	default:
 		return fmt.Errorf("unsupported avro type %s for protobuf field %s", avroSchema.Type(), field.Name())
@@ -734,16 +2036,308 @@ func (c *protobufCodec) encodeValue(msg protoreflect.Message, field protoreflect
 	return nil
 }
 
+// fixedDecimalOf returns the decimal logical type of a fixed schema, if it has one.
+func fixedDecimalOf(fixed *FixedSchema) (*DecimalLogicalSchema, bool) {
+	ls := fixed.Logical()
+	if ls == nil || ls.Type() != Decimal {
+		return nil, false
+	}
+	return ls.(*DecimalLogicalSchema), true
+}
+
+// protoFixedDecimalBytes encodes the decimal string s as the unscaled value of a fixed decimal
+// of the given size. An empty string encodes as zero.
+func protoFixedDecimalBytes(field protoreflect.FieldDescriptor, s string, dec *DecimalLogicalSchema, size int) ([]byte, error) {
+	rat := new(big.Rat)
+	if s != "" {
+		if _, ok := rat.SetString(s); !ok {
+			return nil, fmt.Errorf("protobuf field %s value %q is not a decimal", field.Name(), s)
+		}
+	}
+
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(dec.Scale())), nil)
+	i := new(big.Int).Mul(rat.Num(), scale)
+	i = i.Div(i, rat.Denom())
+
+	if numDigits, ok := checkDecimalPrecision(i, dec.Precision()); !ok {
+		return nil, fmt.Errorf("protobuf field %s value %s exceeds decimal precision %d, has %d significant digits",
+			field.Name(), s, dec.Precision(), numDigits)
+	}
+	limit := new(big.Int).Lsh(one, uint(size*8-1))
+	if i.Cmp(limit) >= 0 || i.Cmp(new(big.Int).Neg(limit)) < 0 {
+		return nil, fmt.Errorf("protobuf field %s value %s does not fit in fixed size %d", field.Name(), s, size)
+	}
+	return fixedDecimalBytes(i, size), nil
+}
+
+const protoTimestampName protoreflect.FullName = "google.protobuf.Timestamp"
+
+func isProtoTimestamp(field protoreflect.FieldDescriptor) bool {
+	return field.Kind() == protoreflect.MessageKind && field.Message().FullName() == protoTimestampName
+}
+
+// isProtoTimestampRecord returns true if schema has the structure of a Timestamp
+// message, a long seconds field and an int nanos field, preserving nanosecond precision.
+func isProtoTimestampRecord(schema *RecordSchema) bool {
+	fields := schema.Fields()
+	return len(fields) == 2 &&
+		fields[0].Name() == "seconds" && fields[0].Type().Type() == Long &&
+		fields[1].Name() == "nanos" && fields[1].Type().Type() == Int
+}
+
+const protoAnyName protoreflect.FullName = "google.protobuf.Any"
+
+func isProtoAny(field protoreflect.FieldDescriptor) bool {
+	return field.Kind() == protoreflect.MessageKind && field.Message().FullName() == protoAnyName
+}
+
+// isProtoAnyRecord returns true if schema has the structure of an Any message, a string
+// type_url field and a bytes value field, holding the packed message as is.
+func isProtoAnyRecord(schema *RecordSchema) bool {
+	fields := schema.Fields()
+	return len(fields) == 2 &&
+		fields[0].Name() == "type_url" && fields[0].Type().Type() == String &&
+		fields[1].Name() == "value" && fields[1].Type().Type() == Bytes
+}
+
+// isResolvedAny returns true if the Any field is mapped to a record of the packed message,
+// which requires ProtoResolveAny.
+func (c *protobufCodec) isResolvedAny(field protoreflect.FieldDescriptor, schema Schema) bool {
+	if !c.cfg.config.ProtoResolveAny || !isProtoAny(field) {
+		return false
+	}
+	if schema.Type() == Ref {
+		schema = schema.(*RefSchema).Schema()
+	}
+	rec, ok := schema.(*RecordSchema)
+	return ok && !isProtoAnyRecord(rec)
+}
+
+// protoAnyTypeName returns the full name of the message packed in the Any message,
+// the last segment of its type URL.
+func protoAnyTypeName(anyMsg protoreflect.Message) string {
+	url := anyMsg.Get(anyMsg.Descriptor().Fields().ByName("type_url")).String()
+	return url[strings.LastIndexByte(url, '/')+1:]
+}
+
+// isProtoAnyOf returns true if the Any message packs the message with the full name
+// of the record schema.
+func isProtoAnyOf(anyMsg protoreflect.Message, schema Schema) bool {
+	if schema.Type() == Ref {
+		schema = schema.(*RefSchema).Schema()
+	}
+	return protoAnyTypeName(anyMsg) == schema.(*RecordSchema).FullName()
+}
+
+// unpackProtoAny returns the message packed in the Any message, which must be the
+// registered message with the full name of schema.
+func unpackProtoAny(anyMsg protoreflect.Message, schema *RecordSchema) (protoreflect.Message, error) {
+	name := protoAnyTypeName(anyMsg)
+	if name != schema.FullName() {
+		return nil, fmt.Errorf("protobuf any type %q does not match record %s", name, schema.FullName())
+	}
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, fmt.Errorf("protobuf any type %q: %w", name, err)
+	}
+	m := mt.New()
+	value := anyMsg.Get(anyMsg.Descriptor().Fields().ByName("value")).Bytes()
+	if err = proto.Unmarshal(value, m.Interface()); err != nil {
+		return nil, fmt.Errorf("unpacking protobuf any type %q: %w", name, err)
+	}
+	return m, nil
+}
+
+// decodeAny decodes a record into the registered message with the full name of schema,
+// and packs it in the Any field of msg.
+func (c *protobufCodec) decodeAny(msg protoreflect.Message, field protoreflect.FieldDescriptor, schema *RecordSchema, r *Reader, depth int) (protoreflect.Value, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(schema.FullName()))
+	if err != nil {
+		return protoreflect.Value{}, fmt.Errorf("protobuf any type %q: %w", schema.FullName(), err)
+	}
+	m := mt.New()
+	nestedCodec, err := c.nestedCodec(schema, m.Descriptor())
+	if err != nil {
+		return protoreflect.Value{}, err
+	}
+	if err = nestedCodec.decodeMessage(m, r, depth+1); err != nil {
+		return protoreflect.Value{}, err
+	}
+	value, err := proto.MarshalOptions{Deterministic: true}.Marshal(m.Interface())
+	if err != nil {
+		return protoreflect.Value{}, fmt.Errorf("packing protobuf any type %q: %w", schema.FullName(), err)
+	}
+
+	anyMsg := newProtoMessageOf(msg, field)
+	fields := anyMsg.Descriptor().Fields()
+	anyMsg.Set(fields.ByName("type_url"), protoreflect.ValueOfString(protoAnyURLPrefix+schema.FullName()))
+	anyMsg.Set(fields.ByName("value"), protoreflect.ValueOfBytes(value))
+	return protoreflect.ValueOfMessage(anyMsg), nil
+}
+
+// protoAnyURLPrefix is the type URL prefix used when packing messages, as in anypb.New.
+const protoAnyURLPrefix = "type.googleapis.com/"
+
+const (
+	protoStructName    protoreflect.FullName = "google.protobuf.Struct"
+	protoValueName     protoreflect.FullName = "google.protobuf.Value"
+	protoNullValueName protoreflect.FullName = "google.protobuf.NullValue"
+)
+
+// isProtoStruct returns true if the field is a Struct message, which maps to an Avro map
+// of its fields.
+func isProtoStruct(field protoreflect.FieldDescriptor) bool {
+	return field.Kind() == protoreflect.MessageKind && field.Message().FullName() == protoStructName
+}
+
+// isProtoValue returns true if the field is a Value message, which maps to an Avro union
+// of its kinds, with null for its null value.
+func isProtoValue(field protoreflect.FieldDescriptor) bool {
+	return field.Kind() == protoreflect.MessageKind && field.Message().FullName() == protoValueName
+}
+
+// isProtoNullValue returns true if the field is a NullValue enum, which maps to an Avro null.
+func isProtoNullValue(field protoreflect.FieldDescriptor) bool {
+	return field.Kind() == protoreflect.EnumKind && field.Enum().FullName() == protoNullValueName
+}
+
+// protoNullValueField returns the NullValue member of the oneof, or nil if it has none.
+func protoNullValueField(oneof protoreflect.OneofDescriptor) protoreflect.FieldDescriptor {
+	fields := oneof.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if isProtoNullValue(fields.Get(i)) {
+			return fields.Get(i)
+		}
+	}
+	return nil
+}
+
+const secondsPerDay = 24 * 60 * 60
+
+// isDateSchema returns true if schema is an int with the date logical type, the
+// number of days since the epoch, mapped to Timestamp fields at midnight UTC.
+func isDateSchema(schema Schema) bool {
+	lts, ok := schema.(LogicalTypeSchema)
+	return ok && lts.Logical() != nil && lts.Logical().Type() == Date
+}
+
+// protoTimestampUnit returns the number of units per second of a long timestamp
+// logical type. Local timestamps are handled as plain longs, without any time zone conversion.
+func protoTimestampUnit(schema Schema) (int64, bool) {
+	lts, ok := schema.(LogicalTypeSchema)
+	if !ok || lts.Logical() == nil {
+		return 0, false
+	}
+	switch lts.Logical().Type() {
+	case TimestampMillis, LocalTimestampMillis:
+		return 1e3, true
+	case TimestampMicros, LocalTimestampMicros:
+		return 1e6, true
+	default:
+		return 0, false
+	}
+}
+
+// epochOffset returns the configured timestamp epoch offset in units of unit per second.
+func (c *protobufCodec) epochOffset(unit int64) int64 {
+	return int64(c.cfg.config.ProtoTimestampEpochOffset) / (1e9 / unit)
+}
+
+// protoTimestampValue returns the Timestamp message ts in units since the epoch,
+// truncating sub-unit precision.
+func protoTimestampValue(ts protoreflect.Message, unit int64) int64 {
+	fields := ts.Descriptor().Fields()
+	secs := ts.Get(fields.ByName("seconds")).Int()
+	nanos := ts.Get(fields.ByName("nanos")).Int()
+	return secs*unit + nanos/(1e9/unit)
+}
+
+// setProtoTimestamp sets the Timestamp message ts from val in units since the epoch.
+func setProtoTimestamp(ts protoreflect.Message, val, unit int64) {
+	secs, frac := val/unit, val%unit
+	if frac < 0 {
+		secs--
+		frac += unit
+	}
+	fields := ts.Descriptor().Fields()
+	ts.Set(fields.ByName("seconds"), protoreflect.ValueOfInt64(secs))
+	ts.Set(fields.ByName("nanos"), protoreflect.ValueOfInt32(int32(frac*(1e9/unit))))
+}
+
+// protoOrdinalProp is the Avro enum property that maps the enum to protobuf int32 fields
+// holding the position of the symbol, instead of protobuf enum fields.
+const protoOrdinalProp = "protoOrdinal"
+
+func isProtoOrdinalEnum(schema *EnumSchema) bool {
+	ordinal, _ := schema.Prop(protoOrdinalProp).(bool)
+	return ordinal
+}
+
+// decodeEnumSymbol resolves an Avro enum symbol or string to the protobuf enum value of field.
+func (c *protobufCodec) decodeEnumSymbol(field protoreflect.FieldDescriptor, sym string) (protoreflect.Value, error) {
+	values := field.Enum().Values()
+	var enumVal protoreflect.EnumValueDescriptor
+	if c.cfg.config.ProtoEnumSymbolFunc != nil {
+		for i := range values.Len() {
+			if c.protoEnumSymbol(field.Enum(), values.Get(i)) == sym {
+				enumVal = values.Get(i)
+				break
+			}
+		}
+	} else if c.cfg.config.ProtoEnumStripPrefix {
+		enumVal = values.ByName(protoreflect.Name(protoEnumPrefix(field.Enum()) + sym))
+	}
+	if enumVal == nil {
+		enumVal = values.ByName(protoreflect.Name(sym))
+	}
+	if enumVal == nil {
+		if c.cfg.config.ProtoUnknownEnumAsNumber {
+			if num, err := strconv.ParseInt(sym, 10, 32); err == nil {
+				return protoreflect.ValueOfEnum(protoreflect.EnumNumber(num)), nil
+			}
+		}
+		return protoreflect.Value{}, fmt.Errorf("unknown enum value %s for field %s", sym, field.Name())
+	}
+	return protoreflect.ValueOfEnum(enumVal.Number()), nil
+}
+
+// encodeEnumSymbol returns the Avro symbol for the protobuf enum value val of field.
+func (c *protobufCodec) encodeEnumSymbol(field protoreflect.FieldDescriptor, val protoreflect.Value) (string, error) {
+	enumVal := field.Enum().Values().ByNumber(val.Enum())
+	if enumVal == nil {
+		if c.cfg.config.ProtoUnknownEnumAsNumber {
+			return strconv.FormatInt(int64(val.Enum()), 10), nil
+		}
+		return "", fmt.Errorf("invalid enum number %d for field %s", val.Enum(), field.Name())
+	}
+	return c.protoEnumSymbol(field.Enum(), enumVal), nil
+}
+
+// protoEnumSymbol returns the Avro symbol of the protobuf enum value enumVal of enum.
+func (c *protobufCodec) protoEnumSymbol(enum protoreflect.EnumDescriptor, enumVal protoreflect.EnumValueDescriptor) string {
+	name := string(enumVal.Name())
+	if c.cfg.config.ProtoEnumStripPrefix {
+		name = strings.TrimPrefix(name, protoEnumPrefix(enum))
+	}
+	if fn := c.cfg.config.ProtoEnumSymbolFunc; fn != nil {
+		name = fn(name)
+	}
+	return name
+}
+
+// protoEnumPrefix returns the conventional value name prefix of a protobuf enum,
+// the upper snake case enum name followed by an underscore.
+func protoEnumPrefix(enum protoreflect.EnumDescriptor) string {
+	return strcase.ToSNAKE(string(enum.Name())) + "_"
+}
+
 // protobufPtrCodec is used when a value type's pointer implements proto.Message
 type protobufPtrCodec struct {
-	typ     reflect2.Type
-	elemTyp reflect2.Type
-	schema  *RecordSchema
+	codec *protobufCodec
 }
 
 func (c *protobufPtrCodec) Encode(ptr unsafe.Pointer, w *Writer) {
 	// ptr points to the struct value, we need to pass the pointer (ptr itself)
 	// to the encoder since proto.Message expects a pointer receiver
-	codec := &protobufCodec{typ: c.typ, schema: c.schema}
-	codec.Encode(unsafe.Pointer(&ptr), w)
+	c.codec.Encode(noescape(unsafe.Pointer(&ptr)), w)
 }
@@ -0,0 +1,117 @@
+package avro
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ProtoMappingKind describes how an Avro field maps to a protobuf message.
+type ProtoMappingKind string
+
+// Protobuf mapping kinds.
+const (
+	// ProtoMappingField is an Avro field holding the value of a protobuf field.
+	ProtoMappingField ProtoMappingKind = "field"
+	// ProtoMappingOneof is an Avro union field holding the set member of a protobuf oneof.
+	ProtoMappingOneof ProtoMappingKind = "oneof"
+	// ProtoMappingPresence is an Avro boolean field holding whether a protobuf field is set.
+	ProtoMappingPresence ProtoMappingKind = "presence"
+	// ProtoMappingDerived is an Avro field computed by a function in Config.ProtoDerivedFields.
+	ProtoMappingDerived ProtoMappingKind = "derived"
+	// ProtoMappingUnknownFields is an Avro bytes field holding the unknown fields of the message.
+	ProtoMappingUnknownFields ProtoMappingKind = "unknownFields"
+	// ProtoMappingSkipped is an Avro field without a protobuf counterpart, or mapped to an
+	// ignored protobuf field. It is skipped on decode. On encode, a field mapped to an ignored
+	// protobuf field is written as its default, or zero value. A field without a counterpart
+	// is only written if its default is null, or if Config.ProtoWriteDefaultsForMissing is
+	// set, and encoding fails otherwise.
+	ProtoMappingSkipped ProtoMappingKind = "skipped"
+)
+
+// ProtoFieldMapping describes how an Avro record field maps to a protobuf message.
+type ProtoFieldMapping struct {
+	// AvroField is the name of the Avro field.
+	AvroField string
+	// ProtoField is the name of the protobuf field or oneof the Avro field maps to.
+	// It is empty for derived, unknown and skipped fields.
+	ProtoField string
+	// Kind is how the Avro field maps to the protobuf message.
+	Kind ProtoMappingKind
+}
+
+// ProtoMappingReport describes how an Avro record schema maps to a protobuf message,
+// as resolved by the protobuf codec.
+type ProtoMappingReport struct {
+	// Fields holds the mapping of the Avro fields, in schema order.
+	Fields []ProtoFieldMapping
+	// UnusedProtoFields holds the names of the protobuf fields not mapped by any Avro
+	// field, in message order. They are not encoded, and left unset on decode.
+	UnusedProtoFields []string
+}
+
+// DescribeProtoMapping returns how the fields of the record schema map to the protobuf
+// message, without encoding it. It fails with the error encoding would fail with if the
+// schema cannot be mapped to the message.
+func DescribeProtoMapping(schema Schema, msg proto.Message) (ProtoMappingReport, error) {
+	return DescribeProtoMappingWithAPI(DefaultConfig, schema, msg)
+}
+
+// DescribeProtoMappingWithAPI is like DescribeProtoMapping, but maps the fields as the
+// protobuf codec of api does, e.g. with its derived fields.
+func DescribeProtoMappingWithAPI(api API, schema Schema, msg proto.Message) (ProtoMappingReport, error) {
+	cfg, ok := frozenConfigOf(api)
+	if !ok {
+		return ProtoMappingReport{}, errors.New("avro: api must be created with Config.Freeze")
+	}
+	return cfg.describeProtoMapping(schema, msg)
+}
+
+func (c *frozenConfig) describeProtoMapping(schema Schema, msg proto.Message) (ProtoMappingReport, error) {
+	if schema.Type() == Ref {
+		schema = schema.(*RefSchema).Schema()
+	}
+	rec, ok := schema.(*RecordSchema)
+	if !ok {
+		return ProtoMappingReport{}, fmt.Errorf("avro: protobuf messages map to record schemas, got %s", schema.Type())
+	}
+	desc := msg.ProtoReflect().Descriptor()
+	plan, err := c.protoMessagePlanOf(rec, desc)
+	if err != nil {
+		return ProtoMappingReport{}, err
+	}
+
+	var report ProtoMappingReport
+	for _, fp := range plan.fields {
+		m := ProtoFieldMapping{AvroField: fp.avro.Name()}
+		switch fp.binding {
+		case protoFieldValue:
+			m.Kind, m.ProtoField = ProtoMappingField, string(fp.field.Name())
+		case protoFieldOneof:
+			m.Kind, m.ProtoField = ProtoMappingOneof, string(fp.oneof.Name())
+		case protoFieldPresence:
+			m.Kind, m.ProtoField = ProtoMappingPresence, string(fp.field.Name())
+		case protoFieldDerived:
+			m.Kind = ProtoMappingDerived
+		case protoFieldUnknown:
+			m.Kind = ProtoMappingUnknownFields
+		default:
+			m.Kind = ProtoMappingSkipped
+		}
+		report.Fields = append(report.Fields, m)
+	}
+
+	fields := desc.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		name := field.Name()
+		if oneof := field.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() {
+			name = oneof.Name()
+		}
+		if _, ok := plan.mapped[name]; !ok {
+			report.UnusedProtoFields = append(report.UnusedProtoFields, string(field.Name()))
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,147 @@
+package avro_test
+
+import (
+	"testing"
+
+	"github.com/hamba/avro/v2"
+	testpb "github.com/hamba/avro/v2/testdata/protobuf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDescribeProtoMapping_PartialSchema(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "score", "type": "double"}
+		]
+	}`)
+
+	got, err := avro.DescribeProtoMapping(schema, &testpb.BasicMessage{})
+
+	require.NoError(t, err)
+	want := avro.ProtoMappingReport{
+		Fields: []avro.ProtoFieldMapping{
+			{AvroField: "id", ProtoField: "id", Kind: avro.ProtoMappingField},
+			{AvroField: "score", ProtoField: "score", Kind: avro.ProtoMappingField},
+		},
+		UnusedProtoFields: []string{"name", "active"},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestDescribeProtoMapping_ExtraFields(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "OptionalMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "name", "type": "string"},
+			{"name": "has_name", "type": "boolean", "protoPresence": "name"},
+			{"name": "email", "type": ["null", "string"], "default": null}
+		]
+	}`)
+
+	got, err := avro.DescribeProtoMapping(schema, &testpb.OptionalMessage{})
+
+	require.NoError(t, err)
+	want := avro.ProtoMappingReport{
+		Fields: []avro.ProtoFieldMapping{
+			{AvroField: "id", ProtoField: "id", Kind: avro.ProtoMappingField},
+			{AvroField: "name", ProtoField: "name", Kind: avro.ProtoMappingField},
+			{AvroField: "has_name", ProtoField: "name", Kind: avro.ProtoMappingPresence},
+			{AvroField: "email", Kind: avro.ProtoMappingSkipped},
+		},
+		UnusedProtoFields: []string{"age"},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestDescribeProtoMapping_Oneof(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "OneofMessage",
+		"fields": [
+			{"name": "value", "type": ["null", "string", "int", "boolean"]}
+		]
+	}`)
+
+	got, err := avro.DescribeProtoMapping(schema, &testpb.OneofMessage{})
+
+	require.NoError(t, err)
+	want := avro.ProtoMappingReport{
+		Fields: []avro.ProtoFieldMapping{
+			{AvroField: "value", ProtoField: "value", Kind: avro.ProtoMappingOneof},
+		},
+		UnusedProtoFields: []string{"id"},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestDescribeProtoMapping_Error(t *testing.T) {
+	defer ConfigTeardown()
+
+	_, err := avro.DescribeProtoMapping(avro.MustParse(`"string"`), &testpb.BasicMessage{})
+	assert.EqualError(t, err, "avro: protobuf messages map to record schemas, got string")
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "OneofMessage",
+		"fields": [
+			{"name": "value", "type": "string"}
+		]
+	}`)
+
+	_, err = avro.DescribeProtoMapping(schema, &testpb.OneofMessage{})
+	assert.EqualError(t, err, "avro: oneof value of testpb.OneofMessage must map to a union, got string")
+}
+
+func TestDescribeProtoMappingWithAPI(t *testing.T) {
+	defer ConfigTeardown()
+
+	api := avro.Config{
+		ProtoDerivedFields: map[string]func(proto.Message) (any, error){
+			"testpb.BasicMessage.display": func(proto.Message) (any, error) { return "", nil },
+		},
+	}.Freeze()
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "display", "type": "string"}
+		]
+	}`)
+
+	got, err := avro.DescribeProtoMappingWithAPI(api, schema, &testpb.BasicMessage{})
+
+	require.NoError(t, err)
+	want := []avro.ProtoFieldMapping{
+		{AvroField: "id", ProtoField: "id", Kind: avro.ProtoMappingField},
+		{AvroField: "display", Kind: avro.ProtoMappingDerived},
+	}
+	assert.Equal(t, want, got.Fields)
+}
+
+func TestDescribeProtoMappingWithAPI_UnsupportedAPI(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{"type": "record", "name": "BasicMessage", "fields": [{"name": "id", "type": "int"}]}`)
+
+	_, err := avro.DescribeProtoMappingWithAPI(wrappedAPI{avro.DefaultConfig}, schema, &testpb.BasicMessage{})
+
+	assert.EqualError(t, err, "avro: api must be created with Config.Freeze")
+}
+
+type wrappedAPI struct {
+	avro.API
+}
@@ -2,12 +2,28 @@ package avro_test
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+	"unique"
+	"unsafe"
 
+	"github.com/ettle/strcase"
 	"github.com/hamba/avro/v2"
 	testpb "github.com/hamba/avro/v2/testdata/protobuf"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestProtobuf_BasicMessage_Encode(t *testing.T) {
@@ -80,6 +96,90 @@ func TestProtobuf_BasicMessage_Decode(t *testing.T) {
 	assert.Equal(t, 88.5, msg.Score)
 }
 
+func TestProtobuf_UnmarshalN_ConcatenatedRecords(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "name", "type": "string"},
+			{"name": "active", "type": "boolean"},
+			{"name": "score", "type": "double"}
+		]
+	}`)
+
+	want := []*testpb.BasicMessage{
+		{Id: 1, Name: "first", Active: true, Score: 1.5},
+		{Id: 2, Name: "second"},
+		{Id: 3, Name: "third", Score: -3},
+	}
+	var data []byte
+	for _, msg := range want {
+		b, err := avro.Marshal(schema, msg)
+		require.NoError(t, err)
+		data = append(data, b...)
+	}
+
+	var got []*testpb.BasicMessage
+	for len(data) > 0 {
+		var msg testpb.BasicMessage
+		n, err := avro.UnmarshalN(schema, data, &msg)
+		require.NoError(t, err)
+		require.Positive(t, n)
+		got = append(got, &msg)
+		data = data[n:]
+	}
+
+	require.Len(t, got, len(want))
+	for i := range want {
+		assert.True(t, proto.Equal(want[i], got[i]), "got %v, want %v", got[i], want[i])
+	}
+}
+
+func TestProtobuf_ConfluentDecoder(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "name", "type": "string"},
+			{"name": "active", "type": "boolean"},
+			{"name": "score", "type": "double"}
+		]
+	}`)
+
+	want := []*testpb.BasicMessage{
+		{Id: 1, Name: "first", Active: true, Score: 1.5},
+		{Id: 2, Name: "second"},
+	}
+	var data []byte
+	for _, msg := range want {
+		b, err := avro.Marshal(schema, msg)
+		require.NoError(t, err)
+		data = append(data, 0x0, 0x0, 0x0, 0x0, 0x7)
+		data = append(data, b...)
+	}
+
+	resolver := func(id uint32) (avro.Schema, error) {
+		if id != 7 {
+			return nil, errors.New("unknown schema id")
+		}
+		return schema, nil
+	}
+	dec := avro.NewConfluentDecoder(resolver, bytes.NewReader(data))
+
+	for _, w := range want {
+		var msg testpb.BasicMessage
+		err := dec.Decode(&msg)
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(w, &msg), "got %v, want %v", &msg, w)
+	}
+}
+
 func TestProtobuf_BasicMessage_RoundTrip(t *testing.T) {
 	defer ConfigTeardown()
 
@@ -114,6 +214,185 @@ func TestProtobuf_BasicMessage_RoundTrip(t *testing.T) {
 	assert.Equal(t, original.Score, decoded.Score)
 }
 
+func TestProtobuf_DisableProtobufCodec(t *testing.T) {
+	defer ConfigTeardown()
+
+	api := avro.Config{DisableProtobufCodec: true, TagKey: "json"}.Freeze()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "name", "type": "string"},
+			{"name": "active", "type": "boolean"},
+			{"name": "score", "type": "double"}
+		]
+	}`)
+
+	original := &testpb.BasicMessage{Id: 42, Name: "Jane Doe", Active: true, Score: 88.5}
+
+	want, err := avro.Marshal(schema, original)
+	require.NoError(t, err)
+	got, err := api.Marshal(schema, original)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	var decoded testpb.BasicMessage
+	err = api.Unmarshal(schema, got, &decoded)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(original, &decoded), "got %v, want %v", &decoded, original)
+
+	// Without the json tags, the native codec looks for the Go field names.
+	_, err = avro.Config{DisableProtobufCodec: true}.Freeze().Marshal(schema, original)
+	assert.Error(t, err)
+}
+
+func TestProtobuf_DecodeIntoMap(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "NestedMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "title", "type": "string"},
+			{
+				"name": "author",
+				"type": {
+					"type": "record",
+					"name": "BasicMessage",
+					"fields": [
+						{"name": "id", "type": "int"},
+						{"name": "name", "type": "string"},
+						{"name": "active", "type": "boolean"},
+						{"name": "score", "type": "double"}
+					]
+				}
+			}
+		]
+	}`)
+	data, err := avro.Marshal(schema, &testpb.NestedMessage{
+		Id:     1,
+		Title:  "My Article",
+		Author: &testpb.BasicMessage{Id: 42, Name: "Jane Doe", Active: true, Score: 88.5},
+	})
+	require.NoError(t, err)
+
+	var msg testpb.NestedMessage
+	err = avro.Unmarshal(schema, data, &msg)
+	require.NoError(t, err)
+
+	// Only protobuf messages are decoded by the protobuf codec, so the same data
+	// decodes into a generic map.
+	var m map[string]any
+	err = avro.Unmarshal(schema, data, &m)
+	require.NoError(t, err)
+
+	assert.Equal(t, int(msg.Id), m["id"])
+	assert.Equal(t, msg.Title, m["title"])
+	author := m["author"].(map[string]any)
+	assert.Equal(t, int(msg.Author.Id), author["id"])
+	assert.Equal(t, msg.Author.Name, author["name"])
+	assert.Equal(t, msg.Author.Active, author["active"])
+	assert.Equal(t, msg.Author.Score, author["score"])
+}
+
+func TestProtobuf_Proto2(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "Proto2Message",
+		"fields": [
+			{"name": "name", "type": "string"},
+			{"name": "count", "type": "int"},
+			{"name": "result", "type": ["null", {
+				"type": "record",
+				"name": "Result",
+				"fields": [
+					{"name": "url", "type": "string"},
+					{"name": "rank", "type": "int"}
+				]
+			}]}
+		]
+	}`)
+	original := &testpb.Proto2Message{
+		Name:   proto.String("query"),
+		Result: &testpb.Proto2Message_Result{Url: proto.String("https://example.com"), Rank: proto.Int32(3)},
+	}
+
+	data, err := avro.Marshal(schema, original)
+	require.NoError(t, err)
+
+	var decoded testpb.Proto2Message
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, "query", decoded.GetName())
+	// The unset count is written as its default.
+	assert.Equal(t, int32(7), decoded.GetCount())
+	assert.Equal(t, "https://example.com", decoded.GetResult().GetUrl())
+	assert.Equal(t, int32(3), decoded.GetResult().GetRank())
+}
+
+func TestProtobuf_Proto2_NullableDefault(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "Proto2Message",
+		"fields": [
+			{"name": "name", "type": "string"},
+			{"name": "count", "type": ["null", "int"]}
+		]
+	}`)
+	original := &testpb.Proto2Message{Name: proto.String("query")}
+
+	data, err := avro.Marshal(schema, original)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x0a, 'q', 'u', 'e', 'r', 'y', 0x00}, data)
+
+	var decoded testpb.Proto2Message
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	assert.True(t, proto.Equal(original, &decoded), "got %v, want %v", &decoded, original)
+	assert.Nil(t, decoded.Count)
+	assert.Equal(t, int32(7), decoded.GetCount())
+}
+
+func TestProtobuf_Proto2_RequiredNotSet(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "Proto2Message",
+		"fields": [
+			{"name": "name", "type": "string"},
+			{"name": "count", "type": "int"}
+		]
+	}`)
+
+	_, err := avro.Marshal(schema, &testpb.Proto2Message{Count: proto.Int32(1)})
+
+	assert.EqualError(t, err, "name: required protobuf field name is not set")
+
+	nullable := avro.MustParse(`{
+		"type": "record",
+		"name": "Proto2Message",
+		"fields": [
+			{"name": "name", "type": ["null", "string"]},
+			{"name": "count", "type": "int"}
+		]
+	}`)
+
+	data, err := avro.Marshal(nullable, &testpb.Proto2Message{Count: proto.Int32(1)})
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x00, 0x02}, data)
+}
+
 func TestProtobuf_NestedMessage_RoundTrip(t *testing.T) {
 	defer ConfigTeardown()
 
@@ -166,6 +445,84 @@ func TestProtobuf_NestedMessage_RoundTrip(t *testing.T) {
 	assert.Equal(t, original.Author.Score, decoded.Author.Score)
 }
 
+func TestProtobuf_MarshalTo(t *testing.T) {
+	defer ConfigTeardown()
+
+	msg := &testpb.NestedMessage{
+		Id:     1,
+		Title:  "My Article",
+		Author: &testpb.BasicMessage{Id: 42, Name: "Author Name", Active: true, Score: 99.9},
+	}
+	want, err := avro.Marshal(nestedMessageSchema, msg)
+	require.NoError(t, err)
+
+	buf := make([]byte, 0, 64)
+	for range 3 {
+		buf, err = avro.MarshalTo(nestedMessageSchema, msg, buf[:0])
+		require.NoError(t, err)
+		assert.Equal(t, want, buf)
+	}
+
+	var decoded testpb.NestedMessage
+	err = avro.Unmarshal(nestedMessageSchema, buf, &decoded)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(msg, &decoded))
+}
+
+func TestProtobuf_NestedMessage_WireBytes(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "NestedMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "author", "type": ["null", "bytes"]}
+		]
+	}`)
+
+	original := &testpb.NestedMessage{
+		Id:     1,
+		Author: &testpb.BasicMessage{Id: 42, Name: "Author Name", Active: true},
+	}
+
+	data, err := avro.Marshal(schema, original)
+	require.NoError(t, err)
+
+	var generic map[string]any
+	err = avro.Unmarshal(schema, data, &generic)
+	require.NoError(t, err)
+	var author testpb.BasicMessage
+	require.NoError(t, proto.Unmarshal(generic["author"].([]byte), &author))
+	assert.True(t, proto.Equal(original.Author, &author), "got %v, want %v", &author, original.Author)
+
+	var decoded testpb.NestedMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(original, &decoded), "got %v, want %v", &decoded, original)
+}
+
+func TestProtobuf_NestedMessage_WireBytesInvalid(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "NestedMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "author", "type": "bytes"}
+		]
+	}`)
+
+	// A truncated varint for field 1.
+	data, err := avro.Marshal(schema, map[string]any{"id": 1, "author": []byte{0x08, 0xff}})
+	require.NoError(t, err)
+
+	var decoded testpb.NestedMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	assert.ErrorContains(t, err, "author: protobuf field author bytes are not a valid testpb.BasicMessage message")
+}
+
 func TestProtobuf_ListMessage_RoundTrip(t *testing.T) {
 	defer ConfigTeardown()
 
@@ -228,6 +585,86 @@ func TestProtobuf_ListMessage_Empty(t *testing.T) {
 	assert.Empty(t, decoded.Numbers)
 }
 
+func TestProtobuf_ListMessage_UnionItems(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "ScalarValueList",
+		"fields": [
+			{"name": "values", "type": {"type": "array", "items": ["null", "string", "int"]}},
+			{"name": "attributes", "type": {"type": "map", "values": ["null", "string", "int"]}},
+			{"name": "names", "type": {"type": "array", "items": ["null", "string"]}}
+		]
+	}`)
+
+	original := &testpb.ScalarValueList{
+		Values: []*testpb.ScalarValue{
+			{Kind: &testpb.ScalarValue_StringValue{StringValue: "a"}},
+			{},
+			{Kind: &testpb.ScalarValue_IntValue{IntValue: 3}},
+		},
+		Attributes: map[string]*testpb.ScalarValue{
+			"k": {Kind: &testpb.ScalarValue_IntValue{IntValue: 1}},
+		},
+		Names: []string{"b"},
+	}
+
+	data, err := avro.Marshal(schema, original)
+	require.NoError(t, err)
+	want := []byte{
+		0x06, 0x02, 0x02, 'a', 0x00, 0x04, 0x06, 0x00, // values
+		0x02, 0x02, 'k', 0x04, 0x02, 0x00, // attributes
+		0x02, 0x02, 0x02, 'b', 0x00, // names
+	}
+	assert.Equal(t, want, data)
+
+	var decoded testpb.ScalarValueList
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	assert.True(t, proto.Equal(original, &decoded))
+}
+
+func TestProtobuf_ListMessage_UnionItemsNull(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "ScalarValueList",
+		"fields": [
+			{"name": "names", "type": {"type": "array", "items": ["null", "string"]}}
+		]
+	}`)
+
+	var decoded testpb.ScalarValueList
+	err := avro.Unmarshal(schema, []byte{0x02, 0x00, 0x00}, &decoded)
+
+	assert.EqualError(t, err, "avro: protobufCodec: names: cannot decode null to protobuf field names of type string")
+}
+
+func TestProtobuf_DecodeReadError(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "ScalarValueList",
+		"fields": [
+			{"name": "names", "type": {"type": "array", "items": ["null", "string"]}}
+		]
+	}`)
+
+	var decoded testpb.ScalarValueList
+	err := avro.Unmarshal(schema, []byte{0x02, 0x00, 0x00}, &decoded)
+
+	var readErr *avro.ReadError
+	require.ErrorAs(t, err, &readErr)
+	assert.Equal(t, "protobufCodec", readErr.Op)
+	assert.Equal(t, "names", readErr.Path)
+	assert.Equal(t, int64(2), readErr.Offset)
+	assert.EqualError(t, readErr.Err, "cannot decode null to protobuf field names of type string")
+}
+
 func TestProtobuf_MapMessage_RoundTrip(t *testing.T) {
 	defer ConfigTeardown()
 
@@ -419,6 +856,39 @@ func TestProtobuf_EnumMessage_AsString_RoundTrip(t *testing.T) {
 	assert.Equal(t, original.Status, decoded.Status)
 }
 
+func TestProtobuf_EnumMessage_AsString_UnknownNumber(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "EnumMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "status", "type": "string"}
+		]
+	}`)
+	msg := &testpb.EnumMessage{Id: 1, Status: testpb.Status(42)}
+
+	_, err := avro.Marshal(schema, msg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid enum number 42 for field status")
+
+	api := avro.Config{ProtoUnknownEnumAsNumber: true}.Freeze()
+
+	data, err := api.Marshal(schema, msg)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x02, 0x04, '4', '2'}, data)
+
+	var got testpb.EnumMessage
+	err = api.Unmarshal(schema, data, &got)
+	require.NoError(t, err)
+	assert.Equal(t, testpb.Status(42), got.Status)
+
+	err = avro.Unmarshal(schema, data, &got)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown enum value 42 for field status")
+}
+
 func TestProtobuf_Encoder_BasicMessage(t *testing.T) {
 	defer ConfigTeardown()
 
@@ -755,25 +1225,87 @@ func TestProtobuf_OptionalMessage_RoundTrip(t *testing.T) {
 	}
 }
 
-func TestProtobuf_OneofMessage_Text(t *testing.T) {
+func TestProtobuf_OptionalMessageField(t *testing.T) {
 	defer ConfigTeardown()
 
-	schema := `{
+	schema := avro.MustParse(`{
 		"type": "record",
-		"name": "OneofMessage",
+		"name": "OptionalAuthorMessage",
 		"fields": [
 			{"name": "id", "type": "int"},
-			{"name": "value", "type": ["null", "string", "int", "boolean"]}
+			{
+				"name": "author",
+				"type": ["null", {
+					"type": "record",
+					"name": "BasicMessage",
+					"fields": [
+						{"name": "id", "type": "int"},
+						{"name": "name", "type": "string"},
+						{"name": "active", "type": "boolean"},
+						{"name": "score", "type": "double"}
+					]
+				}]
+			}
 		]
-	}`
-
-	original := &testpb.OneofMessage{
-		Id:    1,
-		Value: &testpb.OneofMessage_Text{Text: "hello"},
-	}
+	}`)
 
-	data, err := avro.Marshal(avro.MustParse(schema), original)
-	require.NoError(t, err)
+	tests := []struct {
+		name     string
+		msg      *testpb.OptionalAuthorMessage
+		wantData []byte
+	}{
+		{
+			name:     "nil",
+			msg:      &testpb.OptionalAuthorMessage{Id: 1},
+			wantData: []byte{0x02, 0x00},
+		},
+		{
+			name:     "empty",
+			msg:      &testpb.OptionalAuthorMessage{Id: 1, Author: &testpb.BasicMessage{}},
+			wantData: []byte{0x02, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+		{
+			name:     "set",
+			msg:      &testpb.OptionalAuthorMessage{Id: 1, Author: &testpb.BasicMessage{Id: 2, Name: "a", Active: true}},
+			wantData: []byte{0x02, 0x02, 0x04, 0x02, 'a', 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := avro.Marshal(schema, test.msg)
+			require.NoError(t, err)
+			assert.Equal(t, test.wantData, data)
+
+			var decoded testpb.OptionalAuthorMessage
+			err = avro.Unmarshal(schema, data, &decoded)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.msg.Author != nil, decoded.Author != nil)
+			assert.True(t, proto.Equal(test.msg, &decoded))
+		})
+	}
+}
+
+func TestProtobuf_OneofMessage_Text(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := `{
+		"type": "record",
+		"name": "OneofMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "value", "type": ["null", "string", "int", "boolean"]}
+		]
+	}`
+
+	original := &testpb.OneofMessage{
+		Id:    1,
+		Value: &testpb.OneofMessage_Text{Text: "hello"},
+	}
+
+	data, err := avro.Marshal(avro.MustParse(schema), original)
+	require.NoError(t, err)
 
 	var decoded testpb.OneofMessage
 	err = avro.Unmarshal(avro.MustParse(schema), data, &decoded)
@@ -1267,3 +1799,3948 @@ func TestProtobuf_OneofWithMessageMessage_Profile(t *testing.T) {
 	assert.Equal(t, "Software Developer", profileValue.Profile.Bio)
 	assert.Equal(t, int32(1500), profileValue.Profile.Followers)
 }
+
+func TestProtobuf_EnumMessage_AsEnum_RoundTrip(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "EnumMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["STATUS_UNSPECIFIED", "STATUS_ACTIVE", "STATUS_INACTIVE"]}}
+		]
+	}`)
+
+	original := &testpb.EnumMessage{
+		Id:     1,
+		Status: testpb.Status_STATUS_INACTIVE,
+	}
+
+	data, err := avro.Marshal(schema, original)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x02, 0x04}, data)
+
+	var decoded testpb.EnumMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, original.Id, decoded.Id)
+	assert.Equal(t, original.Status, decoded.Status)
+}
+
+func TestProtobuf_EnumMessage_StripPrefix_AsEnum_RoundTrip(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "EnumMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["UNSPECIFIED", "ACTIVE", "INACTIVE"]}}
+		]
+	}`)
+	api := avro.Config{ProtoEnumStripPrefix: true}.Freeze()
+
+	original := &testpb.EnumMessage{
+		Id:     1,
+		Status: testpb.Status_STATUS_ACTIVE,
+	}
+
+	data, err := api.Marshal(schema, original)
+	require.NoError(t, err)
+
+	var got map[string]any
+	err = api.Unmarshal(schema, data, &got)
+	require.NoError(t, err)
+	assert.Equal(t, "ACTIVE", got["status"])
+
+	var decoded testpb.EnumMessage
+	err = api.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, original.Id, decoded.Id)
+	assert.Equal(t, original.Status, decoded.Status)
+}
+
+func TestProtobuf_EnumMessage_StripPrefix_AsString_RoundTrip(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "EnumMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "status", "type": "string"}
+		]
+	}`)
+	api := avro.Config{ProtoEnumStripPrefix: true}.Freeze()
+
+	original := &testpb.EnumMessage{
+		Id:     1,
+		Status: testpb.Status_STATUS_INACTIVE,
+	}
+
+	data, err := api.Marshal(schema, original)
+	require.NoError(t, err)
+
+	var got map[string]any
+	err = api.Unmarshal(schema, data, &got)
+	require.NoError(t, err)
+	assert.Equal(t, "INACTIVE", got["status"])
+
+	var decoded testpb.EnumMessage
+	err = api.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, original.Id, decoded.Id)
+	assert.Equal(t, original.Status, decoded.Status)
+}
+
+func TestProtobuf_EnumMessage_StripPrefix_UnknownSymbol(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "EnumMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["UNSPECIFIED", "ACTIVE", "INACTIVE"]}}
+		]
+	}`)
+
+	msg := &testpb.EnumMessage{
+		Id:     1,
+		Status: testpb.Status_STATUS_ACTIVE,
+	}
+
+	_, err := avro.Marshal(schema, msg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown enum symbol STATUS_ACTIVE")
+}
+
+func TestProtobuf_EnumMessage_SymbolFunc(t *testing.T) {
+	defer ConfigTeardown()
+
+	tests := []struct {
+		name    string
+		config  avro.Config
+		symbols []string
+	}{
+		{
+			name:    "lower case",
+			config:  avro.Config{ProtoEnumStripPrefix: true, ProtoEnumSymbolFunc: strings.ToLower},
+			symbols: []string{"unspecified", "active", "inactive"},
+		},
+		{
+			name:    "pascal case",
+			config:  avro.Config{ProtoEnumSymbolFunc: strcase.ToPascal},
+			symbols: []string{"StatusUnspecified", "StatusActive", "StatusInactive"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			api := test.config.Freeze()
+			enum := `{"type": "enum", "name": "Status", "symbols": ["` + strings.Join(test.symbols, `", "`) + `"]}`
+			for _, typ := range []string{enum, `"string"`} {
+				schema := avro.MustParse(`{
+					"type": "record",
+					"name": "EnumMessage",
+					"fields": [
+						{"name": "status", "type": ` + typ + `}
+					]
+				}`)
+
+				data, err := api.Marshal(schema, &testpb.EnumMessage{Status: testpb.Status_STATUS_ACTIVE})
+				require.NoError(t, err)
+
+				var got map[string]any
+				err = api.Unmarshal(schema, data, &got)
+				require.NoError(t, err)
+				assert.Equal(t, test.symbols[1], got["status"])
+
+				var decoded testpb.EnumMessage
+				err = api.Unmarshal(schema, data, &decoded)
+				require.NoError(t, err)
+				assert.Equal(t, testpb.Status_STATUS_ACTIVE, decoded.Status)
+			}
+		})
+	}
+}
+
+func TestProtobuf_EnumMapMessage_AsInt_RoundTrip(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "EnumMapMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "statuses", "type": {"type": "map", "values": "int"}}
+		]
+	}`)
+
+	original := &testpb.EnumMapMessage{
+		Id: 1,
+		Statuses: map[string]testpb.Status{
+			"alice": testpb.Status_STATUS_ACTIVE,
+			"bob":   testpb.Status_STATUS_INACTIVE,
+		},
+	}
+
+	data, err := avro.Marshal(schema, original)
+	require.NoError(t, err)
+
+	var got map[string]any
+	err = avro.Unmarshal(schema, data, &got)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"alice": 1, "bob": 2}, got["statuses"])
+
+	var decoded testpb.EnumMapMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, original.Id, decoded.Id)
+	assert.Equal(t, original.Statuses, decoded.Statuses)
+}
+
+func TestProtobuf_EnumMapMessage_AsString_RoundTrip(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "EnumMapMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "statuses", "type": {"type": "map", "values": "string"}}
+		]
+	}`)
+
+	original := &testpb.EnumMapMessage{
+		Id: 1,
+		Statuses: map[string]testpb.Status{
+			"alice": testpb.Status_STATUS_ACTIVE,
+			"bob":   testpb.Status_STATUS_INACTIVE,
+			"carol": testpb.Status_STATUS_UNSPECIFIED,
+		},
+	}
+
+	data, err := avro.Marshal(schema, original)
+	require.NoError(t, err)
+
+	var got map[string]any
+	err = avro.Unmarshal(schema, data, &got)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"alice": "STATUS_ACTIVE",
+		"bob":   "STATUS_INACTIVE",
+		"carol": "STATUS_UNSPECIFIED",
+	}, got["statuses"])
+
+	var decoded testpb.EnumMapMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, original.Id, decoded.Id)
+	assert.Equal(t, original.Statuses, decoded.Statuses)
+}
+
+func TestProtobuf_EnumMapMessage_UnknownSymbol(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "EnumMapMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "statuses", "type": {"type": "map", "values": "string"}}
+		]
+	}`)
+
+	data, err := avro.Marshal(schema, map[string]any{
+		"id":       int32(1),
+		"statuses": map[string]any{"alice": "STATUS_UNKNOWN"},
+	})
+	require.NoError(t, err)
+
+	var decoded testpb.EnumMapMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown enum value STATUS_UNKNOWN")
+}
+
+func TestProtobuf_DisallowUnmappedProtoFields(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "name", "type": "string"},
+			{"name": "active", "type": "boolean"}
+		]
+	}`)
+	api := avro.Config{DisallowUnmappedProtoFields: true}.Freeze()
+
+	msg := &testpb.BasicMessage{
+		Id:     1,
+		Name:   "Test",
+		Active: true,
+		Score:  12.5,
+	}
+
+	_, err := api.Marshal(schema, msg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "protobuf field score is set but not mapped")
+
+	// The same message is encoded when the flag is not set.
+	_, err = avro.Marshal(schema, msg)
+	require.NoError(t, err)
+}
+
+func TestProtobuf_DisallowUnmappedProtoFields_UnsetFieldAllowed(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "name", "type": "string"},
+			{"name": "active", "type": "boolean"}
+		]
+	}`)
+	api := avro.Config{DisallowUnmappedProtoFields: true}.Freeze()
+
+	msg := &testpb.BasicMessage{
+		Id:   1,
+		Name: "Test",
+	}
+
+	_, err := api.Marshal(schema, msg)
+	require.NoError(t, err)
+}
+
+func TestProtobuf_DisallowUnmappedProtoFields_Oneof(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "OneofMessage",
+		"fields": [
+			{"name": "id", "type": "int"}
+		]
+	}`)
+	api := avro.Config{DisallowUnmappedProtoFields: true}.Freeze()
+
+	msg := &testpb.OneofMessage{
+		Id:    1,
+		Value: &testpb.OneofMessage_Text{Text: "hello"},
+	}
+
+	_, err := api.Marshal(schema, msg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "protobuf field text is set but not mapped")
+}
+
+func TestProtobuf_IgnoredField(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "IgnoredFieldMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "secret", "type": ["null", "string"]}
+		]
+	}`)
+
+	data, err := avro.Marshal(schema, &testpb.IgnoredFieldMessage{Id: 1, Secret: "s3cr3t"})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x02, 0x00}, data)
+
+	data, err = avro.Marshal(schema, map[string]any{"id": 1, "secret": map[string]any{"string": "s3cr3t"}})
+	require.NoError(t, err)
+
+	var decoded testpb.IgnoredFieldMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	want := &testpb.IgnoredFieldMessage{Id: 1}
+	assert.True(t, proto.Equal(want, &decoded), "got %v, want %v", &decoded, want)
+}
+
+func TestProtobuf_IgnoredField_DisallowUnmapped(t *testing.T) {
+	defer ConfigTeardown()
+
+	api := avro.Config{DisallowUnmappedProtoFields: true}.Freeze()
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "IgnoredFieldMessage",
+		"fields": [
+			{"name": "id", "type": "int"}
+		]
+	}`)
+
+	data, err := api.Marshal(schema, &testpb.IgnoredFieldMessage{Id: 1, Secret: "s3cr3t"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x02}, data)
+}
+
+func TestProtobuf_UnionNarrowing_IntBranch(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "AllTypesMessage",
+		"fields": [
+			{"name": "int64_field", "type": ["int", "long"]},
+			{"name": "uint64_field", "type": ["long", "int"]}
+		]
+	}`)
+
+	msg := &testpb.AllTypesMessage{
+		Int64Field:  -5,
+		Uint64Field: 7,
+	}
+
+	data, err := avro.Marshal(schema, msg)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x00, 0x09, 0x02, 0x0e}, data)
+
+	var got map[string]any
+	err = avro.Unmarshal(schema, data, &got)
+	require.NoError(t, err)
+	assert.Equal(t, -5, got["int64_field"])
+	assert.Equal(t, 7, got["uint64_field"])
+}
+
+func TestProtobuf_UnionNarrowing_LongBranch(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "AllTypesMessage",
+		"fields": [
+			{"name": "int64_field", "type": ["int", "long"]},
+			{"name": "uint64_field", "type": ["long", "int"]}
+		]
+	}`)
+
+	msg := &testpb.AllTypesMessage{
+		Int64Field:  math.MinInt64,
+		Uint64Field: math.MaxInt32 + 1,
+	}
+
+	data, err := avro.Marshal(schema, msg)
+	require.NoError(t, err)
+
+	var got map[string]any
+	err = avro.Unmarshal(schema, data, &got)
+	require.NoError(t, err)
+	assert.Equal(t, int64(math.MinInt64), got["int64_field"])
+	assert.Equal(t, int64(math.MaxInt32+1), got["uint64_field"])
+}
+
+func TestProtobuf_UnionNarrowing_OnlyLongBranch(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "AllTypesMessage",
+		"fields": [
+			{"name": "int64_field", "type": ["string", "long"]}
+		]
+	}`)
+
+	msg := &testpb.AllTypesMessage{Int64Field: 3}
+
+	data, err := avro.Marshal(schema, msg)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x02, 0x06}, data)
+}
+
+func TestProtobuf_UnionNarrowing_NoMatchingBranch(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "AllTypesMessage",
+		"fields": [
+			{"name": "int64_field", "type": ["string", "boolean"]}
+		]
+	}`)
+
+	msg := &testpb.AllTypesMessage{Int64Field: 3}
+
+	_, err := avro.Marshal(schema, msg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no matching union type found for protobuf field int64_field")
+}
+
+func TestProtobuf_IntMapMessage_RoundTrip(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "IntMapMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "counts", "type": {"type": "map", "values": "int"}},
+			{"name": "names", "type": {"type": "map", "values": "string"}},
+			{"name": "codes", "type": {"type": "map", "values": "string"}},
+			{"name": "flags", "type": {"type": "map", "values": "string"}}
+		]
+	}`)
+
+	original := &testpb.IntMapMessage{
+		Id: 1,
+		Counts: map[int64]int32{
+			-9223372036854775808: 1,
+			-42:                  2,
+			0:                    3,
+			9223372036854775807:  4,
+		},
+		Names: map[int32]string{-1: "minus one", 2: "two"},
+		Codes: map[uint32]string{4294967295: "max"},
+		Flags: map[bool]string{true: "yes", false: "no"},
+	}
+
+	data, err := avro.Marshal(schema, original)
+	require.NoError(t, err)
+
+	var got map[string]any
+	err = avro.Unmarshal(schema, data, &got)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"-9223372036854775808": 1,
+		"-42":                  2,
+		"0":                    3,
+		"9223372036854775807":  4,
+	}, got["counts"])
+	assert.Equal(t, map[string]any{"true": "yes", "false": "no"}, got["flags"])
+
+	var decoded testpb.IntMapMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, original.Id, decoded.Id)
+	assert.Equal(t, original.Counts, decoded.Counts)
+	assert.Equal(t, original.Names, decoded.Names)
+	assert.Equal(t, original.Codes, decoded.Codes)
+	assert.Equal(t, original.Flags, decoded.Flags)
+}
+
+func TestProtobuf_SortMapKeys(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "IntMapMessage",
+		"fields": [
+			{"name": "names", "type": {"type": "map", "values": "string"}},
+			{"name": "flags", "type": {"type": "map", "values": "string"}}
+		]
+	}`)
+
+	original := &testpb.IntMapMessage{
+		Names: map[int32]string{10: "ten", 2: "two", -5: "minus five", 100: "hundred", 1: "one"},
+		Flags: map[bool]string{true: "yes", false: "no"},
+	}
+
+	api := avro.Config{SortMapKeys: true}.Freeze()
+
+	for range 5 {
+		data, err := api.Marshal(schema, original)
+		require.NoError(t, err)
+
+		var names, flags []string
+		r := avro.NewReader(bytes.NewReader(data), 64)
+		err = r.ReadMapCallback(func(r *avro.Reader, key string) error {
+			names = append(names, key)
+			r.SkipString()
+			return nil
+		})
+		require.NoError(t, err)
+		err = r.ReadMapCallback(func(r *avro.Reader, key string) error {
+			flags = append(flags, key)
+			r.SkipString()
+			return nil
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"-5", "1", "2", "10", "100"}, names)
+		assert.Equal(t, []string{"false", "true"}, flags)
+	}
+}
+
+func TestProtobuf_IntMapMessage_InvalidKey(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "IntMapMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "counts", "type": {"type": "map", "values": "int"}}
+		]
+	}`)
+
+	data, err := avro.Marshal(schema, map[string]any{
+		"id":     int32(1),
+		"counts": map[string]any{"not-a-number": int32(1)},
+	})
+	require.NoError(t, err)
+
+	var decoded testpb.IntMapMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid int64 map key "not-a-number"`)
+}
+
+func TestProtobuf_OneofMessage_UnionWithoutNull(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "OneofMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "value", "type": ["string", "int", "boolean"]}
+		]
+	}`)
+
+	// The oneof is set, but the schema is still rejected as it cannot represent an unset oneof.
+	msg := &testpb.OneofMessage{
+		Id:    1,
+		Value: &testpb.OneofMessage_Text{Text: "hello"},
+	}
+
+	_, err := avro.Marshal(schema, msg)
+	require.Error(t, err)
+	assert.Equal(t, "avro: oneof value of testpb.OneofMessage must map to a union with a null branch", err.Error())
+
+	data, err := avro.Marshal(schema, map[string]any{"id": int32(1), "value": "hello"})
+	require.NoError(t, err)
+
+	var decoded testpb.OneofMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.Error(t, err)
+	assert.Equal(t, "avro: oneof value of testpb.OneofMessage must map to a union with a null branch", err.Error())
+}
+
+func TestProtobuf_OneofMessage_MemberNotInUnion(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "OneofMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "value", "type": ["null", "string", "boolean"]}
+		]
+	}`)
+
+	msg := &testpb.OneofMessage{Id: 1, Value: &testpb.OneofMessage_Number{Number: 3}}
+
+	buf := bytes.NewBuffer([]byte{})
+	enc := avro.NewEncoderForSchema(schema, buf)
+	err := enc.Encode(msg)
+
+	assert.EqualError(t, err, "value: no matching union type found for oneof field number")
+	assert.Empty(t, buf.Bytes())
+}
+
+func TestProtobuf_OneofMessage_NonUnion(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "OneofMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "value", "type": "string"}
+		]
+	}`)
+
+	msg := &testpb.OneofMessage{
+		Id:    1,
+		Value: &testpb.OneofMessage_Text{Text: "hello"},
+	}
+
+	_, err := avro.Marshal(schema, msg)
+	require.Error(t, err)
+	assert.Equal(t, "avro: oneof value of testpb.OneofMessage must map to a union, got string", err.Error())
+}
+
+func TestProtobuf_MatchJSONName_RoundTrip(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "AllTypesMessage",
+		"fields": [
+			{"name": "int32Field", "type": "int"},
+			{"name": "int64Field", "type": "long"},
+			{"name": "string_field", "type": "string"},
+			{"name": "boolField", "type": "boolean"}
+		]
+	}`)
+	api := avro.Config{ProtoMatchJSONName: true}.Freeze()
+
+	original := &testpb.AllTypesMessage{
+		Int32Field:  12,
+		Int64Field:  34,
+		StringField: "hello",
+		BoolField:   true,
+	}
+
+	data, err := api.Marshal(schema, original)
+	require.NoError(t, err)
+
+	var got map[string]any
+	err = api.Unmarshal(schema, data, &got)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"int32Field":   12,
+		"int64Field":   int64(34),
+		"string_field": "hello",
+		"boolField":    true,
+	}, got)
+
+	var decoded testpb.AllTypesMessage
+	err = api.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, original.Int32Field, decoded.Int32Field)
+	assert.Equal(t, original.Int64Field, decoded.Int64Field)
+	assert.Equal(t, original.StringField, decoded.StringField)
+	assert.Equal(t, original.BoolField, decoded.BoolField)
+}
+
+func TestProtobuf_MatchJSONName_Disabled(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "AllTypesMessage",
+		"fields": [
+			{"name": "int32Field", "type": "int"}
+		]
+	}`)
+
+	msg := &testpb.AllTypesMessage{Int32Field: 12}
+
+	_, err := avro.Marshal(schema, msg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "required field int32Field not found in protobuf message")
+}
+
+func TestProtobuf_MatchJSONName_NestedMessage(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "OneofWithMessageMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{
+				"name": "data",
+				"type": [
+					"null",
+					{
+						"type": "record",
+						"name": "SimpleProfile",
+						"fields": [
+							{"name": "userId", "type": "int"},
+							{"name": "bio", "type": "string"}
+						]
+					}
+				]
+			}
+		]
+	}`)
+	api := avro.Config{ProtoMatchJSONName: true}.Freeze()
+
+	original := &testpb.OneofWithMessageMessage{
+		Id: 1,
+		Data: &testpb.OneofWithMessageMessage_Profile{
+			Profile: &testpb.SimpleProfile{UserId: 100, Bio: "Developer"},
+		},
+	}
+
+	data, err := api.Marshal(schema, original)
+	require.NoError(t, err)
+
+	var decoded testpb.OneofWithMessageMessage
+	err = api.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	require.NotNil(t, decoded.GetProfile())
+	assert.Equal(t, int32(100), decoded.GetProfile().UserId)
+	assert.Equal(t, "Developer", decoded.GetProfile().Bio)
+}
+
+func TestProtobuf_FieldAliases(t *testing.T) {
+	defer ConfigTeardown()
+
+	// The protobuf field title was renamed to name, which the schema keeps as an alias.
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "title", "aliases": ["name"], "type": "string"}
+		]
+	}`)
+
+	data, err := avro.Marshal(schema, map[string]any{"id": 7, "title": "Jane"})
+	require.NoError(t, err)
+
+	var decoded testpb.BasicMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	want := &testpb.BasicMessage{Id: 7, Name: "Jane"}
+	assert.True(t, proto.Equal(want, &decoded), "got %v, want %v", &decoded, want)
+
+	got, err := avro.Marshal(schema, want)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+}
+
+var treeNodeSchema = `{
+	"type": "record",
+	"name": "TreeNode",
+	"fields": [
+		{"name": "value", "type": "int"},
+		{"name": "children", "type": {"type": "array", "items": "TreeNode"}},
+		{"name": "left", "type": ["null", "TreeNode"], "default": null}
+	]
+}`
+
+func newTreeNodeChain(depth int) *testpb.TreeNode {
+	root := &testpb.TreeNode{Value: 0}
+	node := root
+	for i := 1; i <= depth; i++ {
+		child := &testpb.TreeNode{Value: int32(i)}
+		if i%2 == 0 {
+			node.Children = []*testpb.TreeNode{child}
+		} else {
+			node.Left = child
+		}
+		node = child
+	}
+	return root
+}
+
+func TestProtobuf_RecursiveMessage_RoundTrip(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(treeNodeSchema)
+
+	original := newTreeNodeChain(5)
+
+	data, err := avro.Marshal(schema, original)
+	require.NoError(t, err)
+
+	var decoded testpb.TreeNode
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	assert.True(t, proto.Equal(original, &decoded))
+}
+
+var deepMessageSchema = `{
+	"type": "record",
+	"name": "DeepMessage",
+	"fields": [
+		{"name": "id", "type": "int"},
+		{"name": "child", "type": {
+			"type": "record",
+			"name": "DeepLevel2",
+			"fields": [
+				{"name": "id", "type": "int"},
+				{"name": "child", "type": {
+					"type": "record",
+					"name": "DeepLevel3",
+					"fields": [
+						{"name": "id", "type": "int"},
+						{"name": "child", "type": {
+							"type": "record",
+							"name": "DeepLevel4",
+							"fields": [
+								{"name": "id", "type": "int"},
+								{"name": "child", "type": {
+									"type": "record",
+									"name": "DeepLevel5",
+									"fields": [
+										{"name": "id", "type": "int"},
+										{"name": "name", "type": "string"}
+									]
+								}}
+							]
+						}}
+					]
+				}}
+			]
+		}}
+	]
+}`
+
+func TestProtobuf_DeepMessage_RoundTrip(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(deepMessageSchema)
+	original := &testpb.DeepMessage{
+		Id: 1,
+		Child: &testpb.DeepLevel2{
+			Id: 2,
+			Child: &testpb.DeepLevel3{
+				Id: 3,
+				Child: &testpb.DeepLevel4{
+					Id:    4,
+					Child: &testpb.DeepLevel5{Id: 5, Name: "leaf"},
+				},
+			},
+		},
+	}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for range 100 {
+				data, err := avro.Marshal(schema, original)
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				var decoded testpb.DeepMessage
+				err = avro.Unmarshal(schema, data, &decoded)
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.True(t, proto.Equal(original, &decoded), "got %v, want %v", &decoded, original)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestProtobuf_ErrorFieldPath(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "DeepMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "child", "type": {
+				"type": "record",
+				"name": "DeepLevel2",
+				"fields": [
+					{"name": "id", "type": "int"},
+					{"name": "child", "type": ["null", {
+						"type": "record",
+						"name": "DeepLevel3",
+						"fields": [
+							{"name": "id", "type": "string"}
+						]
+					}]}
+				]
+			}}
+		]
+	}`)
+
+	data, err := avro.Marshal(schema, map[string]any{
+		"id": 1,
+		"child": map[string]any{
+			"id":    2,
+			"child": map[string]any{"DeepLevel3": map[string]any{"id": "three"}},
+		},
+	})
+	require.NoError(t, err)
+
+	var decoded testpb.DeepMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	assert.ErrorContains(t, err, "child.child.id: cannot decode string to protobuf field id of type int32")
+
+	_, err = avro.Marshal(schema, &testpb.DeepMessage{
+		Id:    1,
+		Child: &testpb.DeepLevel2{Id: 2, Child: &testpb.DeepLevel3{Id: 3}},
+	})
+	assert.ErrorContains(t, err, "child.child.id: cannot encode protobuf field id of type int32 to string")
+}
+
+func TestProtobuf_MaxRecursionDepth_Decode(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(treeNodeSchema)
+
+	data, err := avro.Config{ProtoMaxRecursionDepth: -1}.Freeze().Marshal(schema, newTreeNodeChain(20))
+	require.NoError(t, err)
+
+	api := avro.Config{ProtoMaxRecursionDepth: 10}.Freeze()
+
+	var decoded testpb.TreeNode
+	err = api.Unmarshal(schema, data, &decoded)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max protobuf recursion depth 10 exceeded")
+}
+
+func TestProtobuf_MaxRecursionDepth_Encode(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(treeNodeSchema)
+	api := avro.Config{ProtoMaxRecursionDepth: 10}.Freeze()
+
+	_, err := api.Marshal(schema, newTreeNodeChain(20))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max protobuf recursion depth 10 exceeded")
+}
+
+func TestProtobuf_MaxRecursionDepth_EncodeCycle(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(treeNodeSchema)
+
+	node := &testpb.TreeNode{Value: 1}
+	node.Left = node
+
+	_, err := avro.Marshal(schema, node)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max protobuf recursion depth")
+}
+
+func TestProtobuf_BytesField_CopiesByDefault(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "AllTypesMessage",
+		"fields": [
+			{"name": "bytes_field", "type": "bytes"}
+		]
+	}`)
+
+	data := []byte{0x06, 'f', 'o', 'o'}
+
+	var decoded testpb.AllTypesMessage
+	err := avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+	require.Equal(t, []byte("foo"), decoded.BytesField)
+
+	data[1] = 'b'
+	assert.Equal(t, []byte("foo"), decoded.BytesField)
+}
+
+func TestProtobuf_BytesField_ZeroCopy(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "AllTypesMessage",
+		"fields": [
+			{"name": "bytes_field", "type": "bytes"}
+		]
+	}`)
+	api := avro.Config{ProtoZeroCopyBytes: true}.Freeze()
+
+	data := []byte{0x06, 'f', 'o', 'o'}
+
+	var decoded testpb.AllTypesMessage
+	err := api.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+	require.Equal(t, []byte("foo"), decoded.BytesField)
+	assert.Equal(t, 3, cap(decoded.BytesField))
+
+	data[1] = 'b'
+	assert.Equal(t, []byte("boo"), decoded.BytesField)
+}
+
+func TestDescribeOneofBindings(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "OneofWithMessageMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{
+				"name": "data",
+				"type": [
+					"null",
+					"string",
+					{
+						"type": "record",
+						"name": "SimpleProfile",
+						"fields": [
+							{"name": "user_id", "type": "int"},
+							{"name": "bio", "type": "string"}
+						]
+					}
+				]
+			}
+		]
+	}`)
+
+	got, err := avro.DescribeOneofBindings(schema, &testpb.OneofWithMessageMessage{})
+
+	require.NoError(t, err)
+	want := []avro.OneofBinding{
+		{
+			Oneof: "data",
+			Field: "data",
+			Branches: map[string]int{
+				"description": 1,
+				"user":        -1,
+				"profile":     2,
+			},
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestDescribeOneofBindings_NonRecordSchema(t *testing.T) {
+	defer ConfigTeardown()
+
+	_, err := avro.DescribeOneofBindings(avro.MustParse(`"string"`), &testpb.OneofWithMessageMessage{})
+
+	assert.Error(t, err)
+}
+
+func TestDescribeOneofBindingsWithAPI(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "CounterMessage",
+		"fields": [
+			{"name": "value", "type": ["null", "string", "boolean"]}
+		]
+	}`)
+
+	got, err := avro.DescribeOneofBindings(schema, &testpb.CounterMessage{})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, map[string]int{"count": -1, "unset": 2}, got[0].Branches)
+
+	api := avro.Config{ProtoInt64AsString: true}.Freeze()
+	got, err = avro.DescribeOneofBindingsWithAPI(api, schema, &testpb.CounterMessage{})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, map[string]int{"count": 1, "unset": 2}, got[0].Branches)
+
+	_, err = avro.DescribeOneofBindingsWithAPI(wrappedAPI{api}, schema, &testpb.CounterMessage{})
+	assert.EqualError(t, err, "avro: api must be created with Config.Freeze")
+}
+
+var identifierMessageSchema = `{
+	"type": "record",
+	"name": "IdentifierMessage",
+	"fields": [
+		{"name": "id", "type": "int"},
+		{"name": "uuid", "type": {"type": "fixed", "name": "UUID", "size": 16}},
+		{"name": "key", "type": ["null", "string", {"type": "fixed", "name": "Hash", "size": 4}]}
+	]
+}`
+
+func TestProtobuf_FixedField_RoundTrip(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(identifierMessageSchema)
+
+	uuid := []byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+	original := &testpb.IdentifierMessage{
+		Id:   1,
+		Uuid: uuid,
+		Key:  &testpb.IdentifierMessage_Hash{Hash: []byte{0xde, 0xad, 0xbe, 0xef}},
+	}
+
+	data, err := avro.Marshal(schema, original)
+	require.NoError(t, err)
+
+	want := append([]byte{0x02}, uuid...)
+	want = append(want, 0x04, 0xde, 0xad, 0xbe, 0xef)
+	assert.Equal(t, want, data)
+
+	var decoded testpb.IdentifierMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), decoded.Id)
+	assert.Equal(t, uuid, decoded.Uuid)
+	assert.Equal(t, []byte{0xde, 0xad, 0xbe, 0xef}, decoded.GetHash())
+}
+
+func TestProtobuf_FixedField_WrongSize(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(identifierMessageSchema)
+
+	msg := &testpb.IdentifierMessage{Id: 1, Uuid: []byte{0x01, 0x02}}
+
+	_, err := avro.Marshal(schema, msg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "protobuf field uuid has 2 bytes, expected fixed size 16")
+}
+
+func TestProtobuf_ResolvedSchema_NestedRecordDefault(t *testing.T) {
+	defer ConfigTeardown()
+
+	writer := avro.MustParse(`{
+		"type": "record",
+		"name": "NestedMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "title", "type": "string"}
+		]
+	}`)
+	reader := avro.MustParse(`{
+		"type": "record",
+		"name": "NestedMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "title", "type": "string"},
+			{
+				"name": "author",
+				"type": {
+					"type": "record",
+					"name": "BasicMessage",
+					"fields": [
+						{"name": "id", "type": "int"},
+						{"name": "name", "type": "string"},
+						{"name": "active", "type": "boolean"},
+						{"name": "score", "type": "double"}
+					]
+				},
+				"default": {"id": 7, "name": "Anonymous", "active": true, "score": 1.5}
+			}
+		]
+	}`)
+
+	data, err := avro.Marshal(writer, &testpb.NestedMessage{Id: 1, Title: "My Article"})
+	require.NoError(t, err)
+
+	schema, err := avro.NewSchemaCompatibility().Resolve(reader, writer)
+	require.NoError(t, err)
+
+	var decoded testpb.NestedMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), decoded.Id)
+	assert.Equal(t, "My Article", decoded.Title)
+	require.NotNil(t, decoded.Author)
+	assert.Equal(t, int32(7), decoded.Author.Id)
+	assert.Equal(t, "Anonymous", decoded.Author.Name)
+	assert.True(t, decoded.Author.Active)
+	assert.Equal(t, 1.5, decoded.Author.Score)
+}
+
+func TestProtobuf_ResolvedSchema_NullableRecordDefault(t *testing.T) {
+	defer ConfigTeardown()
+
+	writer := avro.MustParse(`{
+		"type": "record",
+		"name": "NestedMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "title", "type": "string"}
+		]
+	}`)
+	reader := avro.MustParse(`{
+		"type": "record",
+		"name": "NestedMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "title", "type": "string"},
+			{
+				"name": "author",
+				"type": [
+					"null",
+					{
+						"type": "record",
+						"name": "BasicMessage",
+						"fields": [
+							{"name": "id", "type": "int"},
+							{"name": "name", "type": "string"},
+							{"name": "active", "type": "boolean"},
+							{"name": "score", "type": "double"}
+						]
+					}
+				],
+				"default": null
+			}
+		]
+	}`)
+
+	data, err := avro.Marshal(writer, &testpb.NestedMessage{Id: 1, Title: "My Article"})
+	require.NoError(t, err)
+
+	schema, err := avro.NewSchemaCompatibility().Resolve(reader, writer)
+	require.NoError(t, err)
+
+	decoded := testpb.NestedMessage{Author: &testpb.BasicMessage{Id: 3}}
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), decoded.Id)
+	assert.Nil(t, decoded.Author)
+}
+
+func TestProtobuf_ResolvedSchema_ScalarDefaults(t *testing.T) {
+	defer ConfigTeardown()
+
+	writer := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"}
+		]
+	}`)
+	reader := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "name", "type": "string", "default": "unknown"},
+			{"name": "rank", "type": "int", "default": 3},
+			{"name": "active", "type": "boolean", "default": true},
+			{"name": "score", "type": ["double", "null"], "default": 2.5}
+		]
+	}`)
+
+	data, err := avro.Marshal(writer, &testpb.BasicMessage{Id: 1})
+	require.NoError(t, err)
+
+	schema, err := avro.NewSchemaCompatibility().Resolve(reader, writer)
+	require.NoError(t, err)
+
+	// The rank field has no protobuf counterpart and is ignored.
+	var decoded testpb.BasicMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	want := &testpb.BasicMessage{Id: 1, Name: "unknown", Active: true, Score: 2.5}
+	assert.True(t, proto.Equal(want, &decoded), "got %v, want %v", &decoded, want)
+}
+
+func TestProtobuf_ResolvedSchema_Evolution(t *testing.T) {
+	defer ConfigTeardown()
+
+	// The older writer schema has its fields in another order, a field since removed,
+	// a float score since widened to a double, and no active field.
+	writer := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "name", "type": "string"},
+			{"name": "legacy", "type": {"type": "array", "items": "string"}},
+			{"name": "score", "type": "float"},
+			{"name": "id", "type": "int"}
+		]
+	}`)
+	reader := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "name", "type": "string"},
+			{"name": "active", "type": "boolean", "default": true},
+			{"name": "score", "type": "double"}
+		]
+	}`)
+
+	data, err := avro.Marshal(writer, map[string]any{
+		"name":   "Jane",
+		"legacy": []string{"a", "b"},
+		"score":  float32(1.5),
+		"id":     7,
+	})
+	require.NoError(t, err)
+
+	schema, err := avro.NewSchemaCompatibility().Resolve(reader, writer)
+	require.NoError(t, err)
+
+	var decoded testpb.BasicMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	want := &testpb.BasicMessage{Id: 7, Name: "Jane", Active: true, Score: 1.5}
+	assert.True(t, proto.Equal(want, &decoded), "got %v, want %v", &decoded, want)
+}
+
+func TestProtobuf_ResolvedSchema_Promotion(t *testing.T) {
+	defer ConfigTeardown()
+
+	writer := avro.MustParse(`{
+		"type": "record",
+		"name": "AllTypesMessage",
+		"fields": [
+			{"name": "int64_field", "type": "int"},
+			{"name": "uint64_field", "type": "int"},
+			{"name": "float_field", "type": "long"},
+			{"name": "double_field", "type": "float"},
+			{"name": "string_field", "type": "bytes"},
+			{"name": "bytes_field", "type": "string"}
+		]
+	}`)
+	reader := avro.MustParse(`{
+		"type": "record",
+		"name": "AllTypesMessage",
+		"fields": [
+			{"name": "int64_field", "type": "long"},
+			{"name": "uint64_field", "type": "long"},
+			{"name": "float_field", "type": "float"},
+			{"name": "double_field", "type": "double"},
+			{"name": "string_field", "type": "string"},
+			{"name": "bytes_field", "type": "bytes"}
+		]
+	}`)
+
+	data, err := avro.Marshal(writer, map[string]any{
+		"int64_field":  -12,
+		"uint64_field": 34,
+		"float_field":  int64(56),
+		"double_field": float32(7.5),
+		"string_field": []byte("text"),
+		"bytes_field":  "raw",
+	})
+	require.NoError(t, err)
+
+	schema, err := avro.NewSchemaCompatibility().Resolve(reader, writer)
+	require.NoError(t, err)
+
+	var decoded testpb.AllTypesMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	want := &testpb.AllTypesMessage{
+		Int64Field:  -12,
+		Uint64Field: 34,
+		FloatField:  56,
+		DoubleField: 7.5,
+		StringField: "text",
+		BytesField:  []byte("raw"),
+	}
+	assert.True(t, proto.Equal(want, &decoded), "got %v, want %v", &decoded, want)
+}
+
+func TestProtobuf_ResolvedSchema_WriterOnlyField(t *testing.T) {
+	defer ConfigTeardown()
+
+	// The reader schema dropped the active field, so it is not read even though
+	// the protobuf message has it.
+	writer := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "active", "type": "boolean"}
+		]
+	}`)
+	reader := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"}
+		]
+	}`)
+
+	data, err := avro.Marshal(writer, &testpb.BasicMessage{Id: 1, Active: true})
+	require.NoError(t, err)
+
+	schema, err := avro.NewSchemaCompatibility().Resolve(reader, writer)
+	require.NoError(t, err)
+
+	var decoded testpb.BasicMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), decoded.Id)
+	assert.False(t, decoded.Active)
+}
+
+func TestProtobuf_ResolvedSchema_EnumAndOneofDefaults(t *testing.T) {
+	defer ConfigTeardown()
+
+	enumWriter := avro.MustParse(`{"type": "record", "name": "EnumMessage", "fields": [{"name": "id", "type": "int"}]}`)
+	enumReader := avro.MustParse(`{
+		"type": "record",
+		"name": "EnumMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{
+				"name": "status",
+				"type": {"type": "enum", "name": "Status", "symbols": ["STATUS_UNSPECIFIED", "STATUS_ACTIVE", "STATUS_INACTIVE"]},
+				"default": "STATUS_INACTIVE"
+			}
+		]
+	}`)
+
+	data, err := avro.Marshal(enumWriter, &testpb.EnumMessage{Id: 1})
+	require.NoError(t, err)
+	schema, err := avro.NewSchemaCompatibility().Resolve(enumReader, enumWriter)
+	require.NoError(t, err)
+
+	var enumDecoded testpb.EnumMessage
+	err = avro.Unmarshal(schema, data, &enumDecoded)
+	require.NoError(t, err)
+	assert.Equal(t, testpb.Status_STATUS_INACTIVE, enumDecoded.Status)
+
+	oneofWriter := avro.MustParse(`{"type": "record", "name": "OneofMessage", "fields": [{"name": "id", "type": "int"}]}`)
+	oneofReader := avro.MustParse(`{
+		"type": "record",
+		"name": "OneofMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "value", "type": ["string", "null", "int"], "default": "none"}
+		]
+	}`)
+
+	data, err = avro.Marshal(oneofWriter, &testpb.OneofMessage{Id: 1})
+	require.NoError(t, err)
+	schema, err = avro.NewSchemaCompatibility().Resolve(oneofReader, oneofWriter)
+	require.NoError(t, err)
+
+	var oneofDecoded testpb.OneofMessage
+	err = avro.Unmarshal(schema, data, &oneofDecoded)
+	require.NoError(t, err)
+	assert.Equal(t, "none", oneofDecoded.GetText())
+}
+
+func TestProtobuf_ResolvedSchema_OneofReaderWithoutNull(t *testing.T) {
+	defer ConfigTeardown()
+
+	writer := avro.MustParse(`{
+		"type": "record",
+		"name": "OneofMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "value", "type": ["null", "string", "int"]}
+		]
+	}`)
+	reader := avro.MustParse(`{
+		"type": "record",
+		"name": "OneofMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "value", "type": ["string", "int"]}
+		]
+	}`)
+
+	data, err := avro.Marshal(writer, &testpb.OneofMessage{Id: 1})
+	require.NoError(t, err)
+
+	// The reader cannot represent a null written by the writer.
+	_, err = avro.NewSchemaCompatibility().Resolve(reader, writer)
+	assert.ErrorContains(t, err, "reader union lacking writer schema null")
+
+	// An unset oneof cannot be represented by the reader union either.
+	var decoded testpb.OneofMessage
+	err = avro.Unmarshal(reader, data, &decoded)
+	assert.ErrorContains(t, err, "oneof value of testpb.OneofMessage must map to a union with a null branch")
+}
+
+func TestProtobuf_AvroRecordOmitsProtoField(t *testing.T) {
+	defer ConfigTeardown()
+
+	// The schema has no name or score field, so they keep their protobuf defaults.
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "active", "type": "boolean"}
+		]
+	}`)
+
+	data, err := avro.Marshal(schema, &testpb.BasicMessage{Id: 1, Name: "dropped", Active: true, Score: 9})
+	require.NoError(t, err)
+
+	var decoded testpb.BasicMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	want := &testpb.BasicMessage{Id: 1, Active: true}
+	assert.True(t, proto.Equal(want, &decoded), "got %v, want %v", &decoded, want)
+}
+
+func TestProtobuf_LocalTimestamp_RoundTrip(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "EventMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "created_at", "type": {"type": "long", "logicalType": "local-timestamp-micros"}},
+			{"name": "local_millis", "type": {"type": "long", "logicalType": "local-timestamp-millis"}},
+			{"name": "updated_at", "type": ["null", {"type": "long", "logicalType": "local-timestamp-millis"}]}
+		]
+	}`)
+
+	original := &testpb.EventMessage{
+		Id:          1,
+		CreatedAt:   &timestamppb.Timestamp{Seconds: 1700000000, Nanos: 123456000},
+		LocalMillis: 1700000000123,
+		UpdatedAt:   &timestamppb.Timestamp{Seconds: -1, Nanos: 500000000},
+	}
+
+	data, err := avro.Marshal(schema, original)
+	require.NoError(t, err)
+
+	var generic map[string]any
+	err = avro.Unmarshal(avro.MustParse(`{
+		"type": "record",
+		"name": "EventMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "created_at", "type": "long"},
+			{"name": "local_millis", "type": "long"},
+			{"name": "updated_at", "type": ["null", "long"]}
+		]
+	}`), data, &generic)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1700000000123456), generic["created_at"])
+	assert.Equal(t, int64(1700000000123), generic["local_millis"])
+	assert.Equal(t, int64(-500), generic["updated_at"])
+
+	var decoded testpb.EventMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(original, &decoded))
+
+	fields := schema.(*avro.RecordSchema).Fields()
+	assert.Equal(t, avro.LocalTimestampMicros, fields[1].Type().(*avro.PrimitiveSchema).Logical().Type())
+	assert.Equal(t, avro.LocalTimestampMillis, fields[2].Type().(*avro.PrimitiveSchema).Logical().Type())
+	assert.Contains(t, schema.String(), `"logicalType":"local-timestamp-micros"`)
+}
+
+func TestProtobuf_Timestamp_TruncatesToUnit(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "EventMessage",
+		"fields": [
+			{"name": "created_at", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+			{"name": "updated_at", "type": ["null", {"type": "long", "logicalType": "timestamp-millis"}]}
+		]
+	}`)
+
+	data, err := avro.Marshal(schema, &testpb.EventMessage{
+		CreatedAt: &timestamppb.Timestamp{Seconds: 10, Nanos: 123456789},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x96, 0x9e, 0x1, 0x0}, data)
+
+	var decoded testpb.EventMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), decoded.CreatedAt.Seconds)
+	assert.Equal(t, int32(123000000), decoded.CreatedAt.Nanos)
+	assert.Nil(t, decoded.UpdatedAt)
+}
+
+func TestProtobuf_Timestamp_RequiresLogicalType(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "EventMessage",
+		"fields": [
+			{"name": "created_at", "type": "long"}
+		]
+	}`)
+
+	_, err := avro.Marshal(schema, &testpb.EventMessage{CreatedAt: &timestamppb.Timestamp{Seconds: 10}})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot encode protobuf field created_at of type message to long")
+}
+
+func TestProtobuf_Timestamp_NanosecondRecord(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "EventMessage",
+		"fields": [
+			{"name": "created_at", "type": {
+				"type": "record",
+				"name": "NanoTimestamp",
+				"fields": [
+					{"name": "seconds", "type": "long"},
+					{"name": "nanos", "type": "int"}
+				]
+			}},
+			{"name": "updated_at", "type": ["null", {"type": "long", "logicalType": "timestamp-micros"}, "NanoTimestamp"]}
+		]
+	}`)
+
+	original := &testpb.EventMessage{
+		CreatedAt: &timestamppb.Timestamp{Seconds: 1700000000, Nanos: 123456789},
+		UpdatedAt: &timestamppb.Timestamp{Seconds: -1, Nanos: 123456789},
+	}
+
+	data, err := avro.Marshal(schema, original)
+	require.NoError(t, err)
+
+	var generic map[string]any
+	err = avro.Unmarshal(schema, data, &generic)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"seconds": int64(1700000000), "nanos": 123456789}, generic["created_at"])
+	// The first matching branch is used, truncating the nanoseconds.
+	assert.Equal(t, map[string]any{"long.timestamp-micros": time.Unix(-1, 123456000).UTC()}, generic["updated_at"])
+
+	var decoded testpb.EventMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, int32(123456789), decoded.CreatedAt.Nanos)
+	assert.True(t, proto.Equal(original.CreatedAt, decoded.CreatedAt), "got %v, want %v", decoded.CreatedAt, original.CreatedAt)
+	assert.Equal(t, int32(123456000), decoded.UpdatedAt.Nanos)
+
+	// A union of the record alone preserves the nanoseconds.
+	schema = avro.MustParse(`{
+		"type": "record",
+		"name": "EventMessage",
+		"fields": [
+			{"name": "updated_at", "type": ["null", {
+				"type": "record",
+				"name": "NanoTimestamp",
+				"fields": [
+					{"name": "seconds", "type": "long"},
+					{"name": "nanos", "type": "int"}
+				]
+			}]}
+		]
+	}`)
+
+	data, err = avro.Marshal(schema, original)
+	require.NoError(t, err)
+
+	var nullable testpb.EventMessage
+	err = avro.Unmarshal(schema, data, &nullable)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(original.UpdatedAt, nullable.UpdatedAt), "got %v, want %v", nullable.UpdatedAt, original.UpdatedAt)
+}
+
+func TestProtobuf_Timestamp_Date(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "EventMessage",
+		"fields": [
+			{"name": "created_at", "type": {"type": "int", "logicalType": "date"}},
+			{"name": "updated_at", "type": ["null", {"type": "int", "logicalType": "date"}]}
+		]
+	}`)
+
+	original := &testpb.EventMessage{
+		CreatedAt: timestamppb.New(time.Date(2020, 1, 2, 1, 0, 0, 0, time.FixedZone("UTC+5", 5*60*60))),
+		UpdatedAt: timestamppb.New(time.Date(1969, 12, 31, 12, 0, 0, 0, time.UTC)),
+	}
+
+	data, err := avro.Marshal(schema, original)
+	require.NoError(t, err)
+
+	var days map[string]any
+	err = avro.Unmarshal(avro.MustParse(`{
+		"type": "record",
+		"name": "EventMessage",
+		"fields": [
+			{"name": "created_at", "type": "int"},
+			{"name": "updated_at", "type": ["null", "int"]}
+		]
+	}`), data, &days)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"created_at": 18262, "updated_at": -1}, days)
+
+	var decoded testpb.EventMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), decoded.CreatedAt.AsTime())
+	assert.Equal(t, time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), decoded.UpdatedAt.AsTime())
+}
+
+func TestProtobuf_TimestampEpochOffset(t *testing.T) {
+	defer ConfigTeardown()
+
+	epoch := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	api := avro.Config{ProtoTimestampEpochOffset: time.Duration(epoch.Unix()) * time.Second}.Freeze()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "EventMessage",
+		"fields": [
+			{"name": "created_at", "type": {"type": "long", "logicalType": "timestamp-micros"}},
+			{"name": "local_millis", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+			{"name": "updated_at", "type": ["null", {"type": "long", "logicalType": "timestamp-millis"}]}
+		]
+	}`)
+
+	original := &testpb.EventMessage{
+		CreatedAt:   timestamppb.New(epoch.Add(90 * time.Second)),
+		LocalMillis: epoch.Add(-1500 * time.Millisecond).UnixMilli(),
+		UpdatedAt:   timestamppb.New(time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)),
+	}
+
+	data, err := api.Marshal(schema, original)
+	require.NoError(t, err)
+
+	var generic map[string]any
+	err = api.Unmarshal(avro.MustParse(`{
+		"type": "record",
+		"name": "EventMessage",
+		"fields": [
+			{"name": "created_at", "type": "long"},
+			{"name": "local_millis", "type": "long"},
+			{"name": "updated_at", "type": ["null", "long"]}
+		]
+	}`), data, &generic)
+	require.NoError(t, err)
+	assert.Equal(t, int64(90_000_000), generic["created_at"])
+	assert.Equal(t, int64(-1500), generic["local_millis"])
+	assert.Equal(t, -epoch.UnixMilli(), generic["updated_at"])
+
+	var decoded testpb.EventMessage
+	err = api.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(original, &decoded), "got %v, want %v", &decoded, original)
+
+	unixData, err := avro.Marshal(schema, original)
+	require.NoError(t, err)
+	assert.NotEqual(t, data, unixData)
+}
+
+func TestProtobuf_AnyRecord(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "AnyMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "payload", "type": ["null", {
+				"type": "record",
+				"name": "AnyPayload",
+				"fields": [
+					{"name": "type_url", "type": "string"},
+					{"name": "value", "type": "bytes"}
+				]
+			}]}
+		]
+	}`)
+
+	payload, err := anypb.New(&testpb.BasicMessage{Id: 7, Name: "packed", Active: true, Score: 1.5})
+	require.NoError(t, err)
+	original := &testpb.AnyMessage{Id: 1, Payload: payload}
+
+	data, err := avro.Marshal(schema, original)
+	require.NoError(t, err)
+
+	var decoded testpb.AnyMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(original, &decoded), "got %v, want %v", &decoded, original)
+
+	basic, err := decoded.Payload.UnmarshalNew()
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(&testpb.BasicMessage{Id: 7, Name: "packed", Active: true, Score: 1.5}, basic))
+}
+
+func TestProtobuf_ResolveAny(t *testing.T) {
+	defer ConfigTeardown()
+
+	cfg := avro.Config{ProtoResolveAny: true}.Freeze()
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "AnyMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "payload", "type": ["null", {
+				"type": "record",
+				"name": "BasicMessage",
+				"namespace": "testpb",
+				"fields": [
+					{"name": "id", "type": "int"},
+					{"name": "name", "type": "string"}
+				]
+			}, {
+				"type": "record",
+				"name": "NestedMessage",
+				"namespace": "testpb",
+				"fields": [
+					{"name": "id", "type": "int"},
+					{"name": "title", "type": "string"}
+				]
+			}]}
+		]
+	}`)
+
+	payload, err := anypb.New(&testpb.BasicMessage{Id: 7, Name: "packed"})
+	require.NoError(t, err)
+	original := &testpb.AnyMessage{Id: 1, Payload: payload}
+
+	data, err := cfg.Marshal(schema, original)
+	require.NoError(t, err)
+
+	var generic map[string]any
+	err = cfg.Unmarshal(schema, data, &generic)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"testpb.BasicMessage": map[string]any{"id": 7, "name": "packed"}}, generic["payload"])
+
+	var decoded testpb.AnyMessage
+	err = cfg.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, "type.googleapis.com/testpb.BasicMessage", decoded.Payload.GetTypeUrl())
+	basic, err := decoded.Payload.UnmarshalNew()
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(&testpb.BasicMessage{Id: 7, Name: "packed"}, basic), "got %v", basic)
+
+	var unset testpb.AnyMessage
+	data, err = cfg.Marshal(schema, &testpb.AnyMessage{Id: 2})
+	require.NoError(t, err)
+	err = cfg.Unmarshal(schema, data, &unset)
+	require.NoError(t, err)
+	assert.Nil(t, unset.Payload)
+}
+
+func TestProtobuf_ResolveAny_UnknownType(t *testing.T) {
+	defer ConfigTeardown()
+
+	cfg := avro.Config{ProtoResolveAny: true}.Freeze()
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "AnyMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "payload", "type": ["null", {
+				"type": "record",
+				"name": "NestedMessage",
+				"namespace": "testpb",
+				"fields": [
+					{"name": "id", "type": "int"},
+					{"name": "title", "type": "string"}
+				]
+			}]}
+		]
+	}`)
+
+	payload, err := anypb.New(&testpb.BasicMessage{Id: 7})
+	require.NoError(t, err)
+
+	_, err = cfg.Marshal(schema, &testpb.AnyMessage{Id: 1, Payload: payload})
+	assert.ErrorContains(t, err, "no matching union type found for protobuf field payload")
+}
+
+func TestProtobuf_Struct(t *testing.T) {
+	defer ConfigTeardown()
+
+	scalar := `["null", "double", "string", "boolean"]`
+	value := `["null", "double", "string", "boolean",
+		{"type": "map", "values": ` + scalar + `},
+		{"type": "array", "items": ` + scalar + `}]`
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "StructMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "attributes", "type": {"type": "map", "values": ` + value + `}},
+			{"name": "value", "type": ` + value + `},
+			{"name": "tags", "type": {"type": "array", "items": ` + scalar + `}}
+		]
+	}`)
+
+	attributes, err := structpb.NewStruct(map[string]any{
+		"name":    "Jane",
+		"age":     42.5,
+		"active":  true,
+		"manager": nil,
+		"address": map[string]any{"city": "Cape Town", "zip": 8001},
+		"scores":  []any{1, "two", false, nil},
+	})
+	require.NoError(t, err)
+	tags, err := structpb.NewList([]any{"a", 2, true, nil})
+	require.NoError(t, err)
+	original := &testpb.StructMessage{
+		Id:         1,
+		Attributes: attributes,
+		Value:      structpb.NewStringValue("hello"),
+		Tags:       tags,
+	}
+
+	data, err := avro.Marshal(schema, original)
+	require.NoError(t, err)
+
+	var decoded testpb.StructMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	assert.True(t, proto.Equal(original, &decoded), "got %v, want %v", &decoded, original)
+	assert.Equal(t, attributes.AsMap(), decoded.GetAttributes().AsMap())
+}
+
+func TestProtobuf_Struct_NullValue(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "StructMessage",
+		"fields": [
+			{"name": "value", "type": ["null", "double", "string", "boolean"]}
+		]
+	}`)
+
+	data, err := avro.Marshal(schema, &testpb.StructMessage{Value: structpb.NewNullValue()})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x00}, data)
+
+	var decoded testpb.StructMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	assert.True(t, proto.Equal(structpb.NewNullValue(), decoded.Value), "got %v", decoded.Value)
+}
+
+func TestProtobuf_Struct_UnsupportedKind(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "StructMessage",
+		"fields": [
+			{"name": "value", "type": ["null", "string"]}
+		]
+	}`)
+
+	_, err := avro.Marshal(schema, &testpb.StructMessage{Value: structpb.NewNumberValue(1)})
+
+	assert.ErrorContains(t, err, "no matching union type found for oneof field number_value")
+}
+
+func TestProtobuf_NarrowDoubleToFloat(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "AllTypesMessage",
+		"fields": [
+			{"name": "float_field", "type": "double"}
+		]
+	}`)
+	data, err := avro.Marshal(schema, map[string]any{"float_field": 0.1})
+	require.NoError(t, err)
+
+	t.Run("disabled", func(t *testing.T) {
+		var decoded testpb.AllTypesMessage
+		err := avro.Unmarshal(schema, data, &decoded)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot decode double to protobuf field float_field of type float")
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		api := avro.Config{ProtoNarrowDoubleToFloat: true}.Freeze()
+
+		var decoded testpb.AllTypesMessage
+		err := api.Unmarshal(schema, data, &decoded)
+
+		require.NoError(t, err)
+		assert.Equal(t, float32(0.1), decoded.FloatField)
+	})
+
+	t.Run("strict exact", func(t *testing.T) {
+		api := avro.Config{ProtoNarrowDoubleToFloat: true, ProtoStrictFloatNarrowing: true}.Freeze()
+		data, err := api.Marshal(schema, map[string]any{"float_field": 1.5})
+		require.NoError(t, err)
+
+		var decoded testpb.AllTypesMessage
+		err = api.Unmarshal(schema, data, &decoded)
+
+		require.NoError(t, err)
+		assert.Equal(t, float32(1.5), decoded.FloatField)
+	})
+
+	t.Run("strict precision loss", func(t *testing.T) {
+		api := avro.Config{ProtoNarrowDoubleToFloat: true, ProtoStrictFloatNarrowing: true}.Freeze()
+
+		var decoded testpb.AllTypesMessage
+		err := api.Unmarshal(schema, data, &decoded)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "double value 0.1 cannot be narrowed to float without loss for protobuf field float_field")
+	})
+
+	t.Run("strict overflow", func(t *testing.T) {
+		api := avro.Config{ProtoNarrowDoubleToFloat: true, ProtoStrictFloatNarrowing: true}.Freeze()
+		data, err := api.Marshal(schema, map[string]any{"float_field": math.MaxFloat64})
+		require.NoError(t, err)
+
+		var decoded testpb.AllTypesMessage
+		err = api.Unmarshal(schema, data, &decoded)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot be narrowed to float without loss")
+	})
+}
+
+func TestProtobuf_CountingEncoderRoundTrip(t *testing.T) {
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "name", "type": "string"},
+			{"name": "active", "type": "boolean"},
+			{"name": "score", "type": "double"}
+		]
+	}`)
+
+	buf := &bytes.Buffer{}
+	enc := avro.NewCountingEncoder(schema, buf)
+	for i := range 5 {
+		err := enc.Encode(&testpb.BasicMessage{Id: int32(i), Name: "msg", Active: true, Score: float64(i)})
+		require.NoError(t, err)
+	}
+	err := enc.Finish()
+	require.NoError(t, err)
+
+	dec := avro.NewCountingDecoder(schema, buf)
+	var ids []int32
+	for {
+		var msg testpb.BasicMessage
+		err = dec.Decode(&msg)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		ids = append(ids, msg.Id)
+	}
+	err = dec.Finish()
+
+	require.NoError(t, err)
+	assert.Equal(t, []int32{0, 1, 2, 3, 4}, ids)
+	assert.Equal(t, int64(5), dec.Count())
+}
+
+func TestProtobuf_MapOfArraysOfRecords_RoundTrip(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "GroupedItemsMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "groups", "type": {
+				"type": "map",
+				"values": {
+					"type": "array",
+					"items": {
+						"type": "record",
+						"name": "LineItem",
+						"fields": [
+							{"name": "sku", "type": "string"},
+							{"name": "quantity", "type": "int"}
+						]
+					}
+				}
+			}}
+		]
+	}`)
+
+	original := &testpb.GroupedItemsMessage{
+		Id: 1,
+		Groups: map[string]*testpb.LineItemList{
+			"fruit": {Items: []*testpb.LineItem{
+				{Sku: "apple", Quantity: 3},
+				{Sku: "pear", Quantity: 1},
+			}},
+			"empty": {},
+		},
+	}
+
+	data, err := avro.Marshal(schema, original)
+	require.NoError(t, err)
+
+	var decoded testpb.GroupedItemsMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	assert.True(t, proto.Equal(original, &decoded), "got %v", &decoded)
+
+	// The proto encoding must match the equivalent Go type encoding.
+	type lineItem struct {
+		Sku      string `avro:"sku"`
+		Quantity int    `avro:"quantity"`
+	}
+	type groupedItems struct {
+		ID     int                   `avro:"id"`
+		Groups map[string][]lineItem `avro:"groups"`
+	}
+	var native groupedItems
+	err = avro.Unmarshal(schema, data, &native)
+	require.NoError(t, err)
+	assert.Equal(t, groupedItems{
+		ID: 1,
+		Groups: map[string][]lineItem{
+			"fruit": {{Sku: "apple", Quantity: 3}, {Sku: "pear", Quantity: 1}},
+			"empty": {},
+		},
+	}, native)
+}
+
+func TestProtobuf_OnSkippedField(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "extra_string", "type": "string"},
+			{"name": "name", "type": "string"},
+			{"name": "extra_record", "type": {
+				"type": "record",
+				"name": "Extra",
+				"fields": [{"name": "value", "type": "int"}]
+			}}
+		]
+	}`)
+
+	data := map[string]any{
+		"id":           int32(42),
+		"extra_string": "not in proto",
+		"name":         "John Doe",
+		"extra_record": map[string]any{"value": int32(1)},
+	}
+	encoded, err := avro.Marshal(schema, data)
+	require.NoError(t, err)
+
+	var skipped []string
+	api := avro.Config{
+		OnSkippedField: func(name string) {
+			skipped = append(skipped, name)
+		},
+	}.Freeze()
+
+	var decoded testpb.BasicMessage
+	err = api.Unmarshal(schema, encoded, &decoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(42), decoded.Id)
+	assert.Equal(t, "John Doe", decoded.Name)
+	assert.Equal(t, []string{"extra_string", "extra_record"}, skipped)
+}
+
+const priceMessageSchema = `{
+	"type": "record",
+	"name": "PriceMessage",
+	"fields": [
+		{"name": "id", "type": "int"},
+		{"name": "amount", "type": {"type": "fixed", "name": "Amount", "size": 3, "logicalType": "decimal", "precision": 7, "scale": 2}},
+		{"name": "raw_amount", "type": {"type": "fixed", "name": "RawAmount", "size": 4, "logicalType": "decimal", "precision": 9, "scale": 2}}
+	]
+}`
+
+func TestProtobuf_FixedDecimal_RoundTrip(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(priceMessageSchema)
+
+	original := &testpb.PriceMessage{
+		Id:        1,
+		Amount:    "-123.45",
+		RawAmount: []byte{0x00, 0x00, 0x30, 0x39},
+	}
+
+	data, err := avro.Marshal(schema, original)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x02, 0xff, 0xcf, 0xc7, 0x00, 0x00, 0x30, 0x39}, data)
+
+	var decoded testpb.PriceMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, "-123.45", decoded.Amount)
+	assert.Equal(t, []byte{0x00, 0x00, 0x30, 0x39}, decoded.RawAmount)
+
+	// The encoding must match the native decimal encoding.
+	var native struct {
+		ID        int      `avro:"id"`
+		Amount    *big.Rat `avro:"amount"`
+		RawAmount *big.Rat `avro:"raw_amount"`
+	}
+	err = avro.Unmarshal(schema, data, &native)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewRat(-12345, 100), native.Amount)
+	assert.Equal(t, big.NewRat(12345, 100), native.RawAmount)
+}
+
+func TestProtobuf_FixedDecimal_EncodeErrors(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(priceMessageSchema)
+
+	tests := []struct {
+		name    string
+		amount  string
+		wantErr string
+	}{
+		{
+			name:    "Invalid",
+			amount:  "abc",
+			wantErr: `protobuf field amount value "abc" is not a decimal`,
+		},
+		{
+			name:    "Precision",
+			amount:  "123456.78",
+			wantErr: "protobuf field amount value 123456.78 exceeds decimal precision 7, has 8 significant digits",
+		},
+		{
+			name:    "Size",
+			amount:  "-99999.99",
+			wantErr: "protobuf field amount value -99999.99 does not fit in fixed size 3",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			msg := &testpb.PriceMessage{Amount: test.amount, RawAmount: make([]byte, 4)}
+
+			_, err := avro.Marshal(schema, msg)
+
+			assert.ErrorContains(t, err, test.wantErr)
+		})
+	}
+}
+
+func TestProtobuf_EnumOrdinal_RoundTrip(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": {"type": "enum", "name": "Level", "symbols": ["LOW", "MEDIUM", "HIGH"], "protoOrdinal": true}},
+			{"name": "name", "type": "string"}
+		]
+	}`)
+
+	original := &testpb.BasicMessage{Id: 2, Name: "test"}
+
+	data, err := avro.Marshal(schema, original)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x04, 0x08, 't', 'e', 's', 't'}, data)
+
+	var decoded testpb.BasicMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), decoded.Id)
+
+	var native struct {
+		ID string `avro:"id"`
+	}
+	err = avro.Unmarshal(schema, data, &native)
+	require.NoError(t, err)
+	assert.Equal(t, "HIGH", native.ID)
+
+	_, err = avro.Marshal(schema, &testpb.BasicMessage{Id: 3})
+	assert.ErrorContains(t, err, "protobuf field id value 3 is not a valid enum index")
+}
+
+func TestProtobuf_EnumOrdinal_RequiresProperty(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": {"type": "enum", "name": "Level", "symbols": ["LOW", "MEDIUM", "HIGH"]}}
+		]
+	}`)
+
+	_, err := avro.Marshal(schema, &testpb.BasicMessage{Id: 1})
+
+	assert.ErrorContains(t, err, "cannot encode protobuf field id of type int32 to enum")
+}
+
+func TestProtobuf_ExtraUnionAndRecursiveFieldsInAvro(t *testing.T) {
+	defer ConfigTeardown()
+
+	// Avro schema has extra fields with unions of records, arrays and maps of records,
+	// and a recursive record
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{
+				"name": "extra_union",
+				"type": [
+					"null",
+					{"type": "record", "name": "ExtraA", "fields": [{"name": "a", "type": "string"}]},
+					{"type": "record", "name": "ExtraB", "fields": [{"name": "b", "type": ["null", "long"]}]}
+				]
+			},
+			{"name": "name", "type": "string"},
+			{"name": "extra_array", "type": {"type": "array", "items": "ExtraA"}},
+			{"name": "extra_map", "type": {"type": "map", "values": ["null", "ExtraB"]}},
+			{
+				"name": "extra_tree",
+				"type": {
+					"type": "record",
+					"name": "ExtraNode",
+					"fields": [
+						{"name": "value", "type": "int"},
+						{"name": "next", "type": ["null", "ExtraNode"]},
+						{"name": "children", "type": {"type": "array", "items": "ExtraNode"}},
+						{"name": "nothing", "type": "null"}
+					]
+				}
+			},
+			{"name": "active", "type": "boolean"},
+			{"name": "score", "type": "double"}
+		]
+	}`)
+
+	leaf := map[string]any{"value": int32(3), "next": nil, "children": []any{}, "nothing": nil}
+	data := map[string]any{
+		"id":          int32(99),
+		"extra_union": map[string]any{"ExtraB": map[string]any{"b": map[string]any{"long": int64(7)}}},
+		"name":        "Jane Doe",
+		"extra_array": []any{map[string]any{"a": "x"}, map[string]any{"a": "y"}},
+		"extra_map": map[string]any{
+			"key1": map[string]any{"ExtraB": map[string]any{"b": nil}},
+			"key2": nil,
+		},
+		"extra_tree": map[string]any{
+			"value":    int32(1),
+			"next":     map[string]any{"ExtraNode": map[string]any{"value": int32(2), "next": nil, "children": []any{leaf}, "nothing": nil}},
+			"children": []any{leaf, leaf},
+			"nothing":  nil,
+		},
+		"active": true,
+		"score":  88.5,
+	}
+
+	encoded, err := avro.Marshal(schema, data)
+	require.NoError(t, err)
+
+	var decoded testpb.BasicMessage
+	err = avro.Unmarshal(schema, encoded, &decoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(99), decoded.Id)
+	assert.Equal(t, "Jane Doe", decoded.Name)
+	assert.Equal(t, true, decoded.Active)
+	assert.Equal(t, 88.5, decoded.Score)
+}
+
+func TestProtobuf_AllTypesMessage_BoundaryValues(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "AllTypesMessage",
+		"fields": [
+			{"name": "int32_field", "type": "int"},
+			{"name": "int64_field", "type": "long"},
+			{"name": "uint32_field", "type": "int"},
+			{"name": "uint64_field", "type": "long"},
+			{"name": "sint32_field", "type": "int"},
+			{"name": "sint64_field", "type": "long"},
+			{"name": "fixed32_field", "type": "int"},
+			{"name": "fixed64_field", "type": "long"},
+			{"name": "sfixed32_field", "type": "int"},
+			{"name": "sfixed64_field", "type": "long"},
+			{"name": "float_field", "type": "float"},
+			{"name": "double_field", "type": "double"},
+			{"name": "bool_field", "type": "boolean"},
+			{"name": "string_field", "type": "string"},
+			{"name": "bytes_field", "type": "bytes"}
+		]
+	}`)
+
+	tests := []struct {
+		name string
+		msg  *testpb.AllTypesMessage
+	}{
+		{
+			name: "min",
+			msg: &testpb.AllTypesMessage{
+				Int32Field:    math.MinInt32,
+				Int64Field:    math.MinInt64,
+				Uint32Field:   0,
+				Uint64Field:   0,
+				Sint32Field:   math.MinInt32,
+				Sint64Field:   math.MinInt64,
+				Fixed32Field:  0,
+				Fixed64Field:  0,
+				Sfixed32Field: math.MinInt32,
+				Sfixed64Field: math.MinInt64,
+				FloatField:    -math.MaxFloat32,
+				DoubleField:   -math.MaxFloat64,
+				BoolField:     false,
+				StringField:   "",
+				BytesField:    []byte{},
+			},
+		},
+		{
+			name: "max",
+			msg: &testpb.AllTypesMessage{
+				Int32Field:    math.MaxInt32,
+				Int64Field:    math.MaxInt64,
+				Uint32Field:   math.MaxUint32,
+				Uint64Field:   math.MaxUint64,
+				Sint32Field:   math.MaxInt32,
+				Sint64Field:   math.MaxInt64,
+				Fixed32Field:  math.MaxUint32,
+				Fixed64Field:  math.MaxUint64,
+				Sfixed32Field: math.MaxInt32,
+				Sfixed64Field: math.MaxInt64,
+				FloatField:    math.MaxFloat32,
+				DoubleField:   math.MaxFloat64,
+				BoolField:     true,
+				StringField:   "",
+				BytesField:    []byte{},
+			},
+		},
+		{
+			name: "smallest",
+			msg: &testpb.AllTypesMessage{
+				Int32Field:    -1,
+				Int64Field:    -1,
+				Uint32Field:   1,
+				Uint64Field:   1,
+				Sint32Field:   -1,
+				Sint64Field:   -1,
+				Fixed32Field:  1,
+				Fixed64Field:  1,
+				Sfixed32Field: -1,
+				Sfixed64Field: -1,
+				FloatField:    math.SmallestNonzeroFloat32,
+				DoubleField:   math.SmallestNonzeroFloat64,
+				BoolField:     true,
+				StringField:   "",
+				BytesField:    []byte{},
+			},
+		},
+		{
+			name: "unsigned above signed max",
+			msg: &testpb.AllTypesMessage{
+				Uint32Field:  math.MaxInt32 + 1,
+				Uint64Field:  math.MaxInt64 + 1,
+				Fixed32Field: math.MaxInt32 + 1,
+				Fixed64Field: math.MaxInt64 + 1,
+				FloatField:   -math.SmallestNonzeroFloat32,
+				DoubleField:  -math.SmallestNonzeroFloat64,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := avro.Marshal(schema, test.msg)
+			require.NoError(t, err)
+
+			var decoded testpb.AllTypesMessage
+			err = avro.Unmarshal(schema, data, &decoded)
+			require.NoError(t, err)
+
+			assert.True(t, proto.Equal(test.msg, &decoded), "got %v, want %v", &decoded, test.msg)
+		})
+	}
+}
+
+func TestProtobuf_AllTypesMessage_BoundaryValuesEncoding(t *testing.T) {
+	defer ConfigTeardown()
+
+	tests := []struct {
+		name  string
+		field string
+		msg   *testpb.AllTypesMessage
+		want  []byte
+	}{
+		{
+			name:  "sint64 min",
+			field: "sint64_field",
+			msg:   &testpb.AllTypesMessage{Sint64Field: math.MinInt64},
+			want:  []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01},
+		},
+		{
+			name:  "sint64 max",
+			field: "sint64_field",
+			msg:   &testpb.AllTypesMessage{Sint64Field: math.MaxInt64},
+			want:  []byte{0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01},
+		},
+		{
+			name:  "sfixed64 min",
+			field: "sfixed64_field",
+			msg:   &testpb.AllTypesMessage{Sfixed64Field: math.MinInt64},
+			want:  []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01},
+		},
+		{
+			name:  "fixed64 max int64",
+			field: "fixed64_field",
+			msg:   &testpb.AllTypesMessage{Fixed64Field: math.MaxInt64},
+			want:  []byte{0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01},
+		},
+		{
+			name:  "fixed64 max",
+			field: "fixed64_field",
+			msg:   &testpb.AllTypesMessage{Fixed64Field: math.MaxUint64},
+			want:  []byte{0x01},
+		},
+		{
+			name:  "uint64 above max int64",
+			field: "uint64_field",
+			msg:   &testpb.AllTypesMessage{Uint64Field: math.MaxInt64 + 1},
+			want:  []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schema := avro.MustParse(`{
+				"type": "record",
+				"name": "AllTypesMessage",
+				"fields": [{"name": "` + test.field + `", "type": "long"}]
+			}`)
+
+			data, err := avro.Marshal(schema, test.msg)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, data)
+
+			var decoded testpb.AllTypesMessage
+			err = avro.Unmarshal(schema, data, &decoded)
+			require.NoError(t, err)
+			assert.True(t, proto.Equal(test.msg, &decoded), "got %v, want %v", &decoded, test.msg)
+		})
+	}
+}
+
+func TestProtobuf_AllTypesMessage_PromotedIntToUnsigned(t *testing.T) {
+	defer ConfigTeardown()
+
+	writer := avro.MustParse(`{
+		"type": "record",
+		"name": "AllTypesMessage",
+		"fields": [{"name": "uint64_field", "type": "int"}]
+	}`)
+	reader := avro.MustParse(`{
+		"type": "record",
+		"name": "AllTypesMessage",
+		"fields": [{"name": "uint64_field", "type": "long"}]
+	}`)
+	schema, err := avro.NewSchemaCompatibility().Resolve(reader, writer)
+	require.NoError(t, err)
+
+	var decoded testpb.AllTypesMessage
+	err = avro.Unmarshal(schema, []byte{0xfe, 0xff, 0xff, 0xff, 0x0f}, &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(math.MaxInt32), decoded.Uint64Field)
+
+	// A negative int was not wrapped by the writer, so it cannot be read as unsigned.
+	err = avro.Unmarshal(schema, []byte{0x01}, &decoded)
+	assert.ErrorContains(t, err, "uint64_field value -1 overflows uint64")
+}
+
+func TestProtobuf_AllTypesMessage_BoundaryValuesNarrowedToInt(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "AllTypesMessage",
+		"fields": [
+			{"name": "int64_field", "type": "int"},
+			{"name": "uint64_field", "type": "int"},
+			{"name": "sfixed64_field", "type": "int"},
+			{"name": "fixed64_field", "type": "int"}
+		]
+	}`)
+
+	msgs := []*testpb.AllTypesMessage{
+		{Int64Field: math.MinInt32, Uint64Field: 0, Sfixed64Field: math.MinInt32, Fixed64Field: 0},
+		{Int64Field: math.MaxInt32, Uint64Field: math.MaxInt32, Sfixed64Field: math.MaxInt32, Fixed64Field: math.MaxInt32},
+	}
+	for _, msg := range msgs {
+		data, err := avro.Marshal(schema, msg)
+		require.NoError(t, err)
+
+		var decoded testpb.AllTypesMessage
+		err = avro.Unmarshal(schema, data, &decoded)
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(msg, &decoded), "got %v, want %v", &decoded, msg)
+	}
+
+	overflows := []*testpb.AllTypesMessage{
+		{Int64Field: math.MinInt32 - 1},
+		{Int64Field: math.MaxInt32 + 1},
+		{Uint64Field: math.MaxInt32 + 1},
+		{Uint64Field: math.MaxUint64},
+		{Sfixed64Field: math.MinInt64},
+		{Fixed64Field: math.MaxUint32 + 1},
+	}
+	for _, msg := range overflows {
+		_, err := avro.Marshal(schema, msg)
+		assert.ErrorContains(t, err, "overflows int")
+	}
+
+	// A negative int cannot be decoded into an unsigned 64 bit field.
+	data := []byte{0x00, 0x01, 0x00, 0x00}
+	var decoded testpb.AllTypesMessage
+	err := avro.Unmarshal(schema, data, &decoded)
+	assert.ErrorContains(t, err, "overflows uint64")
+}
+
+func TestProtobuf_Int64AsString(t *testing.T) {
+	defer ConfigTeardown()
+
+	api := avro.Config{ProtoInt64AsString: true}.Freeze()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "AllTypesMessage",
+		"fields": [
+			{"name": "int64_field", "type": "string"},
+			{"name": "uint64_field", "type": "string"},
+			{"name": "sint64_field", "type": "string"},
+			{"name": "fixed64_field", "type": "string"},
+			{"name": "sfixed64_field", "type": ["null", "string"]}
+		]
+	}`)
+
+	original := &testpb.AllTypesMessage{
+		Int64Field:    math.MaxInt64,
+		Uint64Field:   math.MaxUint64,
+		Sint64Field:   math.MinInt64,
+		Fixed64Field:  0,
+		Sfixed64Field: -42,
+	}
+
+	data, err := api.Marshal(schema, original)
+	require.NoError(t, err)
+
+	var generic map[string]any
+	err = api.Unmarshal(schema, data, &generic)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"int64_field":    "9223372036854775807",
+		"uint64_field":   "18446744073709551615",
+		"sint64_field":   "-9223372036854775808",
+		"fixed64_field":  "0",
+		"sfixed64_field": "-42",
+	}, generic)
+
+	var decoded testpb.AllTypesMessage
+	err = api.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(original, &decoded), "got %v, want %v", &decoded, original)
+}
+
+func TestProtobuf_Int64AsString_Invalid(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "AllTypesMessage",
+		"fields": [
+			{"name": "uint64_field", "type": "string"}
+		]
+	}`)
+
+	_, err := avro.Marshal(schema, &testpb.AllTypesMessage{Uint64Field: 1})
+	assert.Error(t, err)
+
+	api := avro.Config{ProtoInt64AsString: true}.Freeze()
+	data, err := api.Marshal(schema, map[string]any{"uint64_field": "-1"})
+	require.NoError(t, err)
+
+	var decoded testpb.AllTypesMessage
+	err = api.Unmarshal(schema, data, &decoded)
+	assert.ErrorContains(t, err, `protobuf field uint64_field value "-1" is not a valid uint64`)
+}
+
+func TestProtobuf_CoerceNumericStrings(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "AllTypesMessage",
+		"fields": [
+			{"name": "int32_field", "type": "string"},
+			{"name": "int64_field", "type": "string"},
+			{"name": "uint32_field", "type": "string"},
+			{"name": "float_field", "type": ["null", "string"]},
+			{"name": "double_field", "type": "string"}
+		]
+	}`)
+	data, err := avro.Marshal(schema, map[string]any{
+		"int32_field":  "42",
+		"int64_field":  "-9223372036854775808",
+		"uint32_field": "4294967295",
+		"float_field":  "1.5",
+		"double_field": "-2.25e3",
+	})
+	require.NoError(t, err)
+
+	var decoded testpb.AllTypesMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.ErrorContains(t, err, "cannot decode string to protobuf field int32_field of type int32")
+
+	api := avro.Config{ProtoCoerceNumericStrings: true}.Freeze()
+	decoded = testpb.AllTypesMessage{}
+	err = api.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+	want := &testpb.AllTypesMessage{
+		Int32Field:  42,
+		Int64Field:  math.MinInt64,
+		Uint32Field: math.MaxUint32,
+		FloatField:  1.5,
+		DoubleField: -2250,
+	}
+	assert.True(t, proto.Equal(want, &decoded), "got %v, want %v", &decoded, want)
+}
+
+func TestProtobuf_CoerceNumericStrings_Invalid(t *testing.T) {
+	defer ConfigTeardown()
+
+	api := avro.Config{ProtoCoerceNumericStrings: true}.Freeze()
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "AllTypesMessage",
+		"fields": [
+			{"name": "int32_field", "type": "string"}
+		]
+	}`)
+
+	tests := []struct {
+		name string
+		val  string
+	}{
+		{name: "not a number", val: "forty-two"},
+		{name: "fraction", val: "4.2"},
+		{name: "overflow", val: "2147483648"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := api.Marshal(schema, map[string]any{"int32_field": test.val})
+			require.NoError(t, err)
+
+			var decoded testpb.AllTypesMessage
+			err = api.Unmarshal(schema, data, &decoded)
+			assert.ErrorContains(t, err, fmt.Sprintf("protobuf field int32_field value %q is not a valid int32", test.val))
+		})
+	}
+}
+
+func TestProtobuf_NonFiniteFloatAsNull(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "score", "type": ["null", "double"]}
+		]
+	}`)
+	api := avro.Config{ProtoNonFiniteFloatAsNull: true}.Freeze()
+
+	tests := []struct {
+		name  string
+		score float64
+		want  any
+	}{
+		{name: "NaN", score: math.NaN(), want: nil},
+		{name: "positive infinity", score: math.Inf(1), want: nil},
+		{name: "negative infinity", score: math.Inf(-1), want: nil},
+		{name: "finite", score: 88.5, want: 88.5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := api.Marshal(schema, &testpb.BasicMessage{Id: 1, Score: test.score})
+			require.NoError(t, err)
+
+			var got map[string]any
+			err = avro.Unmarshal(schema, data, &got)
+			require.NoError(t, err)
+
+			assert.Equal(t, 1, got["id"])
+			assert.Equal(t, test.want, got["score"])
+		})
+	}
+}
+
+func TestProtobuf_NonFiniteFloatAsNull_Disabled(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "score", "type": ["null", "double"]}
+		]
+	}`)
+
+	data, err := avro.Marshal(schema, &testpb.BasicMessage{Id: 1, Score: math.Inf(1)})
+	require.NoError(t, err)
+
+	var got map[string]any
+	err = avro.Unmarshal(schema, data, &got)
+	require.NoError(t, err)
+
+	assert.Equal(t, math.Inf(1), got["score"])
+}
+
+func TestProtobuf_RejectNonFiniteFloats(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "AllTypesMessage",
+		"fields": [
+			{"name": "float_field", "type": "float"},
+			{"name": "double_field", "type": "double"}
+		]
+	}`)
+	api := avro.Config{RejectNonFiniteFloats: true}.Freeze()
+
+	tests := []struct {
+		name    string
+		msg     *testpb.AllTypesMessage
+		wantErr string
+	}{
+		{
+			name:    "NaN double",
+			msg:     &testpb.AllTypesMessage{DoubleField: math.NaN()},
+			wantErr: "double_field: protobuf field double_field value NaN is not finite",
+		},
+		{
+			name:    "positive infinity float",
+			msg:     &testpb.AllTypesMessage{FloatField: float32(math.Inf(1))},
+			wantErr: "float_field: protobuf field float_field value +Inf is not finite",
+		},
+		{
+			name:    "negative infinity double",
+			msg:     &testpb.AllTypesMessage{DoubleField: math.Inf(-1)},
+			wantErr: "double_field: protobuf field double_field value -Inf is not finite",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := api.Marshal(schema, test.msg)
+			assert.EqualError(t, err, test.wantErr)
+
+			// Without the option, non-finite values are encoded as is.
+			_, err = avro.Marshal(schema, test.msg)
+			assert.NoError(t, err)
+		})
+	}
+
+	_, err := api.Marshal(schema, &testpb.AllTypesMessage{FloatField: 1.5, DoubleField: math.MaxFloat64})
+	assert.NoError(t, err)
+}
+
+func TestProtobuf_NonFiniteFloatFunc(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "AllTypesMessage",
+		"fields": [
+			{"name": "float_field", "type": "float"},
+			{"name": "double_field", "type": "double"}
+		]
+	}`)
+	data, err := avro.Marshal(schema, &testpb.AllTypesMessage{FloatField: float32(math.Inf(1)), DoubleField: math.NaN()})
+	require.NoError(t, err)
+
+	api := avro.Config{ProtoNonFiniteFloatFunc: func(float64) float64 { return -1 }}.Freeze()
+
+	var got testpb.AllTypesMessage
+	err = api.Unmarshal(schema, data, &got)
+	require.NoError(t, err)
+
+	assert.Equal(t, float32(-1), got.FloatField)
+	assert.Equal(t, float64(-1), got.DoubleField)
+
+	err = avro.Unmarshal(schema, data, &got)
+	require.NoError(t, err)
+
+	assert.True(t, math.IsInf(float64(got.FloatField), 1))
+	assert.True(t, math.IsNaN(got.DoubleField))
+}
+
+func TestProtobuf_OneofSameTypeMembers_Ambiguous(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "LinkMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "target", "type": ["null", "string"]}
+		]
+	}`)
+
+	_, err := avro.Marshal(schema, &testpb.LinkMessage{Id: 1, Target: &testpb.LinkMessage_Url{Url: "https://example.com"}})
+	assert.ErrorContains(t, err, "fields text and url both map to union branch 1")
+
+	var decoded testpb.LinkMessage
+	err = avro.Unmarshal(schema, []byte{0x02, 0x02, 0x02, 'a'}, &decoded)
+	assert.ErrorContains(t, err, "fields text and url both map to union branch 1")
+}
+
+func TestProtobuf_OneofSameTypeMembers_NamedBranches(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "LinkMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{
+				"name": "target",
+				"type": [
+					"null",
+					{"type": "record", "name": "text", "fields": [{"name": "text", "type": "string"}]},
+					{"type": "record", "name": "url", "fields": [{"name": "url", "type": "string"}]}
+				]
+			}
+		]
+	}`)
+
+	tests := []struct {
+		name string
+		msg  *testpb.LinkMessage
+		want any
+	}{
+		{
+			name: "text",
+			msg:  &testpb.LinkMessage{Id: 1, Target: &testpb.LinkMessage_Text{Text: "hello"}},
+			want: map[string]any{"text": map[string]any{"text": "hello"}},
+		},
+		{
+			name: "url",
+			msg:  &testpb.LinkMessage{Id: 2, Target: &testpb.LinkMessage_Url{Url: "https://example.com"}},
+			want: map[string]any{"url": map[string]any{"url": "https://example.com"}},
+		},
+		{
+			name: "unset",
+			msg:  &testpb.LinkMessage{Id: 3},
+			want: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := avro.Marshal(schema, test.msg)
+			require.NoError(t, err)
+
+			var generic map[string]any
+			err = avro.Unmarshal(schema, data, &generic)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, generic["target"])
+
+			var decoded testpb.LinkMessage
+			err = avro.Unmarshal(schema, data, &decoded)
+			require.NoError(t, err)
+			assert.True(t, proto.Equal(test.msg, &decoded), "got %v, want %v", &decoded, test.msg)
+		})
+	}
+}
+
+func TestProtobuf_OneofSameTypeMembers_OneNamedBranch(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "LinkMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{
+				"name": "target",
+				"type": [
+					"null",
+					"string",
+					{"type": "record", "name": "url", "fields": [{"name": "url", "type": "string"}]}
+				]
+			}
+		]
+	}`)
+
+	bindings, err := avro.DescribeOneofBindings(schema, &testpb.LinkMessage{})
+	require.NoError(t, err)
+	assert.Equal(t, []avro.OneofBinding{
+		{Oneof: "target", Field: "target", Branches: map[string]int{"text": 1, "url": 2}},
+	}, bindings)
+
+	for _, msg := range []*testpb.LinkMessage{
+		{Id: 1, Target: &testpb.LinkMessage_Text{Text: "hello"}},
+		{Id: 2, Target: &testpb.LinkMessage_Url{Url: "https://example.com"}},
+	} {
+		data, err := avro.Marshal(schema, msg)
+		require.NoError(t, err)
+
+		var decoded testpb.LinkMessage
+		err = avro.Unmarshal(schema, data, &decoded)
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(msg, &decoded), "got %v, want %v", &decoded, msg)
+	}
+}
+
+func TestProtobuf_OneofWithMessageMessage_BranchOrder(t *testing.T) {
+	defer ConfigTeardown()
+
+	// The union lists SimpleProfile before BasicMessage, the reverse of the
+	// oneof declaration order.
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "OneofWithMessageMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{
+				"name": "data",
+				"type": [
+					"null",
+					{
+						"type": "record",
+						"name": "SimpleProfile",
+						"fields": [
+							{"name": "user_id", "type": "int"},
+							{"name": "bio", "type": "string"},
+							{"name": "followers", "type": "int"}
+						]
+					},
+					{
+						"type": "record",
+						"name": "BasicMessage",
+						"fields": [
+							{"name": "id", "type": "int"},
+							{"name": "name", "type": "string"},
+							{"name": "active", "type": "boolean"},
+							{"name": "score", "type": "double"}
+						]
+					},
+					"string"
+				]
+			}
+		]
+	}`)
+
+	bindings, err := avro.DescribeOneofBindings(schema, &testpb.OneofWithMessageMessage{})
+	require.NoError(t, err)
+	assert.Equal(t, []avro.OneofBinding{
+		{Oneof: "data", Field: "data", Branches: map[string]int{"description": 3, "user": 2, "profile": 1}},
+	}, bindings)
+
+	msgs := []*testpb.OneofWithMessageMessage{
+		{
+			Id:   1,
+			Data: &testpb.OneofWithMessageMessage_User{User: &testpb.BasicMessage{Id: 10, Name: "Jane", Active: true, Score: 1.5}},
+		},
+		{
+			Id:   2,
+			Data: &testpb.OneofWithMessageMessage_Profile{Profile: &testpb.SimpleProfile{UserId: 10, Bio: "bio", Followers: 3}},
+		},
+		{
+			Id:   3,
+			Data: &testpb.OneofWithMessageMessage_Description{Description: "desc"},
+		},
+	}
+	for _, msg := range msgs {
+		data, err := avro.Marshal(schema, msg)
+		require.NoError(t, err)
+
+		var decoded testpb.OneofWithMessageMessage
+		err = avro.Unmarshal(schema, data, &decoded)
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(msg, &decoded), "got %v, want %v", &decoded, msg)
+	}
+}
+
+type protoIdentified interface {
+	GetId() int32
+}
+
+func TestProtobuf_DecodeIntoInterfaceField(t *testing.T) {
+	defer ConfigTeardown()
+
+	// The record full name matches the protobuf message full name.
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "Envelope",
+		"fields": [
+			{"name": "topic", "type": "string"},
+			{
+				"name": "payload",
+				"type": {
+					"type": "record",
+					"name": "BasicMessage",
+					"namespace": "testpb",
+					"fields": [
+						{"name": "id", "type": "int"},
+						{"name": "name", "type": "string"},
+						{"name": "active", "type": "boolean"},
+						{"name": "score", "type": "double"}
+					]
+				}
+			}
+		]
+	}`)
+
+	type Envelope struct {
+		Topic   string          `avro:"topic"`
+		Payload protoIdentified `avro:"payload"`
+	}
+
+	data, err := avro.Marshal(schema, map[string]any{
+		"topic":   "users",
+		"payload": map[string]any{"id": 42, "name": "Jane", "active": true, "score": 1.5},
+	})
+	require.NoError(t, err)
+
+	var got Envelope
+	err = avro.Unmarshal(schema, data, &got)
+	require.NoError(t, err)
+
+	assert.Equal(t, "users", got.Topic)
+	require.IsType(t, &testpb.BasicMessage{}, got.Payload)
+	assert.True(t, proto.Equal(&testpb.BasicMessage{Id: 42, Name: "Jane", Active: true, Score: 1.5}, got.Payload.(*testpb.BasicMessage)))
+}
+
+func TestProtobuf_DecodeIntoInterfaceField_UnknownMessage(t *testing.T) {
+	defer ConfigTeardown()
+
+	// Without a namespace the record does not name a registered protobuf message.
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "Envelope",
+		"fields": [
+			{
+				"name": "payload",
+				"type": {
+					"type": "record",
+					"name": "BasicMessage",
+					"fields": [{"name": "id", "type": "int"}]
+				}
+			}
+		]
+	}`)
+
+	type Envelope struct {
+		Payload protoIdentified `avro:"payload"`
+	}
+
+	var got Envelope
+	err := avro.Unmarshal(schema, []byte{0x54}, &got)
+
+	assert.ErrorContains(t, err, "can not unmarshal into nil")
+}
+
+func TestProtobuf_ValidateUTF8(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "name", "type": "string"}
+		]
+	}`)
+	// id=1, name="a\xffb"
+	data := []byte{0x02, 0x06, 'a', 0xff, 'b'}
+
+	var decoded testpb.BasicMessage
+	err := avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, "a\xffb", decoded.Name)
+
+	api := avro.Config{ProtoValidateUTF8: true}.Freeze()
+
+	var invalid testpb.BasicMessage
+	err = api.Unmarshal(schema, data, &invalid)
+	assert.ErrorContains(t, err, "protobuf field name value is not valid UTF-8")
+
+	var valid testpb.BasicMessage
+	err = api.Unmarshal(schema, []byte{0x02, 0x08, 'J', 0xc3, 0xa9, 'n'}, &valid)
+	require.NoError(t, err)
+	assert.Equal(t, "Jén", valid.Name)
+}
+
+func TestProtobuf_ListElementFunc(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "ListMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "tags", "type": {"type": "array", "items": "string"}},
+			{"name": "numbers", "type": {"type": "array", "items": "int"}}
+		]
+	}`)
+
+	const n = 1_000_000
+	original := &testpb.ListMessage{Id: 1, Tags: []string{"a", "b"}, Numbers: make([]int32, n)}
+	for i := range original.Numbers {
+		original.Numbers[i] = int32(i)
+	}
+	data, err := avro.Marshal(schema, original)
+	require.NoError(t, err)
+
+	var (
+		count int
+		sum   int64
+		tags  []string
+	)
+	api := avro.Config{
+		ProtoListElementFunc: func(field string, elem any) error {
+			switch field {
+			case "testpb.ListMessage.numbers":
+				count++
+				sum += int64(elem.(int32))
+			case "testpb.ListMessage.tags":
+				tags = append(tags, elem.(string))
+			}
+			return nil
+		},
+	}.Freeze()
+
+	var decoded testpb.ListMessage
+	err = api.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, n, count)
+	assert.Equal(t, int64(n)*(n-1)/2, sum)
+	assert.Equal(t, []string{"a", "b"}, tags)
+	assert.Equal(t, int32(1), decoded.Id)
+	assert.Empty(t, decoded.Tags)
+	assert.Empty(t, decoded.Numbers)
+}
+
+func TestProtobuf_ListElementFunc_Messages(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "TreeNode",
+		"fields": [
+			{"name": "value", "type": "int"},
+			{"name": "children", "type": {"type": "array", "items": "TreeNode"}}
+		]
+	}`)
+
+	original := &testpb.TreeNode{
+		Value:    1,
+		Children: []*testpb.TreeNode{{Value: 2}, {Value: 3}},
+	}
+	data, err := avro.Marshal(schema, original)
+	require.NoError(t, err)
+
+	var got []*testpb.TreeNode
+	api := avro.Config{
+		ProtoListElementFunc: func(_ string, elem any) error {
+			got = append(got, elem.(*testpb.TreeNode))
+			return nil
+		},
+	}.Freeze()
+
+	var decoded testpb.TreeNode
+	err = api.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	require.Len(t, got, 2)
+	assert.Equal(t, int32(2), got[0].Value)
+	assert.Equal(t, int32(3), got[1].Value)
+}
+
+func TestProtobuf_ListElementFunc_Error(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "ListMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "numbers", "type": {"type": "array", "items": "int"}}
+		]
+	}`)
+
+	data, err := avro.Marshal(schema, &testpb.ListMessage{Id: 1, Numbers: []int32{1, 2, 3, 4}})
+	require.NoError(t, err)
+
+	var count int
+	api := avro.Config{
+		ProtoListElementFunc: func(_ string, _ any) error {
+			count++
+			if count == 2 {
+				return errors.New("test")
+			}
+			return nil
+		},
+	}.Freeze()
+
+	var decoded testpb.ListMessage
+	err = api.Unmarshal(schema, data, &decoded)
+
+	assert.ErrorContains(t, err, "test")
+	assert.Equal(t, 2, count)
+}
+
+func TestProtobuf_StringInterner(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "ListMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "tags", "type": {"type": "array", "items": "string"}}
+		]
+	}`)
+	data, err := avro.Marshal(schema, &testpb.ListMessage{Id: 1, Tags: []string{"dup", "dup", "other"}})
+	require.NoError(t, err)
+
+	var plain testpb.ListMessage
+	err = avro.Unmarshal(schema, data, &plain)
+	require.NoError(t, err)
+	assert.NotSame(t, unsafe.StringData(plain.Tags[0]), unsafe.StringData(plain.Tags[1]))
+
+	api := avro.Config{
+		ProtoStringInterner: func(s string) string {
+			return unique.Make(s).Value()
+		},
+	}.Freeze()
+
+	var first, second testpb.ListMessage
+	err = api.Unmarshal(schema, data, &first)
+	require.NoError(t, err)
+	err = api.Unmarshal(schema, data, &second)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"dup", "dup", "other"}, first.Tags)
+	assert.Same(t, unsafe.StringData(first.Tags[0]), unsafe.StringData(first.Tags[1]))
+	assert.Same(t, unsafe.StringData(first.Tags[0]), unsafe.StringData(second.Tags[0]))
+	assert.NotSame(t, unsafe.StringData(first.Tags[0]), unsafe.StringData(first.Tags[2]))
+}
+
+func TestProtobuf_ExternalNamedTypeReference(t *testing.T) {
+	defer ConfigTeardown()
+
+	// BasicMessage is registered in the cache before NestedMessage refers to it.
+	cache := &avro.SchemaCache{}
+	_, err := avro.ParseWithCache(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"namespace": "com.example",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "name", "type": "string"},
+			{"name": "active", "type": "boolean"},
+			{"name": "score", "type": "double"}
+		]
+	}`, "", cache)
+	require.NoError(t, err)
+
+	schema, err := avro.ParseWithCache(`{
+		"type": "record",
+		"name": "NestedMessage",
+		"namespace": "com.example",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "title", "type": "string"},
+			{"name": "author", "type": ["null", "BasicMessage"]}
+		]
+	}`, "", cache)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		msg  *testpb.NestedMessage
+	}{
+		{
+			name: "set",
+			msg: &testpb.NestedMessage{
+				Id:     1,
+				Title:  "Article",
+				Author: &testpb.BasicMessage{Id: 2, Name: "Jane", Active: true, Score: 4.5},
+			},
+		},
+		{
+			name: "unset",
+			msg:  &testpb.NestedMessage{Id: 1, Title: "Article"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := avro.Marshal(schema, test.msg)
+			require.NoError(t, err)
+
+			var decoded testpb.NestedMessage
+			err = avro.Unmarshal(schema, data, &decoded)
+			require.NoError(t, err)
+
+			assert.True(t, proto.Equal(test.msg, &decoded), "got %v, want %v", &decoded, test.msg)
+		})
+	}
+}
+
+func TestProtobuf_ExternalNamedTypeReferenceFromFiles(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema, err := avro.ParseFiles("testdata/protobuf/basic-message.avsc", "testdata/protobuf/nested-message.avsc")
+	require.NoError(t, err)
+
+	original := &testpb.NestedMessage{
+		Id:     1,
+		Title:  "Article",
+		Author: &testpb.BasicMessage{Id: 2, Name: "Jane", Active: true, Score: 4.5},
+	}
+
+	data, err := avro.Marshal(schema, original)
+	require.NoError(t, err)
+
+	var decoded testpb.NestedMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	assert.True(t, proto.Equal(original, &decoded), "got %v, want %v", &decoded, original)
+}
+
+func TestProtobuf_WriteDefaultsForMissing(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "count", "type": "int"},
+			{"name": "label", "type": "string"},
+			{"name": "enabled", "type": "boolean"},
+			{"name": "ratio", "type": "double", "default": 0.5},
+			{"name": "note", "type": ["string", "null"]},
+			{"name": "kind", "type": {"type": "enum", "name": "Kind", "symbols": ["A", "B"]}},
+			{"name": "values", "type": {"type": "array", "items": "long"}}
+		]
+	}`)
+
+	_, err := avro.Marshal(schema, &testpb.BasicMessage{Id: 1})
+	require.ErrorContains(t, err, "required field count not found in protobuf message")
+
+	api := avro.Config{ProtoWriteDefaultsForMissing: true}.Freeze()
+
+	data, err := api.Marshal(schema, &testpb.BasicMessage{Id: 1})
+	require.NoError(t, err)
+
+	var got map[string]any
+	err = avro.Unmarshal(schema, data, &got)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"id":      1,
+		"count":   0,
+		"label":   "",
+		"enabled": false,
+		"ratio":   0.5,
+		"note":    nil,
+		"kind":    "A",
+		"values":  []any{},
+	}, got)
+}
+
+func TestProtobuf_WriteDefaultsForMissing_Record(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{
+				"name": "extra",
+				"type": {"type": "record", "name": "Extra", "fields": [{"name": "a", "type": "int"}]}
+			}
+		]
+	}`)
+	api := avro.Config{ProtoWriteDefaultsForMissing: true}.Freeze()
+
+	_, err := api.Marshal(schema, &testpb.BasicMessage{Id: 1})
+	assert.ErrorContains(t, err, "required field extra not found in protobuf message")
+
+	// Decoding is unaffected.
+	var decoded testpb.BasicMessage
+	err = api.Unmarshal(schema, []byte{0x02, 0x04}, &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), decoded.Id)
+}
+
+func TestProtobuf_DerivedField(t *testing.T) {
+	defer ConfigTeardown()
+
+	api := avro.Config{
+		ProtoDerivedFields: map[string]func(proto.Message) (any, error){
+			"testpb.BasicMessage.display": func(msg proto.Message) (any, error) {
+				m := msg.(*testpb.BasicMessage)
+				return fmt.Sprintf("#%d %s", m.Id, m.Name), nil
+			},
+		},
+	}.Freeze()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "name", "type": "string"},
+			{"name": "display", "type": "string"}
+		]
+	}`)
+
+	original := &testpb.BasicMessage{Id: 7, Name: "Jane"}
+
+	data, err := api.Marshal(schema, original)
+	require.NoError(t, err)
+
+	var generic map[string]any
+	err = api.Unmarshal(schema, data, &generic)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"id": 7, "name": "Jane", "display": "#7 Jane"}, generic)
+
+	var decoded testpb.BasicMessage
+	err = api.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(original, &decoded), "got %v, want %v", &decoded, original)
+}
+
+func TestProtobuf_DerivedField_Error(t *testing.T) {
+	defer ConfigTeardown()
+
+	api := avro.Config{
+		ProtoDerivedFields: map[string]func(proto.Message) (any, error){
+			"testpb.BasicMessage.display": func(proto.Message) (any, error) {
+				return nil, errors.New("test")
+			},
+		},
+	}.Freeze()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "display", "type": "string"}
+		]
+	}`)
+
+	_, err := api.Marshal(schema, &testpb.BasicMessage{Id: 7})
+	assert.ErrorContains(t, err, "derive field display: test")
+}
+
+func TestProtobuf_DecodeUnionNullBranchOrder(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "OptionalMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "name", "type": ["string", "null"]},
+			{"name": "age", "type": ["int", "null"]}
+		]
+	}`)
+
+	// id=1, name=null (branch 1), age=30 (branch 0)
+	data := []byte{0x02, 0x02, 0x00, 0x3c}
+
+	decoded := testpb.OptionalMessage{Name: proto.String("stale")}
+	err := avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), decoded.Id)
+	assert.Nil(t, decoded.Name)
+	require.NotNil(t, decoded.Age)
+	assert.Equal(t, int32(30), *decoded.Age)
+
+	// Fields without presence are set to their zero value on null.
+	basicSchema := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "score", "type": ["double", "null"]}
+		]
+	}`)
+
+	basic := testpb.BasicMessage{Score: 3}
+	err = avro.Unmarshal(basicSchema, []byte{0x02, 0x02}, &basic)
+	require.NoError(t, err)
+	assert.Equal(t, float64(0), basic.Score)
+}
+
+func TestProtobuf_NonNullableScalarUnion(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "AllTypesMessage",
+		"fields": [
+			{"name": "int64_field", "type": ["int", "long"]},
+			{"name": "sint64_field", "type": ["int", "long"]}
+		]
+	}`)
+
+	long, err := avro.Marshal(avro.MustParse("long"), int64(-1<<40))
+	require.NoError(t, err)
+	// int64_field is the int branch holding -3, sint64_field the long branch.
+	data := append([]byte{0x00, 0x05, 0x02}, long...)
+
+	var decoded testpb.AllTypesMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, int64(-3), decoded.Int64Field)
+	assert.Equal(t, int64(-1<<40), decoded.Sint64Field)
+
+	got, err := avro.Marshal(schema, &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+}
+
+func TestProtobuf_PresenceField_RoundTrip(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "OptionalMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "has_name", "type": "boolean", "protoPresence": "name"},
+			{"name": "name", "type": "string"},
+			{"name": "age", "type": "int"},
+			{"name": "has_age", "type": "boolean", "protoPresence": "age"}
+		]
+	}`)
+
+	tests := []struct {
+		name string
+		msg  *testpb.OptionalMessage
+		want map[string]any
+	}{
+		{
+			name: "set",
+			msg:  &testpb.OptionalMessage{Id: 1, Name: proto.String("Jane"), Age: proto.Int32(30)},
+			want: map[string]any{"id": 1, "has_name": true, "name": "Jane", "age": 30, "has_age": true},
+		},
+		{
+			name: "set to zero",
+			msg:  &testpb.OptionalMessage{Id: 2, Name: proto.String(""), Age: proto.Int32(0)},
+			want: map[string]any{"id": 2, "has_name": true, "name": "", "age": 0, "has_age": true},
+		},
+		{
+			name: "unset",
+			msg:  &testpb.OptionalMessage{Id: 3},
+			want: map[string]any{"id": 3, "has_name": false, "name": "", "age": 0, "has_age": false},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := avro.Marshal(schema, test.msg)
+			require.NoError(t, err)
+
+			var generic map[string]any
+			err = avro.Unmarshal(schema, data, &generic)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, generic)
+
+			var decoded testpb.OptionalMessage
+			err = avro.Unmarshal(schema, data, &decoded)
+			require.NoError(t, err)
+			assert.True(t, proto.Equal(test.msg, &decoded), "got %v, want %v", &decoded, test.msg)
+		})
+	}
+}
+
+func TestProtobuf_PresenceField_Invalid(t *testing.T) {
+	defer ConfigTeardown()
+
+	tests := []struct {
+		name    string
+		field   string
+		wantErr string
+	}{
+		{
+			name:    "not boolean",
+			field:   `{"name": "has_name", "type": "int", "protoPresence": "name"}`,
+			wantErr: "avro: presence field has_name must be a boolean, got int",
+		},
+		{
+			name:    "unknown field",
+			field:   `{"name": "has_nick", "type": "boolean", "protoPresence": "nick"}`,
+			wantErr: "avro: presence field has_nick refers to unknown field nick of testpb.OptionalMessage",
+		},
+		{
+			name:    "no presence",
+			field:   `{"name": "has_id", "type": "boolean", "protoPresence": "id"}`,
+			wantErr: "avro: presence field has_id refers to field id of testpb.OptionalMessage, which has no presence",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schema := avro.MustParse(`{
+				"type": "record",
+				"name": "OptionalMessage",
+				"fields": [{"name": "id", "type": "int"}, ` + test.field + `]
+			}`)
+
+			_, err := avro.Marshal(schema, &testpb.OptionalMessage{Id: 1})
+			assert.EqualError(t, err, test.wantErr)
+		})
+	}
+}
+
+func TestProtobuf_UnknownFields(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "name", "type": "string"},
+			{"name": "unknown_fields", "type": "bytes", "protoUnknownFields": true}
+		]
+	}`)
+
+	wire, err := proto.Marshal(&testpb.BasicMessage{Id: 1, Name: "known"})
+	require.NoError(t, err)
+	wire = protowire.AppendTag(wire, 99, protowire.BytesType)
+	wire = protowire.AppendString(wire, "from a newer producer")
+	wire = protowire.AppendTag(wire, 100, protowire.VarintType)
+	wire = protowire.AppendVarint(wire, 42)
+
+	var original testpb.BasicMessage
+	require.NoError(t, proto.Unmarshal(wire, &original))
+	require.NotEmpty(t, original.ProtoReflect().GetUnknown())
+
+	data, err := avro.Marshal(schema, &original)
+	require.NoError(t, err)
+
+	var decoded testpb.BasicMessage
+	err = avro.Unmarshal(schema, data, &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, original.ProtoReflect().GetUnknown(), decoded.ProtoReflect().GetUnknown())
+
+	got, err := proto.Marshal(&decoded)
+	require.NoError(t, err)
+	assert.Equal(t, wire, got)
+}
+
+func TestProtobuf_UnknownFields_Invalid(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "unknown_fields", "type": "string", "protoUnknownFields": true}
+		]
+	}`)
+
+	_, err := avro.Marshal(schema, &testpb.BasicMessage{Id: 1})
+	assert.EqualError(t, err, "avro: field unknown_fields of testpb.BasicMessage holding unknown fields must be bytes, got string")
+}
+
+func TestProtobuf_ImplicitZeroAsNull(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "OptionalMessage",
+		"fields": [
+			{"name": "id", "type": ["null", "int"]},
+			{"name": "age", "type": ["null", "int"]}
+		]
+	}`)
+	strict := avro.Config{ProtoImplicitZeroAsNull: true}.Freeze()
+
+	tests := []struct {
+		name       string
+		api        avro.API
+		msg        *testpb.OptionalMessage
+		wantID     any
+		wantAge    any
+		wantDecode *testpb.OptionalMessage
+	}{
+		{
+			name:       "default zero",
+			api:        avro.DefaultConfig,
+			msg:        &testpb.OptionalMessage{Id: 0, Age: proto.Int32(0)},
+			wantID:     0,
+			wantAge:    0,
+			wantDecode: &testpb.OptionalMessage{Id: 0, Age: proto.Int32(0)},
+		},
+		{
+			name:       "default unset",
+			api:        avro.DefaultConfig,
+			msg:        &testpb.OptionalMessage{},
+			wantID:     0,
+			wantAge:    nil,
+			wantDecode: &testpb.OptionalMessage{},
+		},
+		{
+			name:       "implicit zero as null",
+			api:        strict,
+			msg:        &testpb.OptionalMessage{Id: 0, Age: proto.Int32(0)},
+			wantID:     nil,
+			wantAge:    0,
+			wantDecode: &testpb.OptionalMessage{Id: 0, Age: proto.Int32(0)},
+		},
+		{
+			name:       "implicit non-zero",
+			api:        strict,
+			msg:        &testpb.OptionalMessage{Id: 5},
+			wantID:     5,
+			wantAge:    nil,
+			wantDecode: &testpb.OptionalMessage{Id: 5},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := test.api.Marshal(schema, test.msg)
+			require.NoError(t, err)
+
+			var generic map[string]any
+			err = avro.Unmarshal(schema, data, &generic)
+			require.NoError(t, err)
+			assert.Equal(t, test.wantID, generic["id"])
+			assert.Equal(t, test.wantAge, generic["age"])
+
+			var decoded testpb.OptionalMessage
+			err = test.api.Unmarshal(schema, data, &decoded)
+			require.NoError(t, err)
+			assert.True(t, proto.Equal(test.wantDecode, &decoded), "got %v, want %v", &decoded, test.wantDecode)
+		})
+	}
+}
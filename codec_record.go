@@ -9,12 +9,13 @@ import (
 	"unsafe"
 
 	"github.com/modern-go/reflect2"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 func createDecoderOfRecord(d *decoderContext, schema Schema, typ reflect2.Type) ValDecoder {
 	switch typ.Kind() {
 	case reflect.Struct:
-		if dec := createDecoderOfProtobuf(schema, typ); dec != nil {
+		if dec := createDecoderOfProtobuf(d, schema, typ); dec != nil {
 			return dec
 		}
 		if dec := createDecoderOfAvroMarshaler(schema, typ); dec != nil {
@@ -34,7 +35,11 @@ func createDecoderOfRecord(d *decoderContext, schema Schema, typ reflect2.Type)
 
 	case reflect.Interface:
 		if ifaceType, ok := typ.(*reflect2.UnsafeIFaceType); ok {
-			return &recordIfaceDecoder{schema: schema, valType: ifaceType}
+			return &recordIfaceDecoder{
+				schema:    schema,
+				valType:   ifaceType,
+				protoType: protoMessageTypeOf(schema.(*RecordSchema), ifaceType),
+			}
 		}
 	}
 
@@ -44,7 +49,7 @@ func createDecoderOfRecord(d *decoderContext, schema Schema, typ reflect2.Type)
 func createEncoderOfRecord(e *encoderContext, schema *RecordSchema, typ reflect2.Type) ValEncoder {
 	switch typ.Kind() {
 	case reflect.Struct:
-		if enc := createEncoderOfProtobuf(schema, typ); enc != nil {
+		if enc := createEncoderOfProtobuf(e, schema, typ); enc != nil {
 			return enc
 		}
 		if enc := createEncoderOfAvroMarshaler(schema, typ); enc != nil {
@@ -421,15 +426,20 @@ func (e *recordMapEncoder) Encode(ptr unsafe.Pointer, w *Writer) {
 }
 
 type recordIfaceDecoder struct {
-	schema  Schema
-	valType *reflect2.UnsafeIFaceType
+	schema    Schema
+	valType   *reflect2.UnsafeIFaceType
+	protoType protoreflect.MessageType // The protobuf message allocated into a nil interface, if any.
 }
 
 func (d *recordIfaceDecoder) Decode(ptr unsafe.Pointer, r *Reader) {
 	obj := d.valType.UnsafeIndirect(ptr)
 	if reflect2.IsNil(obj) {
-		r.ReportError("decode non empty interface", "can not unmarshal into nil")
-		return
+		if d.protoType == nil {
+			r.ReportError("decode non empty interface", "can not unmarshal into nil")
+			return
+		}
+		obj = d.protoType.New().Interface()
+		reflect.NewAt(d.valType.Type1(), ptr).Elem().Set(reflect.ValueOf(obj))
 	}
 
 	r.ReadVal(d.schema, obj)
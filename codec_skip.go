@@ -6,7 +6,17 @@ import (
 )
 
 func createSkipDecoder(schema Schema) ValDecoder {
+	return skipDecoderOfType(map[string]ValDecoder{}, schema)
+}
+
+// skipDecoderOfType creates a skip decoder for schema. Record decoders are
+// tracked by full name in seen so recursive references resolve to the
+// decoder already being built.
+func skipDecoderOfType(seen map[string]ValDecoder, schema Schema) ValDecoder {
 	switch schema.Type() {
+	case Null:
+		return &nullCodec{}
+
 	case Boolean:
 		return &boolSkipDecoder{}
 
@@ -29,22 +39,26 @@ func createSkipDecoder(schema Schema) ValDecoder {
 		return &bytesSkipDecoder{}
 
 	case Record:
-		return skipDecoderOfRecord(schema)
+		return skipDecoderOfRecord(seen, schema)
 
 	case Ref:
-		return createSkipDecoder(schema.(*RefSchema).Schema())
+		rec := schema.(*RefSchema).Schema()
+		if dec, ok := seen[rec.FullName()]; ok {
+			return dec
+		}
+		return skipDecoderOfType(seen, rec)
 
 	case Enum:
 		return &enumSkipDecoder{symbols: schema.(*EnumSchema).Symbols()}
 
 	case Array:
-		return skipDecoderOfArray(schema)
+		return skipDecoderOfArray(seen, schema)
 
 	case Map:
-		return skipDecoderOfMap(schema)
+		return skipDecoderOfMap(seen, schema)
 
 	case Union:
-		return skipDecoderOfUnion(schema)
+		return skipDecoderOfUnion(seen, schema)
 
 	case Fixed:
 		return &fixedSkipDecoder{size: schema.(*FixedSchema).Size()}
@@ -96,17 +110,18 @@ func (c *bytesSkipDecoder) Decode(_ unsafe.Pointer, r *Reader) {
 	r.SkipBytes()
 }
 
-func skipDecoderOfRecord(schema Schema) ValDecoder {
+func skipDecoderOfRecord(seen map[string]ValDecoder, schema Schema) ValDecoder {
 	rec := schema.(*RecordSchema)
 
-	decoders := make([]ValDecoder, len(rec.Fields()))
+	dec := &recordSkipDecoder{}
+	seen[rec.FullName()] = dec
+
+	dec.decoders = make([]ValDecoder, len(rec.Fields()))
 	for i, field := range rec.Fields() {
-		decoders[i] = createSkipDecoder(field.Type())
+		dec.decoders[i] = skipDecoderOfType(seen, field.Type())
 	}
 
-	return &recordSkipDecoder{
-		decoders: decoders,
-	}
+	return dec
 }
 
 type recordSkipDecoder struct {
@@ -127,9 +142,9 @@ func (c *enumSkipDecoder) Decode(_ unsafe.Pointer, r *Reader) {
 	r.SkipInt()
 }
 
-func skipDecoderOfArray(schema Schema) ValDecoder {
+func skipDecoderOfArray(seen map[string]ValDecoder, schema Schema) ValDecoder {
 	arr := schema.(*ArraySchema)
-	decoder := createSkipDecoder(arr.Items())
+	decoder := skipDecoderOfType(seen, arr.Items())
 
 	return &sliceSkipDecoder{
 		decoder: decoder,
@@ -158,9 +173,9 @@ func (d *sliceSkipDecoder) Decode(_ unsafe.Pointer, r *Reader) {
 	}
 }
 
-func skipDecoderOfMap(schema Schema) ValDecoder {
+func skipDecoderOfMap(seen map[string]ValDecoder, schema Schema) ValDecoder {
 	m := schema.(*MapSchema)
-	decoder := createSkipDecoder(m.Values())
+	decoder := skipDecoderOfType(seen, m.Values())
 
 	return &mapSkipDecoder{
 		decoder: decoder,
@@ -190,30 +205,32 @@ func (d *mapSkipDecoder) Decode(_ unsafe.Pointer, r *Reader) {
 	}
 }
 
-func skipDecoderOfUnion(schema Schema) ValDecoder {
+func skipDecoderOfUnion(seen map[string]ValDecoder, schema Schema) ValDecoder {
 	union := schema.(*UnionSchema)
 
+	decoders := make([]ValDecoder, len(union.Types()))
+	for i, typ := range union.Types() {
+		decoders[i] = skipDecoderOfType(seen, typ)
+	}
+
 	return &unionSkipDecoder{
-		schema: union,
+		schema:   union,
+		decoders: decoders,
 	}
 }
 
 type unionSkipDecoder struct {
-	schema *UnionSchema
+	schema   *UnionSchema
+	decoders []ValDecoder
 }
 
 func (d *unionSkipDecoder) Decode(_ unsafe.Pointer, r *Reader) {
-	_, resSchema := getUnionSchema(d.schema, r)
+	idx, resSchema := getUnionSchema(d.schema, r)
 	if resSchema == nil {
 		return
 	}
 
-	// In a null case, just return
-	if resSchema.Type() == Null {
-		return
-	}
-
-	createSkipDecoder(resSchema).Decode(nil, r)
+	d.decoders[idx].Decode(nil, r)
 }
 
 type fixedSkipDecoder struct {
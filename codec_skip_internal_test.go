@@ -13,3 +13,23 @@ func TestCreateSkipDecoder_UnsupportedType(t *testing.T) {
 
 	assert.IsType(t, &errorDecoder{}, dec)
 }
+
+func TestCreateSkipDecoder_RecursiveRecord(t *testing.T) {
+	schema := MustParse(`{
+		"type": "record",
+		"name": "Node",
+		"fields": [
+			{"name": "value", "type": "int"},
+			{"name": "next", "type": ["null", "Node"]}
+		]
+	}`)
+	// value=1, next=Node{value=2, next=null}, then a trailing int.
+	data := []byte{0x02, 0x02, 0x04, 0x00, 0x36}
+	r := NewReader(nil, 0).Reset(data)
+
+	dec := createSkipDecoder(schema)
+	dec.Decode(nil, r)
+
+	assert.NoError(t, r.Error)
+	assert.Equal(t, int32(27), r.ReadInt())
+}
@@ -4,16 +4,104 @@ import (
 	"errors"
 	"io"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/modern-go/reflect2"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
-	defaultMaxByteSliceSize = 1_048_576 // 1 MiB
+	defaultMaxByteSliceSize       = 1_048_576 // 1 MiB
+	defaultProtoMaxRecursionDepth = 10_000
 )
 
 // DefaultConfig is the default API.
-var DefaultConfig = Config{}.Freeze()
+var DefaultConfig API = defaultAPI{}
+
+// defaultFrozenConfig holds the config DefaultConfig uses, replaced by SetDefaultConfig.
+var defaultFrozenConfig = func() *atomic.Pointer[frozenConfig] {
+	var p atomic.Pointer[frozenConfig]
+	p.Store(Config{}.Freeze().(*frozenConfig))
+	return &p
+}()
+
+// SetDefaultConfig freezes cfg and sets it as the config of DefaultConfig, the API used by
+// package level functions such as Marshal and Unmarshal, and by Readers and Writers created
+// without a config. It is safe to call while values are encoded or decoded, and calls already
+// running keep using the previous config. Types and type converters registered with the
+// previous config are not carried over. If DefaultConfig has been assigned another API,
+// SetDefaultConfig restores it, which is not safe for concurrent use.
+func SetDefaultConfig(cfg Config) {
+	defaultFrozenConfig.Store(cfg.Freeze().(*frozenConfig))
+	if _, ok := DefaultConfig.(defaultAPI); !ok {
+		DefaultConfig = defaultAPI{}
+	}
+}
+
+// defaultAPI is the API of DefaultConfig, using the config set by SetDefaultConfig.
+type defaultAPI struct{}
+
+func (defaultAPI) load() *frozenConfig {
+	return defaultFrozenConfig.Load()
+}
+
+func (d defaultAPI) Marshal(schema Schema, v any) ([]byte, error) {
+	return d.load().Marshal(schema, v)
+}
+
+func (d defaultAPI) Unmarshal(schema Schema, data []byte, v any) error {
+	return d.load().Unmarshal(schema, data, v)
+}
+
+func (d defaultAPI) NewEncoder(schema Schema, w io.Writer) *Encoder {
+	return d.load().NewEncoder(schema, w)
+}
+
+func (d defaultAPI) NewDecoder(schema Schema, r io.Reader) *Decoder {
+	return d.load().NewDecoder(schema, r)
+}
+
+func (d defaultAPI) DecoderOf(schema Schema, typ reflect2.Type) ValDecoder {
+	return d.load().DecoderOf(schema, typ)
+}
+
+func (d defaultAPI) EncoderOf(schema Schema, typ reflect2.Type) ValEncoder {
+	return d.load().EncoderOf(schema, typ)
+}
+
+func (d defaultAPI) Register(name string, obj any) {
+	d.load().Register(name, obj)
+}
+
+func (d defaultAPI) RegisterTypeConverters(convs ...TypeConverter) {
+	d.load().RegisterTypeConverters(convs...)
+}
+
+func (d defaultAPI) TypeOf(name string) (reflect2.Type, error) {
+	return d.load().TypeOf(name)
+}
+
+func (d defaultAPI) NamesOf(typ reflect2.Type) ([]string, error) {
+	return d.load().NamesOf(typ)
+}
+
+// frozenConfigOf returns the config of api, or false if api was not created by Config.Freeze.
+func frozenConfigOf(api API) (*frozenConfig, bool) {
+	if d, ok := api.(defaultAPI); ok {
+		return d.load(), true
+	}
+	cfg, ok := api.(*frozenConfig)
+	return cfg, ok
+}
+
+// mustFrozenConfig returns the config of api, panicking if api was not created by Config.Freeze.
+func mustFrozenConfig(api API) *frozenConfig {
+	if d, ok := api.(defaultAPI); ok {
+		return d.load()
+	}
+	return api.(*frozenConfig)
+}
 
 // Config customises how the codec should behave.
 type Config struct {
@@ -62,6 +150,149 @@ type Config struct {
 	// is left at its zero value instead of requiring a pointer type.
 	// This defaults to false for backward compatibility.
 	UnionNullValueAsZero bool
+
+	// DisableProtobufCodec makes protobuf generated structs use the native struct codec,
+	// mapping their exported Go fields by name or TagKey tag, instead of the protobuf
+	// codec, which uses protobuf reflection. Setting TagKey to "json" maps the fields by
+	// their protobuf names. Only scalar, repeated and map fields of matching Go types are
+	// supported, with none of the protobuf specific mappings (e.g. oneofs, enum names or
+	// timestamps).
+	DisableProtobufCodec bool
+
+	// ProtoEnumStripPrefix strips the enum type name prefix from protobuf enum
+	// value names (e.g. `STATUS_ACTIVE` becomes `ACTIVE` for an enum named `Status`)
+	// when mapping them to Avro enum symbols or strings. The prefix is re-added on decode.
+	ProtoEnumStripPrefix bool
+
+	// ProtoEnumSymbolFunc, when set, converts protobuf enum value names to Avro enum symbols
+	// or strings, after ProtoEnumStripPrefix is applied, to bridge differing naming
+	// conventions (e.g. `strings.ToLower` maps `ACTIVE` to `active`). On decode, the symbol is
+	// matched against the converted names of all the enum values, falling back to the
+	// protobuf name itself.
+	ProtoEnumSymbolFunc func(name string) string
+
+	// ProtoUnknownEnumAsNumber causes protobuf enum numbers without a known value, as held by
+	// messages from producers with a newer enum, to be encoded as their decimal number when
+	// mapped to an Avro string (e.g. "7"), instead of failing. On decode, a string holding a
+	// decimal number that matches no enum value is decoded as that number.
+	ProtoUnknownEnumAsNumber bool
+
+	// DisallowUnmappedProtoFields causes encoding a protobuf message to fail when
+	// the message has populated fields that are not covered by the Avro schema,
+	// instead of silently dropping them.
+	DisallowUnmappedProtoFields bool
+
+	// ProtoMatchJSONName allows Avro record fields to match protobuf fields by their
+	// JSON name (e.g. `userId` for `user_id`) when no field matches the exact protobuf name.
+	ProtoMatchJSONName bool
+
+	// ProtoMaxRecursionDepth is the maximum depth of nested protobuf messages the codec will
+	// decode or encode, defaulting to 10000. If this depth is exceeded, an error is returned.
+	// This can be disabled by setting a negative number.
+	ProtoMaxRecursionDepth int
+
+	// ProtoZeroCopyBytes makes protobuf bytes fields alias the input data when decoding
+	// with Unmarshal, instead of copying it. This is unsafe: the input data must not be
+	// modified or reused for as long as the decoded message is in use.
+	// This has no effect when decoding from an io.Reader.
+	ProtoZeroCopyBytes bool
+
+	// ProtoNarrowDoubleToFloat allows an Avro double to be decoded into a protobuf float
+	// field, narrowing the value.
+	ProtoNarrowDoubleToFloat bool
+
+	// ProtoStrictFloatNarrowing causes decoding to fail when narrowing an Avro double to
+	// a protobuf float overflows or loses precision. It requires ProtoNarrowDoubleToFloat.
+	ProtoStrictFloatNarrowing bool
+
+	// ProtoNonFiniteFloatAsNull causes protobuf float and double fields holding NaN or
+	// an infinity to be encoded as null when the Avro schema is a nullable union, for
+	// consumers that cannot represent non-finite numbers.
+	ProtoNonFiniteFloatAsNull bool
+
+	// RejectNonFiniteFloats causes encoding to fail when a protobuf float or double
+	// field holds NaN or an infinity, for consumers that reject non-finite numbers. Fields
+	// encoded as null by ProtoNonFiniteFloatAsNull are not rejected.
+	RejectNonFiniteFloats bool
+
+	// ProtoNonFiniteFloatFunc, when set, is called with each NaN or infinite value decoded
+	// into a protobuf float or double field, and returns the value to set instead, such as
+	// a sentinel like math.MaxFloat64.
+	ProtoNonFiniteFloatFunc func(f float64) float64
+
+	// ProtoValidateUTF8 causes decoding to fail when an Avro string decoded into a
+	// protobuf string field is not valid UTF-8.
+	ProtoValidateUTF8 bool
+
+	// ProtoListElementFunc, when set, is called with each element decoded into a repeated
+	// protobuf field instead of appending it to the field, which is left empty. This allows
+	// large arrays to be processed without holding them in memory. The function receives the
+	// full name of the protobuf field and the element, which is a proto.Message for message
+	// fields and the Go value of the field kind otherwise (e.g. int32 or string).
+	// Decoding waits for the function to return, and an error returned by it stops decoding
+	// and fails the decode call with its message.
+	ProtoListElementFunc func(field string, elem any) error
+
+	// ProtoStringInterner, when set, is called with each Avro string decoded into a
+	// protobuf string field, and the returned string is stored instead. It can return
+	// a previously seen equal string to share its memory, reducing the memory held by
+	// messages with repetitive string values. It must be safe for concurrent use.
+	ProtoStringInterner func(s string) string
+
+	// ProtoWriteDefaultsForMissing causes encoding a protobuf message to write a value for
+	// Avro record fields with no matching protobuf field, instead of failing. A nullable
+	// union is written as null, otherwise the field default is written, or the zero value
+	// of the field type (e.g. 0, "" or false) if it has none. Records without a default have
+	// no zero value, so encoding still fails for them.
+	ProtoWriteDefaultsForMissing bool
+
+	// ProtoImplicitZeroAsNull causes protobuf fields without presence (non-optional proto3
+	// scalars) holding their zero value to be encoded as null when the Avro schema is a
+	// nullable union. By default, only unset optional fields are encoded as null, and an
+	// implicit zero is encoded as the zero value, as it cannot be told apart from an explicit one.
+	ProtoImplicitZeroAsNull bool
+
+	// ProtoTimestampEpochOffset is the offset of the epoch of Avro long timestamps from the
+	// Unix epoch, for systems counting time from a different zero date. It is subtracted
+	// when encoding protobuf Timestamp and int64 fields to a timestamp logical type, and
+	// added when decoding them. Timestamps before the epoch are encoded as negative values.
+	ProtoTimestampEpochOffset time.Duration
+
+	// ProtoInt64AsString allows protobuf 64 bit integer fields to map to Avro strings holding
+	// the decimal value, as in the protobuf JSON mapping, so values beyond the precision of
+	// JavaScript numbers are not altered by consumers.
+	ProtoInt64AsString bool
+
+	// ProtoCoerceNumericStrings allows Avro strings to be decoded into protobuf integer,
+	// float and double fields, parsing their decimal form, for producers writing numbers
+	// as strings. Decoding fails if a string is not a valid number of the field type.
+	// This is lenient and lossy, as e.g. "1.0" and "1" decode to the same value.
+	ProtoCoerceNumericStrings bool
+
+	// SortMapKeys causes maps to be encoded in key order, so equal values always encode
+	// to the same bytes. Keys are sorted lexicographically, after conversion with MarshalText
+	// for keys implementing encoding.TextMarshaler. It also applies to protobuf map fields,
+	// where integer keys are sorted numerically (e.g. 2 before 10) and bool keys false first.
+	SortMapKeys bool
+
+	// ProtoDerivedFields maps Avro record fields to functions computing their value from
+	// the protobuf message when encoding, instead of reading a protobuf field of the same
+	// name, e.g. to enrich records with a field derived from several protobuf fields.
+	// The keys are the full name of the protobuf message and the Avro field name, separated
+	// by a dot (e.g. `example.User.full_name`). The returned value is encoded like any Go
+	// value with the field schema. Derived fields are skipped when decoding.
+	ProtoDerivedFields map[string]func(msg proto.Message) (any, error)
+
+	// ProtoResolveAny allows google.protobuf.Any fields to map to records of the packed
+	// message, usually in a union, resolving the message type in the global protobuf type
+	// registry by the record full name. By default, Any fields only map to records with a
+	// string type_url field and a bytes value field, holding the packed message as is.
+	ProtoResolveAny bool
+
+	// OnSkippedField is called with the name of each Avro record field that is skipped
+	// when decoding into a protobuf message, because the message has no matching field.
+	// This can be used to detect schema drift.
+	OnSkippedField func(name string)
 }
 
 // Freeze makes the configuration immutable.
@@ -137,6 +368,8 @@ type frozenConfig struct {
 	decoderCache sync.Map // map[cacheKey]ValDecoder
 	encoderCache sync.Map // map[cacheKey]ValEncoder
 
+	protoPlanCache sync.Map // map[protoPlanKey]*protoMessagePlan
+
 	readerPool *sync.Pool
 	writerPool *sync.Pool
 
@@ -161,6 +394,24 @@ func (c *frozenConfig) Marshal(schema Schema, v any) ([]byte, error) {
 	return copied, nil
 }
 
+// MarshalTo appends the Avro encoding of v to buf and returns the extended buffer.
+func (c *frozenConfig) MarshalTo(schema Schema, v any, buf []byte) ([]byte, error) {
+	writer := c.borrowWriter()
+	defer c.returnWriter(writer)
+
+	// Write into buf in place of the pooled buffer, which is restored afterwards.
+	pooled := writer.buf
+	writer.buf = buf
+	writer.WriteVal(schema, v)
+	result := writer.buf
+	writer.buf = pooled
+
+	if err := writer.Error; err != nil {
+		return buf, err
+	}
+	return result, nil
+}
+
 func (c *frozenConfig) borrowWriter() *Writer {
 	writer := c.writerPool.Get().(*Writer)
 	writer.Reset(nil)
@@ -188,6 +439,23 @@ func (c *frozenConfig) Unmarshal(schema Schema, data []byte, v any) error {
 	return err
 }
 
+// UnmarshalN is like Unmarshal, but also returns the number of bytes of data consumed.
+func (c *frozenConfig) UnmarshalN(schema Schema, data []byte, v any) (int, error) {
+	reader := c.borrowReader(data)
+	defer c.returnReader(reader)
+
+	reader.ReadVal(schema, v)
+	n, err := int(reader.BytesRead()), reader.Error
+
+	// Unlike Unmarshal, running out of data is reported, as the data is expected
+	// to hold further values and a cut off value would be silently partial.
+	if errors.Is(err, io.EOF) {
+		return n, io.ErrUnexpectedEOF
+	}
+
+	return n, err
+}
+
 func (c *frozenConfig) borrowReader(data []byte) *Reader {
 	reader := c.readerPool.Get().(*Reader)
 	reader.Reset(data)
@@ -303,6 +571,14 @@ func (c *frozenConfig) getMaxByteSliceSize() int {
 	return size
 }
 
+func (c *frozenConfig) getProtoMaxRecursionDepth() int {
+	depth := c.config.ProtoMaxRecursionDepth
+	if depth == 0 {
+		return defaultProtoMaxRecursionDepth
+	}
+	return depth
+}
+
 func (c *frozenConfig) getMaxSliceAllocSize() int {
 	size := c.config.MaxSliceAllocSize
 	if size > maxAllocSize || size <= 0 {
@@ -16,3 +16,24 @@ func checkDecimalPrecision(value *big.Int, prec int) (int, bool) {
 
 	return numDigits, true
 }
+
+// bytesDecimalBytes returns the big-endian two's complement encoding of the unscaled
+// decimal value i.
+func bytesDecimalBytes(i *big.Int) []byte {
+	var b []byte
+	switch i.Sign() {
+	case 0:
+		b = []byte{0}
+
+	case 1:
+		b = i.Bytes()
+		if b[0]&0x80 > 0 {
+			b = append([]byte{0}, b...)
+		}
+
+	case -1:
+		length := uint(i.BitLen()/8+1) * 8
+		b = (&big.Int{}).Add(i, (&big.Int{}).Lsh(one, length)).Bytes()
+	}
+	return b
+}
@@ -1,6 +1,9 @@
 package avro
 
 import (
+	"encoding/binary"
+	"errors"
+	"fmt"
 	"io"
 )
 
@@ -42,8 +45,189 @@ func (d *Decoder) Decode(v any) error {
 	return d.r.Error
 }
 
+// ReadSchemaVersion reads the schema version written ahead of the next value by an
+// Encoder using WithSchemaVersion, so the reader can branch on it before decoding the
+// value with Decode. It returns io.EOF when there are no more values.
+func (d *Decoder) ReadSchemaVersion() (int, error) {
+	if d.r.head == d.r.tail && d.r.reader != nil {
+		if !d.r.loadMore() {
+			if errors.Is(d.r.Error, io.EOF) {
+				return 0, io.EOF
+			}
+			return 0, fmt.Errorf("avro: reading schema version: %w", d.r.Error)
+		}
+	}
+
+	version := d.r.ReadInt()
+	if d.r.Error != nil {
+		return 0, fmt.Errorf("avro: reading schema version: %w", d.r.Error)
+	}
+	return int(version), nil
+}
+
+// CountingDecoder reads and decodes Avro values written by a CountingEncoder,
+// verifying the trailing count once finished.
+type CountingDecoder struct {
+	d        *Decoder
+	count    int64
+	trailer  int64
+	finished bool
+}
+
+// NewCountingDecoder returns a new counting decoder that reads from r using schema.
+func NewCountingDecoder(schema Schema, r io.Reader) *CountingDecoder {
+	return &CountingDecoder{d: NewDecoderForSchema(schema, r)}
+}
+
+// Decode reads the next Avro encoded value from its input and stores it in the value pointed to by v.
+// It returns io.EOF once the trailing count is reached.
+func (d *CountingDecoder) Decode(v any) error {
+	if d.finished {
+		return io.EOF
+	}
+	more, err := d.readMarker()
+	if err != nil {
+		return err
+	}
+	if !more {
+		return io.EOF
+	}
+
+	r := d.d.r
+	r.ReadVal(d.d.s, v)
+	if r.Error != nil {
+		if errors.Is(r.Error, io.EOF) {
+			return fmt.Errorf("avro: reading value: %w", io.ErrUnexpectedEOF)
+		}
+		return r.Error
+	}
+	d.count++
+	return nil
+}
+
+// readMarker reads the marker ahead of the next value, returning false once
+// the end marker and the trailing count have been read.
+func (d *CountingDecoder) readMarker() (bool, error) {
+	r := d.d.r
+	if r.head == r.tail && r.reader != nil && !r.loadMore() {
+		if errors.Is(r.Error, io.EOF) {
+			return false, fmt.Errorf("avro: missing trailing count: %w", io.ErrUnexpectedEOF)
+		}
+		return false, fmt.Errorf("avro: reading marker: %w", r.Error)
+	}
+
+	switch marker := r.ReadLong(); {
+	case r.Error != nil:
+		return false, fmt.Errorf("avro: reading marker: %w", r.Error)
+	case marker == 1:
+		return true, nil
+	case marker != 0:
+		return false, fmt.Errorf("avro: invalid marker %d", marker)
+	}
+
+	d.trailer = r.ReadLong()
+	if r.Error != nil {
+		return false, fmt.Errorf("avro: reading trailing count: %w", r.Error)
+	}
+	d.finished = true
+	return false, nil
+}
+
+// Count returns the number of values read.
+func (d *CountingDecoder) Count() int64 {
+	return d.count
+}
+
+// Finish reads the trailing count from the stream, if Decode has not reached it yet,
+// and verifies that it matches the number of values read.
+func (d *CountingDecoder) Finish() error {
+	if !d.finished {
+		more, err := d.readMarker()
+		if err != nil {
+			return err
+		}
+		if more {
+			return errors.New("avro: values left before the trailing count")
+		}
+	}
+	if d.trailer != d.count {
+		return fmt.Errorf("avro: trailing count %d does not match %d values read", d.trailer, d.count)
+	}
+	return nil
+}
+
+// ConfluentDecoder reads and decodes Avro values framed in the Confluent wire format
+// from an input stream. Each value is prefixed with a zero magic byte and the 4 byte
+// big-endian id of its schema in the schema registry.
+// See https://docs.confluent.io/platform/current/schema-registry/fundamentals/serdes-develop/index.html#wire-format.
+type ConfluentDecoder struct {
+	resolve func(id uint32) (Schema, error)
+	r       *Reader
+	id      uint32
+}
+
+// NewConfluentDecoder returns a new decoder that reads from r, using resolver to get the
+// schema of each value from its schema id. The resolver is called for every value, so it
+// should cache the schemas it returns.
+func NewConfluentDecoder(resolver func(id uint32) (Schema, error), r io.Reader) *ConfluentDecoder {
+	return &ConfluentDecoder{
+		resolve: resolver,
+		r:       NewReader(r, 512),
+	}
+}
+
+// Decode reads the next Confluent wire formatted value from its input and stores it in
+// the value pointed to by v. It returns io.EOF when there are no more values.
+func (d *ConfluentDecoder) Decode(v any) error {
+	if d.r.head == d.r.tail && d.r.reader != nil {
+		if !d.r.loadMore() {
+			if d.r.Error == nil || errors.Is(d.r.Error, io.EOF) {
+				return io.EOF
+			}
+			return d.r.Error
+		}
+	}
+
+	var header [5]byte
+	d.r.Read(header[:])
+	if d.r.Error != nil {
+		return d.r.Error
+	}
+	if header[0] != 0 {
+		d.r.Error = fmt.Errorf("avro: invalid magic byte %x", header[0])
+		return d.r.Error
+	}
+	d.id = binary.BigEndian.Uint32(header[1:])
+
+	schema, err := d.resolve(d.id)
+	if err != nil {
+		d.r.Error = fmt.Errorf("avro: resolving schema %d: %w", d.id, err)
+		return d.r.Error
+	}
+
+	d.r.ReadVal(schema, v)
+
+	//nolint:errorlint // Only direct EOF errors should be discarded.
+	if d.r.Error == io.EOF {
+		return nil
+	}
+	return d.r.Error
+}
+
+// SchemaID returns the schema id of the last value read.
+func (d *ConfluentDecoder) SchemaID() uint32 {
+	return d.id
+}
+
 // Unmarshal parses the Avro encoded data and stores the result in the value pointed to by v.
 // If v is nil or not a pointer, Unmarshal returns an error.
 func Unmarshal(schema Schema, data []byte, v any) error {
 	return DefaultConfig.Unmarshal(schema, data, v)
 }
+
+// UnmarshalN is like Unmarshal, but also returns the number of bytes of data consumed,
+// allowing concatenated values to be decoded one after the other. If data ends before
+// the value is complete, UnmarshalN returns io.ErrUnexpectedEOF.
+func UnmarshalN(schema Schema, data []byte, v any) (int, error) {
+	return mustFrozenConfig(DefaultConfig).UnmarshalN(schema, data, v)
+}
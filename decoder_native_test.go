@@ -507,6 +507,19 @@ func TestDecoder_Time_Date(t *testing.T) {
 	assert.Equal(t, time.Date(2920, 1, 2, 0, 0, 0, 0, time.UTC), got)
 }
 
+func TestDecoder_Time_DateBeforeEpoch(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{"type":"int","logicalType":"date"}`)
+
+	var got time.Time
+	err := avro.Unmarshal(schema, []byte{0x01}, &got)
+
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), got)
+	assert.Equal(t, time.UTC, got.Location())
+}
+
 func TestDecoder_Time_TimestampMillis(t *testing.T) {
 	defer ConfigTeardown()
 
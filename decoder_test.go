@@ -2,10 +2,14 @@ package avro_test
 
 import (
 	"bytes"
+	"errors"
+	"io"
 	"testing"
+	"testing/iotest"
 
 	"github.com/hamba/avro/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewDecoder_SchemaError(t *testing.T) {
@@ -68,6 +72,40 @@ func TestDecoder_DecodeNilPtr(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestDecoder_ReadSchemaVersion(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse("string")
+	buf := bytes.NewBuffer([]byte{})
+	enc := avro.NewEncoderForSchema(schema, buf, avro.WithSchemaVersion(2))
+	require.NoError(t, enc.Encode("foo"))
+	dec := avro.NewDecoderForSchema(schema, buf)
+
+	version, err := dec.ReadSchemaVersion()
+	require.NoError(t, err)
+	assert.Equal(t, 2, version)
+
+	var got string
+	err = dec.Decode(&got)
+	require.NoError(t, err)
+	assert.Equal(t, "foo", got)
+
+	_, err = dec.ReadSchemaVersion()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestDecoder_ReadSchemaVersionReaderError(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse("string")
+	dec := avro.NewDecoderForSchema(schema, iotest.ErrReader(errors.New("test")))
+
+	_, err := dec.ReadSchemaVersion()
+
+	assert.EqualError(t, err, "avro: reading schema version: test")
+	assert.NotErrorIs(t, err, io.EOF)
+}
+
 func TestUnmarshal(t *testing.T) {
 	defer ConfigTeardown()
 
@@ -99,3 +137,258 @@ func TestUnmarshal_NilPtr(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+func TestUnmarshal_ReadError(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{"type": "array", "items": "boolean"}`)
+
+	var got []bool
+	err := avro.Unmarshal(schema, []byte{0x04, 0x01, 0x02, 0x00}, &got)
+
+	var readErr *avro.ReadError
+	require.ErrorAs(t, err, &readErr)
+	assert.Equal(t, "ReadBool", readErr.Op)
+	assert.Equal(t, int64(3), readErr.Offset)
+	assert.ErrorContains(t, err, "avro: ReadBool: invalid bool")
+}
+
+func TestUnmarshal_SetDefaultConfig(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse("string")
+	data, err := avro.Marshal(schema, "too long")
+	require.NoError(t, err)
+
+	avro.SetDefaultConfig(avro.Config{MaxByteSliceSize: 4})
+
+	var got string
+	err = avro.Unmarshal(schema, data, &got)
+
+	assert.ErrorContains(t, err, "size is greater than `Config.MaxByteSliceSize`")
+}
+
+func TestUnmarshalN(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse("string")
+
+	var s string
+	n, err := avro.UnmarshalN(schema, []byte{0x06, 0x66, 0x6f, 0x6f, 0x02}, &s)
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, n)
+	assert.Equal(t, "foo", s)
+}
+
+func TestUnmarshalN_TruncatedValue(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{"type":"record","name":"test","fields":[{"name":"a","type":"long"},{"name":"b","type":"string"}]}`)
+	data := []byte{0x36, 0x06, 0x66, 0x6f, 0x6f, 0x38, 0x06, 0x62}
+
+	var got struct {
+		A int64  `avro:"a"`
+		B string `avro:"b"`
+	}
+	n, err := avro.UnmarshalN(schema, data, &got)
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+
+	_, err = avro.UnmarshalN(schema, data[n:], &got)
+
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestCountingDecoder(t *testing.T) {
+	defer ConfigTeardown()
+
+	data := []byte{0x02, 0x36, 0x02, 0x1a, 0x00, 0x04}
+	schema := avro.MustParse("int")
+	dec := avro.NewCountingDecoder(schema, bytes.NewReader(data))
+
+	var got []int
+	for {
+		var i int
+		err := dec.Decode(&i)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		got = append(got, i)
+	}
+	err := dec.Finish()
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{27, 13}, got)
+	assert.Equal(t, int64(2), dec.Count())
+}
+
+func TestCountingDecoder_CountMismatch(t *testing.T) {
+	defer ConfigTeardown()
+
+	data := []byte{0x02, 0x36, 0x00, 0x04}
+	schema := avro.MustParse("int")
+	dec := avro.NewCountingDecoder(schema, bytes.NewReader(data))
+
+	var i int
+	err := dec.Decode(&i)
+	require.NoError(t, err)
+	err = dec.Decode(&i)
+	require.ErrorIs(t, err, io.EOF)
+	err = dec.Finish()
+
+	assert.EqualError(t, err, "avro: trailing count 2 does not match 1 values read")
+}
+
+func TestCountingDecoder_FinishWithValuesLeft(t *testing.T) {
+	defer ConfigTeardown()
+
+	data := []byte{0x02, 0x36, 0x02, 0x1a, 0x00, 0x04}
+	schema := avro.MustParse("int")
+	dec := avro.NewCountingDecoder(schema, bytes.NewReader(data))
+
+	var i int
+	err := dec.Decode(&i)
+	require.NoError(t, err)
+	err = dec.Finish()
+
+	assert.EqualError(t, err, "avro: values left before the trailing count")
+}
+
+func TestCountingDecoder_MissingCount(t *testing.T) {
+	defer ConfigTeardown()
+
+	data := []byte{0x02, 0x36}
+	schema := avro.MustParse("int")
+	dec := avro.NewCountingDecoder(schema, bytes.NewReader(data))
+
+	var i int
+	err := dec.Decode(&i)
+	require.NoError(t, err)
+	err = dec.Decode(&i)
+
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Error(t, dec.Finish())
+}
+
+func TestCountingDecoder_TruncatedValue(t *testing.T) {
+	defer ConfigTeardown()
+
+	data := []byte{0x02, 0x06, 0x66}
+	schema := avro.MustParse("string")
+	dec := avro.NewCountingDecoder(schema, bytes.NewReader(data))
+
+	var s string
+	err := dec.Decode(&s)
+
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestCountingDecoder_InvalidMarker(t *testing.T) {
+	defer ConfigTeardown()
+
+	data := []byte{0x04, 0x36}
+	schema := avro.MustParse("int")
+	dec := avro.NewCountingDecoder(schema, bytes.NewReader(data))
+
+	var i int
+	err := dec.Decode(&i)
+
+	assert.EqualError(t, err, "avro: invalid marker 2")
+}
+
+func TestConfluentDecoder(t *testing.T) {
+	defer ConfigTeardown()
+
+	data := []byte{
+		0x0, 0x0, 0x0, 0x0, 0x2a, 0x36,
+		0x0, 0x0, 0x0, 0x1, 0x0, 0x06, 0x66, 0x6f, 0x6f,
+	}
+	schemas := map[uint32]avro.Schema{
+		42:  avro.MustParse("int"),
+		256: avro.MustParse("string"),
+	}
+	resolver := func(id uint32) (avro.Schema, error) {
+		return schemas[id], nil
+	}
+	dec := avro.NewConfluentDecoder(resolver, bytes.NewReader(data))
+
+	var i int
+	err := dec.Decode(&i)
+	require.NoError(t, err)
+	assert.Equal(t, 27, i)
+	assert.Equal(t, uint32(42), dec.SchemaID())
+
+	var s string
+	err = dec.Decode(&s)
+	require.NoError(t, err)
+	assert.Equal(t, "foo", s)
+	assert.Equal(t, uint32(256), dec.SchemaID())
+
+	err = dec.Decode(&s)
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestConfluentDecoder_BadMagic(t *testing.T) {
+	defer ConfigTeardown()
+
+	data := []byte{0x1, 0x0, 0x0, 0x0, 0x2a, 0x36}
+	resolver := func(uint32) (avro.Schema, error) {
+		return avro.MustParse("int"), nil
+	}
+	dec := avro.NewConfluentDecoder(resolver, bytes.NewReader(data))
+
+	var i int
+	err := dec.Decode(&i)
+
+	assert.EqualError(t, err, "avro: invalid magic byte 1")
+}
+
+func TestConfluentDecoder_ResolverError(t *testing.T) {
+	defer ConfigTeardown()
+
+	data := []byte{0x0, 0x0, 0x0, 0x0, 0x2a, 0x36}
+	errNotFound := errors.New("schema not found")
+	resolver := func(uint32) (avro.Schema, error) {
+		return nil, errNotFound
+	}
+	dec := avro.NewConfluentDecoder(resolver, bytes.NewReader(data))
+
+	var i int
+	err := dec.Decode(&i)
+
+	assert.ErrorIs(t, err, errNotFound)
+	assert.EqualError(t, err, "avro: resolving schema 42: schema not found")
+}
+
+func TestConfluentDecoder_ShortHeader(t *testing.T) {
+	defer ConfigTeardown()
+
+	data := []byte{0x0, 0x0, 0x0}
+	resolver := func(uint32) (avro.Schema, error) {
+		return avro.MustParse("int"), nil
+	}
+	dec := avro.NewConfluentDecoder(resolver, bytes.NewReader(data))
+
+	var i int
+	err := dec.Decode(&i)
+
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestConfluentDecoder_ReaderError(t *testing.T) {
+	defer ConfigTeardown()
+
+	errRead := errors.New("test")
+	resolver := func(uint32) (avro.Schema, error) {
+		return avro.MustParse("int"), nil
+	}
+	dec := avro.NewConfluentDecoder(resolver, iotest.ErrReader(errRead))
+
+	var i int
+	err := dec.Decode(&i)
+
+	assert.ErrorIs(t, err, errRead)
+	assert.NotErrorIs(t, err, io.EOF)
+}
@@ -1,37 +1,122 @@
 package avro
 
 import (
+	"fmt"
 	"io"
+	"math"
 )
 
+// EncoderFunc is a function used to customize the Encoder.
+type EncoderFunc func(e *Encoder)
+
+// WithSchemaVersion prefixes each value written by the encoder with the schema version n,
+// encoded as an Avro int, so readers can branch on it using Decoder.ReadSchemaVersion.
+// The version must fit in an Avro int, otherwise NewEncoder and Encode fail.
+func WithSchemaVersion(n int) EncoderFunc {
+	return func(e *Encoder) {
+		if n < math.MinInt32 || n > math.MaxInt32 {
+			e.err = fmt.Errorf("avro: schema version %d does not fit in an int", n)
+			return
+		}
+		e.version = int32(n)
+		e.versioned = true
+	}
+}
+
 // Encoder writes Avro values to an output stream.
 type Encoder struct {
 	s Schema
 	w *Writer
+
+	version   int32
+	versioned bool
+	err       error
 }
 
 // NewEncoder returns a new encoder that writes to w using schema s.
-func NewEncoder(s string, w io.Writer) (*Encoder, error) {
+func NewEncoder(s string, w io.Writer, opts ...EncoderFunc) (*Encoder, error) {
 	sch, err := Parse(s)
 	if err != nil {
 		return nil, err
 	}
-	return NewEncoderForSchema(sch, w), nil
+	enc := NewEncoderForSchema(sch, w, opts...)
+	if enc.err != nil {
+		return nil, enc.err
+	}
+	return enc, nil
 }
 
 // NewEncoderForSchema returns a new encoder that writes to w using schema.
-func NewEncoderForSchema(schema Schema, w io.Writer) *Encoder {
-	return DefaultConfig.NewEncoder(schema, w)
+func NewEncoderForSchema(schema Schema, w io.Writer, opts ...EncoderFunc) *Encoder {
+	return NewEncoderWithAPI(schema, w, DefaultConfig, opts...)
+}
+
+// NewEncoderWithAPI returns a new encoder that writes to w using schema and an API.
+func NewEncoderWithAPI(schema Schema, w io.Writer, api API, opts ...EncoderFunc) *Encoder {
+	enc := api.NewEncoder(schema, w)
+	for _, opt := range opts {
+		opt(enc)
+	}
+	return enc
 }
 
 // Encode writes the Avro encoding of v to the stream.
 func (e *Encoder) Encode(v any) error {
+	if e.err != nil {
+		return e.err
+	}
+	if e.versioned {
+		e.w.WriteInt(e.version)
+	}
 	e.w.WriteVal(e.s, v)
 	_ = e.w.Flush()
 	return e.w.Error
 }
 
+// CountingEncoder writes Avro values to an output stream, followed by the
+// number of values written once finished. Each value is preceded by a long 1
+// marker, and the count by a long 0 marker, so readers can find the end of the
+// values without knowing how many were written.
+type CountingEncoder struct {
+	e     *Encoder
+	count int64
+}
+
+// NewCountingEncoder returns a new counting encoder that writes to w using schema.
+func NewCountingEncoder(schema Schema, w io.Writer) *CountingEncoder {
+	return &CountingEncoder{e: NewEncoderForSchema(schema, w)}
+}
+
+// Encode writes the Avro encoding of v to the stream.
+func (e *CountingEncoder) Encode(v any) error {
+	e.e.w.WriteLong(1)
+	if err := e.e.Encode(v); err != nil {
+		return err
+	}
+	e.count++
+	return nil
+}
+
+// Count returns the number of values written.
+func (e *CountingEncoder) Count() int64 {
+	return e.count
+}
+
+// Finish writes the end marker, followed by the number of values written as a trailing long.
+func (e *CountingEncoder) Finish() error {
+	e.e.w.WriteLong(0)
+	e.e.w.WriteLong(e.count)
+	_ = e.e.w.Flush()
+	return e.e.w.Error
+}
+
 // Marshal returns the Avro encoding of v.
 func Marshal(schema Schema, v any) ([]byte, error) {
 	return DefaultConfig.Marshal(schema, v)
 }
+
+// MarshalTo appends the Avro encoding of v to buf and returns the extended buffer, like append.
+// Reusing the buffer (e.g. with buf[:0]) avoids allocating for each value.
+func MarshalTo(schema Schema, v any, buf []byte) ([]byte, error) {
+	return mustFrozenConfig(DefaultConfig).MarshalTo(schema, v, buf)
+}
@@ -150,6 +150,54 @@ func TestEncoder_MapWithMoreThanBlockLengthKeys(t *testing.T) {
 	})
 }
 
+func TestEncoder_MapSortKeys(t *testing.T) {
+	defer ConfigTeardown()
+
+	api := avro.Config{BlockLength: 2, SortMapKeys: true}.Freeze()
+	schema := avro.MustParse(`{"type":"map", "values": "int"}`)
+	m := map[string]int{"foo": 1, "bar": 2, "baz": 3, "qux": 4, "abc": 5}
+
+	want := []byte{
+		0x03, 0x14, 0x06, 0x61, 0x62, 0x63, 0x0a, 0x06, 0x62, 0x61, 0x72, 0x04, // abc, bar
+		0x03, 0x14, 0x06, 0x62, 0x61, 0x7a, 0x06, 0x06, 0x66, 0x6f, 0x6f, 0x02, // baz, foo
+		0x01, 0x0a, 0x06, 0x71, 0x75, 0x78, 0x08, // qux
+		0x00,
+	}
+	for range 10 {
+		got, err := api.Marshal(schema, m)
+
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestEncoder_MapMarshallerSortKeys(t *testing.T) {
+	defer ConfigTeardown()
+
+	api := avro.Config{SortMapKeys: true}.Freeze()
+	schema := avro.MustParse(`{"type":"map", "values": "string"}`)
+	m := map[textMarshallerInt]string{3: "c", 1: "a", 2: "b"}
+
+	want := []byte{0x05, 0x18, 0x02, 0x31, 0x02, 0x61, 0x02, 0x32, 0x02, 0x62, 0x02, 0x33, 0x02, 0x63, 0x00}
+	for range 10 {
+		got, err := api.Marshal(schema, m)
+
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestEncoder_MapMarshallerSortKeysError(t *testing.T) {
+	defer ConfigTeardown()
+
+	api := avro.Config{SortMapKeys: true}.Freeze()
+	schema := avro.MustParse(`{"type":"map", "values": "int"}`)
+
+	_, err := api.Marshal(schema, map[textMarshallerError]int{1: 1})
+
+	require.Error(t, err)
+}
+
 type textMarshallerInt int
 
 func (t textMarshallerInt) MarshalText() (text []byte, err error) {
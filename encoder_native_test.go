@@ -431,6 +431,55 @@ func TestEncoder_Time_Date(t *testing.T) {
 	assert.Equal(t, []byte{0xCA, 0xAD, 0x2A}, buf.Bytes())
 }
 
+func TestEncoder_Time_DateNormalizesToUTC(t *testing.T) {
+	defer ConfigTeardown()
+
+	tests := []struct {
+		name string
+		time time.Time
+		want int
+	}{
+		{
+			name: "midnight",
+			time: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: 18262,
+		},
+		{
+			name: "end of day",
+			time: time.Date(2020, 1, 1, 23, 59, 59, 999999999, time.UTC),
+			want: 18262,
+		},
+		{
+			name: "offset zone",
+			time: time.Date(2020, 1, 2, 1, 0, 0, 0, time.FixedZone("UTC+5", 5*60*60)),
+			want: 18262,
+		},
+		{
+			name: "before epoch",
+			time: time.Date(1969, 12, 31, 12, 0, 0, 0, time.UTC),
+			want: -1,
+		},
+		{
+			name: "before epoch midnight",
+			time: time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC),
+			want: -1,
+		},
+	}
+
+	schema := avro.MustParse(`{"type":"int","logicalType":"date"}`)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := avro.Marshal(schema, test.time)
+			require.NoError(t, err)
+
+			var got int
+			err = avro.Unmarshal(avro.MustParse("int"), data, &got)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
 func TestEncoder_Time_TimestampMillis(t *testing.T) {
 	defer ConfigTeardown()
 
@@ -640,6 +689,18 @@ func TestEncoder_Duration_TimeMicros(t *testing.T) {
 	assert.Equal(t, []byte{0x86, 0xEA, 0xC8, 0xE9, 0x97, 0x07}, buf.Bytes())
 }
 
+func TestEncoder_Duration_TimeTruncates(t *testing.T) {
+	defer ConfigTeardown()
+
+	millis, err := avro.Marshal(avro.MustParse(`{"type":"int","logicalType":"time-millis"}`), 1999*time.Microsecond)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x02}, millis)
+
+	micros, err := avro.Marshal(avro.MustParse(`{"type":"long","logicalType":"time-micros"}`), 1999*time.Nanosecond)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x02}, micros)
+}
+
 func TestEncoder_Duration_InvalidLogicalType(t *testing.T) {
 	defer ConfigTeardown()
 
@@ -2,6 +2,8 @@ package avro_test
 
 import (
 	"bytes"
+	"math"
+	"sync"
 	"testing"
 
 	"github.com/hamba/avro/v2"
@@ -30,6 +32,49 @@ func TestEncoder_EncodeUnsupportedType(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestEncoder_WithSchemaVersion(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse("string")
+	buf := bytes.NewBuffer([]byte{})
+	enc := avro.NewEncoderForSchema(schema, buf, avro.WithSchemaVersion(2))
+
+	err := enc.Encode("foo")
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x04, 0x06, 0x66, 0x6f, 0x6f}, buf.Bytes())
+}
+
+func TestEncoder_WithSchemaVersionOutOfRange(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse("string")
+	buf := bytes.NewBuffer([]byte{})
+	enc := avro.NewEncoderForSchema(schema, buf, avro.WithSchemaVersion(math.MaxInt32+1))
+
+	err := enc.Encode("foo")
+
+	assert.EqualError(t, err, "avro: schema version 2147483648 does not fit in an int")
+	assert.Empty(t, buf.Bytes())
+
+	_, err = avro.NewEncoder(`"string"`, buf, avro.WithSchemaVersion(math.MinInt32-1))
+	assert.Error(t, err)
+}
+
+func TestNewEncoderWithAPI(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{"type":"array","items":"int"}`)
+	api := avro.Config{BlockLength: 1, DisableBlockSizeHeader: true}.Freeze()
+	buf := bytes.NewBuffer([]byte{})
+	enc := avro.NewEncoderWithAPI(schema, buf, api, avro.WithSchemaVersion(2))
+
+	err := enc.Encode([]int{1, 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x04, 0x02, 0x02, 0x02, 0x04, 0x00}, buf.Bytes())
+}
+
 func TestMarshal(t *testing.T) {
 	defer ConfigTeardown()
 
@@ -50,3 +95,100 @@ func TestMarshal_Error(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+func TestMarshalTo(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse("string")
+	buf := make([]byte, 1, 16)
+	buf[0] = 0xff
+
+	got, err := avro.MarshalTo(schema, "foo", buf)
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xff, 0x06, 0x66, 0x6f, 0x6f}, got)
+	assert.Same(t, &buf[0], &got[0])
+}
+
+func TestMarshalTo_Grows(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse("string")
+
+	got, err := avro.MarshalTo(schema, "foo", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x06, 0x66, 0x6f, 0x6f}, got)
+}
+
+func TestMarshalTo_Error(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse("int")
+	buf := []byte{0xff}
+
+	got, err := avro.MarshalTo(schema, "foo", buf)
+
+	assert.Error(t, err)
+	assert.Equal(t, []byte{0xff}, got)
+}
+
+func TestMarshal_SetDefaultConfig(t *testing.T) {
+	defer ConfigTeardown()
+
+	avro.SetDefaultConfig(avro.Config{BlockLength: 1, DisableBlockSizeHeader: true})
+	schema := avro.MustParse(`{"type": "array", "items": "int"}`)
+
+	b, err := avro.Marshal(schema, []int{1, 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x02, 0x02, 0x02, 0x04, 0x00}, b)
+}
+
+func TestMarshal_SetDefaultConfigConcurrently(t *testing.T) {
+	defer ConfigTeardown()
+
+	// Restore the default API, which the teardown of other tests replaces.
+	avro.SetDefaultConfig(avro.Config{})
+	schema := avro.MustParse(`{"type": "array", "items": "int"}`)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				_, err := avro.Marshal(schema, []int{1, 2})
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	for range 100 {
+		avro.SetDefaultConfig(avro.Config{BlockLength: 1})
+	}
+	wg.Wait()
+
+	avro.SetDefaultConfig(avro.Config{BlockLength: 1, DisableBlockSizeHeader: true})
+	b, err := avro.Marshal(schema, []int{1, 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x02, 0x02, 0x02, 0x04, 0x00}, b)
+}
+
+func TestCountingEncoder(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse("int")
+	buf := bytes.NewBuffer([]byte{})
+	enc := avro.NewCountingEncoder(schema, buf)
+
+	for _, v := range []int{27, 13} {
+		err := enc.Encode(v)
+		require.NoError(t, err)
+	}
+	err := enc.Finish()
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), enc.Count())
+	assert.Equal(t, []byte{0x02, 0x36, 0x02, 0x1a, 0x00, 0x04}, buf.Bytes())
+}
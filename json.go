@@ -0,0 +1,472 @@
+package avro
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+)
+
+// MarshalJSON encodes the JSON document jsonData as Avro data of the schema. Values are
+// mapped by the schema as in the Avro JSON encoding: records and maps are objects, arrays
+// are arrays, enums are their symbol, and union values other than null are an object with
+// the type name of their branch as single key (e.g. `{"string": "a"}`, or `{"long": 1}`
+// for a timestamp-millis). Bytes and fixed values are base64 encoded strings. Record
+// fields missing from the document are written as their default.
+func MarshalJSON(schema Schema, jsonData []byte) ([]byte, error) {
+	return MarshalJSONWithAPI(DefaultConfig, schema, jsonData)
+}
+
+// MarshalJSONWithAPI is like MarshalJSON, but encodes the data, including the defaults
+// of missing record fields, with api.
+func MarshalJSONWithAPI(api API, schema Schema, jsonData []byte) ([]byte, error) {
+	cfg, ok := frozenConfigOf(api)
+	if !ok {
+		return nil, errors.New("avro: api must be created with Config.Freeze")
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(jsonData))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("avro: invalid JSON: %w", err)
+	}
+	if dec.More() {
+		return nil, errors.New("avro: invalid JSON: data after the top-level value")
+	}
+
+	e := &jsonEncoder{cfg: cfg, w: NewWriter(nil, 512, WithWriterConfig(cfg))}
+	if err := e.encode(schema, v, ""); err != nil {
+		return nil, err
+	}
+	return e.w.Buffer(), nil
+}
+
+type jsonEncoder struct {
+	cfg *frozenConfig
+	w   *Writer
+}
+
+func (e *jsonEncoder) encode(schema Schema, v any, path string) error {
+	invalid := func(format string, args ...any) error {
+		if path == "" {
+			return fmt.Errorf("avro: "+format, args...)
+		}
+		return fmt.Errorf("avro: %s: "+format, append([]any{path}, args...)...)
+	}
+	mismatch := func() error {
+		return invalid("cannot encode JSON %s as %s", jsonTypeName(v), schema.Type())
+	}
+
+	switch schema.Type() {
+	case Null:
+		if v != nil {
+			return mismatch()
+		}
+
+	case Boolean:
+		b, ok := v.(bool)
+		if !ok {
+			return mismatch()
+		}
+		e.w.WriteBool(b)
+
+	case Int, Long:
+		n, ok := v.(json.Number)
+		if !ok {
+			return mismatch()
+		}
+		bitSize := 64
+		if schema.Type() == Int {
+			bitSize = 32
+		}
+		i, err := strconv.ParseInt(n.String(), 10, bitSize)
+		if err != nil {
+			return invalid("invalid %s %s", schema.Type(), n)
+		}
+		e.w.WriteLong(i)
+
+	case Float, Double:
+		n, ok := v.(json.Number)
+		if !ok {
+			return mismatch()
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return invalid("invalid %s %s", schema.Type(), n)
+		}
+		if schema.Type() == Float {
+			e.w.WriteFloat(float32(f))
+			break
+		}
+		e.w.WriteDouble(f)
+
+	case String:
+		s, ok := v.(string)
+		if !ok {
+			return mismatch()
+		}
+		e.w.WriteString(s)
+
+	case Bytes, Fixed:
+		s, ok := v.(string)
+		if !ok {
+			return mismatch()
+		}
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return invalid("invalid base64 %s: %v", schema.Type(), err)
+		}
+		if schema.Type() == Bytes {
+			e.w.WriteBytes(b)
+			break
+		}
+		if size := schema.(*FixedSchema).Size(); len(b) != size {
+			return invalid("fixed size %d does not match %d bytes", size, len(b))
+		}
+		_, _ = e.w.Write(b)
+
+	case Enum:
+		s, ok := v.(string)
+		if !ok {
+			return mismatch()
+		}
+		idx := slices.Index(schema.(*EnumSchema).Symbols(), s)
+		if idx == -1 {
+			return invalid("unknown enum symbol %s", s)
+		}
+		e.w.WriteInt(int32(idx))
+
+	case Record:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return mismatch()
+		}
+		fields := schema.(*RecordSchema).Fields()
+		for name := range obj {
+			if !slices.ContainsFunc(fields, func(f *Field) bool { return f.Name() == name }) {
+				return invalid("unknown field %s", name)
+			}
+		}
+		for _, f := range fields {
+			fieldPath := joinValidationPath(path, f.Name())
+			fv, ok := obj[f.Name()]
+			if !ok {
+				if !f.HasDefault() {
+					return fmt.Errorf("avro: %s: missing field without default", fieldPath)
+				}
+				def, err := encodeFieldDefault(e.cfg, f)
+				if err != nil {
+					return fmt.Errorf("avro: %s: encode default: %w", fieldPath, err)
+				}
+				_, _ = e.w.Write(def)
+				continue
+			}
+			if err := e.encode(f.Type(), fv, fieldPath); err != nil {
+				return err
+			}
+		}
+
+	case Ref:
+		return e.encode(schema.(*RefSchema).Schema(), v, path)
+
+	case Array:
+		arr, ok := v.([]any)
+		if !ok {
+			return mismatch()
+		}
+		items := schema.(*ArraySchema).Items()
+		e.w.WriteArrayStart(len(arr))
+		for i, item := range arr {
+			if err := e.encode(items, item, path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+		e.w.WriteArrayEnd()
+
+	case Map:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return mismatch()
+		}
+		values := schema.(*MapSchema).Values()
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		// Keys are sorted so that the same document always encodes to the same data.
+		slices.Sort(keys)
+		e.w.WriteMapStart(len(keys))
+		for _, k := range keys {
+			e.w.WriteString(k)
+			if err := e.encode(values, obj[k], path+"["+k+"]"); err != nil {
+				return err
+			}
+		}
+		e.w.WriteMapEnd()
+
+	case Union:
+		types := schema.(*UnionSchema).Types()
+		if v == nil {
+			_, idx := types.Get(string(Null))
+			if idx == -1 {
+				return invalid("null is not in union")
+			}
+			e.w.WriteLong(int64(idx))
+			break
+		}
+		obj, ok := v.(map[string]any)
+		if !ok || len(obj) != 1 {
+			return invalid("union value must be null or an object with a single branch name")
+		}
+		for name, bv := range obj {
+			idx := slices.IndexFunc(types, func(t Schema) bool { return jsonBranchName(t) == name })
+			if idx == -1 {
+				return invalid("unknown union branch %s", name)
+			}
+			e.w.WriteLong(int64(idx))
+			return e.encode(types[idx], bv, path)
+		}
+
+	default:
+		return invalid("schema type %s is unsupported", schema.Type())
+	}
+	return nil
+}
+
+// jsonBranchName returns the name keying values of the union branch in the Avro JSON
+// encoding: the full name of named types, and the type name of others, without their
+// logical type.
+func jsonBranchName(schema Schema) string {
+	if schema.Type() == Ref {
+		schema = schema.(*RefSchema).Schema()
+	}
+	if n, ok := schema.(NamedSchema); ok {
+		return n.FullName()
+	}
+	return string(schema.Type())
+}
+
+// jsonTypeName returns the JSON type of a decoded JSON value, for error messages.
+func jsonTypeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// UnmarshalToJSON decodes the Avro data of the schema into a JSON document, mapping
+// values as MarshalJSON does. Record fields are written in schema order, and the data
+// must hold exactly one value of the schema.
+func UnmarshalToJSON(schema Schema, data []byte) ([]byte, error) {
+	return UnmarshalToJSONWithAPI(DefaultConfig, schema, data)
+}
+
+// UnmarshalToJSONWithAPI is like UnmarshalToJSON, but decodes the data with api, e.g.
+// for its size limits.
+func UnmarshalToJSONWithAPI(api API, schema Schema, data []byte) ([]byte, error) {
+	if _, ok := frozenConfigOf(api); !ok {
+		return nil, errors.New("avro: api must be created with Config.Freeze")
+	}
+
+	d := &jsonDecoder{r: NewReader(nil, 0, WithReaderConfig(api)).Reset(data)}
+	if err := d.decode(schema, ""); err != nil {
+		return nil, err
+	}
+	if n := d.r.BytesRead(); n < int64(len(data)) {
+		return nil, fmt.Errorf("avro: %d trailing bytes", int64(len(data))-n)
+	}
+	return d.buf, nil
+}
+
+type jsonDecoder struct {
+	r   *Reader
+	buf []byte
+}
+
+func (d *jsonDecoder) decode(schema Schema, path string) error {
+	switch schema.Type() {
+	case Null:
+		d.buf = append(d.buf, "null"...)
+
+	case Boolean:
+		d.buf = strconv.AppendBool(d.buf, d.r.ReadBool())
+
+	case Int:
+		d.buf = strconv.AppendInt(d.buf, int64(d.r.ReadInt()), 10)
+
+	case Long:
+		d.buf = strconv.AppendInt(d.buf, d.r.ReadLong(), 10)
+
+	case Float, Double:
+		var f float64
+		bitSize := 64
+		if schema.Type() == Float {
+			f, bitSize = float64(d.r.ReadFloat()), 32
+		} else {
+			f = d.r.ReadDouble()
+		}
+		if d.r.Error == nil && (math.IsNaN(f) || math.IsInf(f, 0)) {
+			return d.error(path, fmt.Errorf("%v cannot be represented in JSON", f))
+		}
+		d.buf = strconv.AppendFloat(d.buf, f, 'g', -1, bitSize)
+
+	case String:
+		d.appendString(d.r.ReadString())
+
+	case Bytes:
+		d.appendString(base64.StdEncoding.EncodeToString(d.r.ReadBytes()))
+
+	case Fixed:
+		b := make([]byte, schema.(*FixedSchema).Size())
+		d.r.Read(b)
+		d.appendString(base64.StdEncoding.EncodeToString(b))
+
+	case Enum:
+		symbols := schema.(*EnumSchema).Symbols()
+		idx := d.r.ReadInt()
+		if d.r.Error != nil {
+			break
+		}
+		if idx < 0 || int(idx) >= len(symbols) {
+			return d.error(path, fmt.Errorf("enum index %d out of range", idx))
+		}
+		d.appendString(symbols[idx])
+
+	case Record:
+		d.buf = append(d.buf, '{')
+		for i, f := range schema.(*RecordSchema).Fields() {
+			if i > 0 {
+				d.buf = append(d.buf, ',')
+			}
+			d.appendString(f.Name())
+			d.buf = append(d.buf, ':')
+			if err := d.decode(f.Type(), joinValidationPath(path, f.Name())); err != nil {
+				return err
+			}
+		}
+		d.buf = append(d.buf, '}')
+
+	case Ref:
+		return d.decode(schema.(*RefSchema).Schema(), path)
+
+	case Array:
+		items := schema.(*ArraySchema).Items()
+		d.buf = append(d.buf, '[')
+		i := 0
+		err := d.decodeBlocks(path, func() error {
+			if i > 0 {
+				d.buf = append(d.buf, ',')
+			}
+			err := d.decode(items, path+"["+strconv.Itoa(i)+"]")
+			i++
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		d.buf = append(d.buf, ']')
+
+	case Map:
+		values := schema.(*MapSchema).Values()
+		d.buf = append(d.buf, '{')
+		first := true
+		err := d.decodeBlocks(path, func() error {
+			if !first {
+				d.buf = append(d.buf, ',')
+			}
+			first = false
+			key := d.r.ReadString()
+			d.appendString(key)
+			d.buf = append(d.buf, ':')
+			return d.decode(values, path+"["+key+"]")
+		})
+		if err != nil {
+			return err
+		}
+		d.buf = append(d.buf, '}')
+
+	case Union:
+		types := schema.(*UnionSchema).Types()
+		idx := d.r.ReadLong()
+		if d.r.Error != nil {
+			break
+		}
+		if idx < 0 || idx >= int64(len(types)) {
+			return d.error(path, fmt.Errorf("union index %d out of range", idx))
+		}
+		branch := types[idx]
+		if branch.Type() == Null {
+			d.buf = append(d.buf, "null"...)
+			break
+		}
+		d.buf = append(d.buf, '{')
+		d.appendString(jsonBranchName(branch))
+		d.buf = append(d.buf, ':')
+		if err := d.decode(branch, path); err != nil {
+			return err
+		}
+		d.buf = append(d.buf, '}')
+
+	default:
+		return d.error(path, fmt.Errorf("schema type %s is unsupported", schema.Type()))
+	}
+
+	if d.r.Error != nil {
+		return d.error(path, d.r.Error)
+	}
+	return nil
+}
+
+// decodeBlocks decodes the blocks of an array or map, calling fn for each item.
+func (d *jsonDecoder) decodeBlocks(path string, fn func() error) error {
+	for {
+		l, _ := d.r.ReadBlockHeader()
+		if d.r.Error != nil {
+			return d.error(path, d.r.Error)
+		}
+		if l == 0 {
+			return nil
+		}
+		for range l {
+			start := d.r.BytesRead()
+			if err := fn(); err != nil {
+				return err
+			}
+			// Items encoded in zero bytes (e.g. nulls) do not consume the data, so
+			// their count is limited for short data not to produce unbounded output.
+			if d.r.BytesRead() == start {
+				if limit := d.r.cfg.getMaxByteSliceSize(); limit > 0 && l > int64(limit) {
+					return d.error(path, fmt.Errorf("block of %d empty items is greater than `Config.MaxByteSliceSize`", l))
+				}
+			}
+		}
+	}
+}
+
+func (d *jsonDecoder) appendString(s string) {
+	// Marshaling a string cannot fail.
+	b, _ := json.Marshal(s)
+	d.buf = append(d.buf, b...)
+}
+
+func (d *jsonDecoder) error(path string, err error) error {
+	if path == "" {
+		return fmt.Errorf("avro: %w", err)
+	}
+	return fmt.Errorf("avro: %s: %w", path, err)
+}
@@ -0,0 +1,260 @@
+package avro_test
+
+import (
+	"testing"
+
+	"github.com/hamba/avro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "User",
+		"namespace": "org.example",
+		"fields": [
+			{"name": "id", "type": "long"},
+			{"name": "name", "type": "string"},
+			{"name": "score", "type": "double"},
+			{"name": "active", "type": "boolean"},
+			{"name": "avatar", "type": "bytes"},
+			{"name": "hash", "type": {"type": "fixed", "name": "Hash", "size": 2}},
+			{"name": "role", "type": {"type": "enum", "name": "Role", "symbols": ["ADMIN", "USER"]}},
+			{"name": "tags", "type": {"type": "array", "items": "string"}},
+			{"name": "attrs", "type": {"type": "map", "values": "int"}},
+			{"name": "email", "type": ["null", "string"]},
+			{"name": "address", "type": ["null", {
+				"type": "record",
+				"name": "Address",
+				"fields": [{"name": "city", "type": "string"}]
+			}]},
+			{"name": "level", "type": "int", "default": 3}
+		]
+	}`)
+	doc := `{
+		"id": 42,
+		"name": "Jane",
+		"score": 88.5,
+		"active": true,
+		"avatar": "AQID",
+		"hash": "q80=",
+		"role": "USER",
+		"tags": ["a", "b"],
+		"attrs": {"x": 1},
+		"email": {"string": "jane@example.com"},
+		"address": {"org.example.Address": {"city": "Cape Town"}}
+	}`
+
+	got, err := avro.MarshalJSON(schema, []byte(doc))
+	require.NoError(t, err)
+
+	type Address struct {
+		City string `avro:"city"`
+	}
+	type User struct {
+		ID      int64          `avro:"id"`
+		Name    string         `avro:"name"`
+		Score   float64        `avro:"score"`
+		Active  bool           `avro:"active"`
+		Avatar  []byte         `avro:"avatar"`
+		Hash    [2]byte        `avro:"hash"`
+		Role    string         `avro:"role"`
+		Tags    []string       `avro:"tags"`
+		Attrs   map[string]int `avro:"attrs"`
+		Email   *string        `avro:"email"`
+		Address *Address       `avro:"address"`
+		Level   int            `avro:"level"`
+	}
+	var user User
+	err = avro.Unmarshal(schema, got, &user)
+	require.NoError(t, err)
+
+	email := "jane@example.com"
+	want := User{
+		ID:      42,
+		Name:    "Jane",
+		Score:   88.5,
+		Active:  true,
+		Avatar:  []byte{1, 2, 3},
+		Hash:    [2]byte{0xab, 0xcd},
+		Role:    "USER",
+		Tags:    []string{"a", "b"},
+		Attrs:   map[string]int{"x": 1},
+		Email:   &email,
+		Address: &Address{City: "Cape Town"},
+		Level:   3,
+	}
+	assert.Equal(t, want, user)
+
+	back, err := avro.UnmarshalToJSON(schema, got)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"id": 42,
+		"name": "Jane",
+		"score": 88.5,
+		"active": true,
+		"avatar": "AQID",
+		"hash": "q80=",
+		"role": "USER",
+		"tags": ["a", "b"],
+		"attrs": {"x": 1},
+		"email": {"string": "jane@example.com"},
+		"address": {"org.example.Address": {"city": "Cape Town"}},
+		"level": 3
+	}`, string(back))
+}
+
+func TestMarshalJSON_Null(t *testing.T) {
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "User",
+		"fields": [
+			{"name": "email", "type": ["null", "string"]},
+			{"name": "tags", "type": {"type": "array", "items": ["null", "long"]}}
+		]
+	}`)
+
+	got, err := avro.MarshalJSON(schema, []byte(`{"email": null, "tags": [null, {"long": 1}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x00, 0x04, 0x00, 0x02, 0x02, 0x00}, got)
+
+	back, err := avro.UnmarshalToJSON(schema, got)
+	require.NoError(t, err)
+	assert.Equal(t, `{"email":null,"tags":[null,{"long":1}]}`, string(back))
+}
+
+func TestMarshalJSON_Errors(t *testing.T) {
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "User",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "email", "type": ["null", "string"]},
+			{"name": "tags", "type": {"type": "array", "items": "string"}},
+			{"name": "avatar", "type": "bytes"}
+		]
+	}`)
+
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr string
+	}{
+		{
+			name:    "invalid json",
+			doc:     `{"id": `,
+			wantErr: "avro: invalid JSON: unexpected EOF",
+		},
+		{
+			name:    "type mismatch",
+			doc:     `{"id": "1", "email": null, "tags": [], "avatar": ""}`,
+			wantErr: "avro: id: cannot encode JSON string as int",
+		},
+		{
+			name:    "int overflow",
+			doc:     `{"id": 2147483648, "email": null, "tags": [], "avatar": ""}`,
+			wantErr: "avro: id: invalid int 2147483648",
+		},
+		{
+			name:    "union without branch name",
+			doc:     `{"id": 1, "email": "jane@example.com", "tags": [], "avatar": ""}`,
+			wantErr: "avro: email: union value must be null or an object with a single branch name",
+		},
+		{
+			name:    "unknown union branch",
+			doc:     `{"id": 1, "email": {"int": 1}, "tags": [], "avatar": ""}`,
+			wantErr: "avro: email: unknown union branch int",
+		},
+		{
+			name:    "array item",
+			doc:     `{"id": 1, "email": null, "tags": ["a", 1], "avatar": ""}`,
+			wantErr: "avro: tags[1]: cannot encode JSON number as string",
+		},
+		{
+			name:    "invalid base64",
+			doc:     `{"id": 1, "email": null, "tags": [], "avatar": "!"}`,
+			wantErr: "avro: avatar: invalid base64 bytes: illegal base64 data at input byte 0",
+		},
+		{
+			name:    "missing field",
+			doc:     `{"id": 1, "email": null, "avatar": ""}`,
+			wantErr: "avro: tags: missing field without default",
+		},
+		{
+			name:    "unknown field",
+			doc:     `{"id": 1, "email": null, "tags": [], "avatar": "", "name": "Jane"}`,
+			wantErr: "avro: unknown field name",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := avro.MarshalJSON(schema, []byte(test.doc))
+
+			assert.EqualError(t, err, test.wantErr)
+		})
+	}
+}
+
+func TestUnmarshalToJSON_Errors(t *testing.T) {
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "User",
+		"fields": [
+			{"name": "role", "type": {"type": "enum", "name": "Role", "symbols": ["ADMIN", "USER"]}},
+			{"name": "score", "type": "double"}
+		]
+	}`)
+
+	_, err := avro.UnmarshalToJSON(schema, []byte{0x06})
+	assert.EqualError(t, err, "avro: role: enum index 3 out of range")
+
+	_, err = avro.UnmarshalToJSON(schema, []byte{0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xf8, 0x7f})
+	assert.EqualError(t, err, "avro: score: NaN cannot be represented in JSON")
+
+	_, err = avro.UnmarshalToJSON(schema, []byte{0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00})
+	assert.EqualError(t, err, "avro: 1 trailing bytes")
+}
+
+func TestUnmarshalToJSON_EmptyItemsLimit(t *testing.T) {
+	schema := avro.MustParse(`{"type": "array", "items": "null"}`)
+
+	got, err := avro.UnmarshalToJSON(schema, []byte{0x06, 0x00})
+	require.NoError(t, err)
+	assert.Equal(t, `[null,null,null]`, string(got))
+
+	// A block of 2^62 nulls in 10 bytes.
+	data := []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x01, 0x00}
+	_, err = avro.UnmarshalToJSON(schema, data)
+	assert.EqualError(t, err, "avro: block of 4611686018427387904 empty items is greater than `Config.MaxByteSliceSize`")
+}
+
+func TestMarshalJSON_LogicalTypeUnion(t *testing.T) {
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "Event",
+		"fields": [
+			{"name": "at", "type": ["null", {"type": "long", "logicalType": "timestamp-millis"}]}
+		]
+	}`)
+
+	got, err := avro.MarshalJSON(schema, []byte(`{"at": {"long": 1000}}`))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x02, 0xd0, 0x0f}, got)
+
+	back, err := avro.UnmarshalToJSON(schema, got)
+	require.NoError(t, err)
+	assert.Equal(t, `{"at":{"long":1000}}`, string(back))
+}
+
+func TestJSONWithAPI(t *testing.T) {
+	api := avro.Config{MaxByteSliceSize: 2}.Freeze()
+
+	got, err := avro.MarshalJSONWithAPI(api, avro.MustParse(`{"type": "array", "items": "int"}`), []byte(`[1, 2]`))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x04, 0x02, 0x04, 0x00}, got)
+
+	_, err = avro.UnmarshalToJSONWithAPI(api, avro.MustParse(`"string"`), []byte{0x06, 0x66, 0x6f, 0x6f})
+	assert.ErrorContains(t, err, "size is greater than `Config.MaxByteSliceSize`")
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"hash/crc32"
 	"io"
+	"sync"
 
 	"github.com/golang/snappy"
 	"github.com/klauspost/compress/zstd"
@@ -34,23 +35,60 @@ type zstdOptions struct {
 	DOptions []zstd.DOption
 }
 
-func resolveCodec(name CodecName, codecOpts codecOptions) (Codec, error) {
-	switch name {
-	case Null, "":
-		return &NullCodec{}, nil
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[CodecName]func() Compressor{}
+)
+
+func init() {
+	RegisterCodec(string(Null), func() Compressor { return &NullCodec{} })
+	RegisterCodec(string(Deflate), func() Compressor { return &DeflateCodec{compLvl: flate.DefaultCompression} })
+	RegisterCodec(string(Snappy), func() Compressor { return &SnappyCodec{} })
+	RegisterCodec(string(ZStandard), func() Compressor { return &ZStandardCodec{} })
+}
 
-	case Deflate:
-		return &DeflateCodec{compLvl: codecOpts.DeflateCompressionLevel}, nil
+// Compressor compresses and decompresses the data of file blocks.
+type Compressor interface {
+	// Compress compresses the given bytes.
+	Compress([]byte) ([]byte, error)
+	// Decompress decompresses the given bytes.
+	Decompress([]byte) ([]byte, error)
+}
 
-	case Snappy:
-		return &SnappyCodec{}, nil
+// RegisterCodec registers a compression codec under name, so it can be used
+// with WithCodec and resolved from the codec of files being decoded.
+// newCompressor is called for every encoder and decoder using the codec.
+// Registering a name again replaces the previous codec, including built-in ones.
+func RegisterCodec(name string, newCompressor func() Compressor) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
 
-	case ZStandard:
-		return newZStandardCodec(codecOpts.ZStandardOptions), nil
+	codecs[CodecName(name)] = newCompressor
+}
 
-	default:
+// configurableCodec is implemented by the built-in codecs that take the
+// compression options of encoders and decoders.
+type configurableCodec interface {
+	withOptions(opts codecOptions) Compressor
+}
+
+func resolveCodec(name CodecName, codecOpts codecOptions) (Compressor, error) {
+	if name == "" {
+		name = Null
+	}
+
+	codecsMu.RLock()
+	newCompressor, ok := codecs[name]
+	codecsMu.RUnlock()
+	if !ok {
 		return nil, fmt.Errorf("unknown codec %s", name)
 	}
+
+	c := newCompressor()
+	if cc, ok := c.(configurableCodec); ok {
+		c = cc.withOptions(codecOpts)
+	}
+	return c, nil
 }
 
 // Codec represents a compression codec.
@@ -74,6 +112,16 @@ func (*NullCodec) Encode(b []byte) []byte {
 	return b
 }
 
+// Compress returns the given bytes.
+func (*NullCodec) Compress(b []byte) ([]byte, error) {
+	return b, nil
+}
+
+// Decompress returns the given bytes.
+func (*NullCodec) Decompress(b []byte) ([]byte, error) {
+	return b, nil
+}
+
 // DeflateCodec is a flate compression codec.
 type DeflateCodec struct {
 	compLvl int
@@ -92,15 +140,37 @@ func (c *DeflateCodec) Decode(b []byte) ([]byte, error) {
 	return data, nil
 }
 
-// Encode encodes the given bytes.
+// Encode encodes the given bytes. It returns nil if the compression level is invalid.
 func (c *DeflateCodec) Encode(b []byte) []byte {
+	data, _ := c.Compress(b)
+	return data
+}
+
+// Compress compresses the given bytes.
+func (c *DeflateCodec) Compress(b []byte) ([]byte, error) {
 	data := bytes.NewBuffer(make([]byte, 0, len(b)))
 
-	w, _ := flate.NewWriter(data, c.compLvl)
-	_, _ = w.Write(b)
-	_ = w.Close()
+	w, err := flate.NewWriter(data, c.compLvl)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(b); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+
+	return data.Bytes(), nil
+}
+
+// Decompress decompresses the given bytes.
+func (c *DeflateCodec) Decompress(b []byte) ([]byte, error) {
+	return c.Decode(b)
+}
 
-	return data.Bytes()
+func (c *DeflateCodec) withOptions(opts codecOptions) Compressor {
+	return &DeflateCodec{compLvl: opts.DeflateCompressionLevel}
 }
 
 // SnappyCodec is a snappy compression codec.
@@ -119,8 +189,8 @@ func (*SnappyCodec) Decode(b []byte) ([]byte, error) {
 	}
 
 	crc := binary.BigEndian.Uint32(b[l-4:])
-	if crc32.ChecksumIEEE(dst) != crc {
-		return nil, errors.New("snappy checksum mismatch")
+	if got := crc32.ChecksumIEEE(dst); got != crc {
+		return nil, fmt.Errorf("snappy checksum mismatch: block has crc %08x, data has crc %08x", crc, got)
 	}
 
 	return dst, nil
@@ -136,6 +206,16 @@ func (*SnappyCodec) Encode(b []byte) []byte {
 	return dst
 }
 
+// Compress compresses the given bytes.
+func (c *SnappyCodec) Compress(b []byte) ([]byte, error) {
+	return c.Encode(b), nil
+}
+
+// Decompress decompresses the given bytes.
+func (c *SnappyCodec) Decompress(b []byte) ([]byte, error) {
+	return c.Decode(b)
+}
+
 // ZStandardCodec is a zstandard compression codec.
 type ZStandardCodec struct {
 	decoder *zstd.Decoder
@@ -162,3 +242,17 @@ func (zstdCodec *ZStandardCodec) Encode(b []byte) []byte {
 	defer zstdCodec.encoder.Reset(nil)
 	return zstdCodec.encoder.EncodeAll(b, nil)
 }
+
+// Compress compresses the given bytes.
+func (zstdCodec *ZStandardCodec) Compress(b []byte) ([]byte, error) {
+	return zstdCodec.Encode(b), nil
+}
+
+// Decompress decompresses the given bytes.
+func (zstdCodec *ZStandardCodec) Decompress(b []byte) ([]byte, error) {
+	return zstdCodec.Decode(b)
+}
+
+func (zstdCodec *ZStandardCodec) withOptions(opts codecOptions) Compressor {
+	return newZStandardCodec(opts.ZStandardOptions)
+}
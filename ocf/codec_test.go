@@ -64,8 +64,9 @@ func BenchmarkZstdEncodeDecodeLowEntropyLong(b *testing.B) {
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		compressed := codec.Encode(input)
-		_, decodeErr := codec.Decode(compressed)
+		compressed, encodeErr := codec.Compress(input)
+		require.NoError(b, encodeErr)
+		_, decodeErr := codec.Decompress(compressed)
 		require.NoError(b, decodeErr)
 	}
 }
@@ -79,8 +80,9 @@ func BenchmarkZstdEncodeDecodeHighEntropyLong(b *testing.B) {
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		compressed := codec.Encode(input)
-		_, decodeErr := codec.Decode(compressed)
+		compressed, encodeErr := codec.Compress(input)
+		require.NoError(b, encodeErr)
+		_, decodeErr := codec.Decompress(compressed)
 		require.NoError(b, decodeErr)
 	}
 }
@@ -89,8 +91,9 @@ func verifyZstdEncodeDecode(t *testing.T, input []byte) {
 	codec, err := resolveCodec(ZStandard, codecOptions{})
 	require.NoError(t, err)
 
-	compressed := codec.Encode(input)
-	actual, decodeErr := codec.Decode(compressed)
+	compressed, encodeErr := codec.Compress(input)
+	require.NoError(t, encodeErr)
+	actual, decodeErr := codec.Decompress(compressed)
 
 	require.NoError(t, decodeErr)
 	assert.Equal(t, input, actual)
@@ -12,15 +12,20 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"sync"
 
 	"github.com/hamba/avro/v2"
 	"github.com/hamba/avro/v2/internal/bytesx"
 	"github.com/klauspost/compress/zstd"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
 	schemaKey = "avro.schema"
 	codecKey  = "avro.codec"
+
+	reservedKeyPrefix = "avro."
 )
 
 var (
@@ -57,6 +62,9 @@ type decoderConfig struct {
 	DecoderConfig avro.API
 	SchemaCache   *avro.SchemaCache
 	CodecOptions  codecOptions
+	MessagePool   *sync.Pool
+	Parallelism   int
+	Strict        bool
 }
 
 // DecoderFunc represents a configuration function for Decoder.
@@ -84,18 +92,67 @@ func WithZStandardDecoderOptions(opts ...zstd.DOption) DecoderFunc {
 	}
 }
 
+// WithMessagePool sets the pool DecodeMessage obtains protobuf messages from.
+// The pool must return proto.Message values.
+func WithMessagePool(pool *sync.Pool) DecoderFunc {
+	return func(cfg *decoderConfig) {
+		cfg.MessagePool = pool
+	}
+}
+
+// WithParallelism sets the number of blocks the decoder decompresses concurrently.
+// Blocks are still read in order, and their values are returned in file order, so
+// HasNext and Decode behave as without it. A block error is returned once the values
+// of the preceding blocks have been read. Up to n blocks are held in memory, and
+// a registered codec is created n times. A value of 1 or less, the default,
+// decompresses each block when its values are first read.
+func WithParallelism(n int) DecoderFunc {
+	return func(cfg *decoderConfig) {
+		cfg.Parallelism = n
+	}
+}
+
+// WithStrictValidation makes the decoder check that each block holds exactly as many
+// values as its declared count. Decode and NextRaw return an error if a block ends
+// before its last value, or if data is left in a block after its last value, which
+// is otherwise ignored.
+func WithStrictValidation() DecoderFunc {
+	return func(cfg *decoderConfig) {
+		cfg.Strict = true
+	}
+}
+
 // Decoder reads and decodes Avro values from a container file.
 type Decoder struct {
 	reader      *avro.Reader
 	resetReader *bytesx.ResetReader
-	decoder     *avro.Decoder
+	blockReader *avro.Reader
 	meta        map[string][]byte
 	sync        [16]byte
 	schema      avro.Schema
 
-	codec Codec
+	codec Compressor
+
+	msgPool *sync.Pool
 
+	// With parallelism, blocks are read ahead and decompressed concurrently,
+	// each with a codec taken from codecs.
+	parallelism int
+	codecs      chan Compressor
+	pending     []*pendingBlock
+	readErr     error
+
+	strict     bool
+	blockCount int64
+	count      int64
+}
+
+// pendingBlock is a block being decompressed. Its data and error are set once done is closed.
+type pendingBlock struct {
 	count int64
+	done  chan struct{}
+	data  []byte
+	err   error
 }
 
 // NewDecoder returns a new decoder that reads from reader r.
@@ -120,15 +177,28 @@ func NewDecoder(r io.Reader, opts ...DecoderFunc) (*Decoder, error) {
 
 	decReader := bytesx.NewResetReader([]byte{})
 
-	return &Decoder{
+	dec := &Decoder{
 		reader:      reader,
 		resetReader: decReader,
-		decoder:     cfg.DecoderConfig.NewDecoder(h.Schema, decReader),
+		blockReader: avro.NewReader(decReader, 512, avro.WithReaderConfig(cfg.DecoderConfig)),
 		meta:        h.Meta,
 		sync:        h.Sync,
 		codec:       h.Codec,
 		schema:      h.Schema,
-	}, nil
+		msgPool:     cfg.MessagePool,
+		strict:      cfg.Strict,
+	}
+	if cfg.Parallelism > 1 {
+		dec.parallelism = cfg.Parallelism
+		dec.codecs = make(chan Compressor, cfg.Parallelism)
+		dec.codecs <- h.Codec
+		for range cfg.Parallelism - 1 {
+			// The codec was already resolved from the header.
+			codec, _ := resolveCodec(CodecName(h.Meta[codecKey]), cfg.CodecOptions)
+			dec.codecs <- codec
+		}
+	}
+	return dec, nil
 }
 
 // Metadata returns the header metadata.
@@ -142,11 +212,21 @@ func (d *Decoder) Schema() avro.Schema {
 	return d.schema
 }
 
+// Codec returns the name of the codec the blocks of the file are compressed with,
+// as read from the file's metadata.
+func (d *Decoder) Codec() CodecName {
+	if name := CodecName(d.meta[codecKey]); name != "" {
+		return name
+	}
+	return Null
+}
+
 // HasNext determines if there is another value to read.
 func (d *Decoder) HasNext() bool {
 	if d.count <= 0 {
 		count := d.readBlock()
 		d.count = count
+		d.blockCount = count
 	}
 
 	if d.reader.Error != nil {
@@ -164,7 +244,72 @@ func (d *Decoder) Decode(v any) error {
 
 	d.count--
 
-	return d.decoder.Decode(v)
+	d.blockReader.ReadVal(d.schema, v)
+	return d.blockError()
+}
+
+// DecodeMessage reads the next Avro encoded value into a protobuf message obtained
+// from the message pool. The message is reset before it is decoded into, and can be
+// put back into the pool once the caller is done with it.
+func (d *Decoder) DecodeMessage() (proto.Message, error) {
+	if d.msgPool == nil {
+		return nil, errors.New("decoder: no message pool configured")
+	}
+
+	msg, ok := d.msgPool.Get().(proto.Message)
+	if !ok {
+		return nil, errors.New("decoder: message pool did not return a proto.Message")
+	}
+	proto.Reset(msg)
+
+	if err := d.Decode(msg); err != nil {
+		d.msgPool.Put(msg)
+		return nil, err
+	}
+	return msg, nil
+}
+
+// NextRaw returns the Avro encoded bytes of the next value without decoding it.
+// The bytes can be decoded independently using the decoder schema.
+func (d *Decoder) NextRaw() ([]byte, error) {
+	if d.count <= 0 {
+		return nil, errors.New("decoder: no data found, call HasNext first")
+	}
+
+	d.count--
+
+	raw := d.blockReader.ReadRaw(d.schema)
+	if err := d.blockError(); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+func (d *Decoder) blockError() error {
+	if d.strict {
+		return d.strictBlockError()
+	}
+
+	//nolint:errorlint // Only direct EOF errors should be discarded.
+	if d.blockReader.Error == io.EOF {
+		return nil
+	}
+	return d.blockReader.Error
+}
+
+// strictBlockError returns the block reader error, or an error if the block does not
+// hold exactly its count of values.
+func (d *Decoder) strictBlockError() error {
+	if errors.Is(d.blockReader.Error, io.EOF) || errors.Is(d.blockReader.Error, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("decoder: block ended before its %d values were read", d.blockCount)
+	}
+	if d.blockReader.Error != nil {
+		return d.blockReader.Error
+	}
+	if d.count == 0 && d.blockReader.More() {
+		return fmt.Errorf("decoder: block has data left after its %d values", d.blockCount)
+	}
+	return nil
 }
 
 // Error returns the last reader error.
@@ -177,31 +322,38 @@ func (d *Decoder) Error() error {
 }
 
 func (d *Decoder) readBlock() int64 {
-	_ = d.reader.Peek()
-	if errors.Is(d.reader.Error, io.EOF) {
-		// There is no next block
-		return 0
+	if d.parallelism > 1 {
+		return d.readBlockParallel()
 	}
 
-	count := d.reader.ReadLong()
-	size := d.reader.ReadLong()
-
-	// Read the blocks data
-	switch {
-	case count > 0:
-		data := make([]byte, size)
-		d.reader.Read(data)
-
-		data, err := d.codec.Decode(data)
+	count, data := d.readRawBlock()
+	if count > 0 && d.reader.Error == nil {
+		data, err := d.codec.Decompress(data)
 		if err != nil {
 			d.reader.Error = err
 		}
 
 		d.resetReader.Reset(data)
+	}
+
+	return count
+}
+
+// readRawBlock reads the next block, returning its count and compressed data.
+func (d *Decoder) readRawBlock() (int64, []byte) {
+	_ = d.reader.Peek()
+	if errors.Is(d.reader.Error, io.EOF) {
+		// There is no next block
+		return 0, nil
+	}
+
+	count := d.reader.ReadLong()
+	size := d.reader.ReadLong()
 
-	case size > 0:
-		// Skip the block data when count is 0
-		data := make([]byte, size)
+	// Read the blocks data, which is skipped when count is 0
+	var data []byte
+	if size > 0 {
+		data = make([]byte, size)
 		d.reader.Read(data)
 	}
 
@@ -212,7 +364,57 @@ func (d *Decoder) readBlock() int64 {
 		d.reader.Error = errors.New("decoder: invalid block")
 	}
 
-	return count
+	return count, data
+}
+
+// readBlockParallel reads ahead up to parallelism blocks, decompressing them
+// concurrently, and returns the count of the next block once it is decompressed.
+func (d *Decoder) readBlockParallel() int64 {
+	for d.readErr == nil && len(d.pending) < d.parallelism {
+		count, data := d.readRawBlock()
+		if d.reader.Error != nil {
+			// The error, including the end of the file, is reported once the
+			// blocks read before it have been consumed.
+			d.readErr, d.reader.Error = d.reader.Error, nil
+			break
+		}
+		d.pending = append(d.pending, d.decompress(count, data))
+	}
+
+	if len(d.pending) == 0 {
+		d.reader.Error = d.readErr
+		return 0
+	}
+
+	block := d.pending[0]
+	d.pending[0] = nil
+	d.pending = d.pending[1:]
+
+	<-block.done
+	if block.err != nil {
+		d.reader.Error = block.err
+	}
+	d.resetReader.Reset(block.data)
+
+	return block.count
+}
+
+// decompress decompresses the block data in a new goroutine, using a codec from the codecs.
+func (d *Decoder) decompress(count int64, data []byte) *pendingBlock {
+	block := &pendingBlock{count: count, done: make(chan struct{})}
+	if count <= 0 {
+		close(block.done)
+		return block
+	}
+
+	go func() {
+		defer close(block.done)
+
+		codec := <-d.codecs
+		block.data, block.err = codec.Decompress(data)
+		d.codecs <- codec
+	}()
+	return block
 }
 
 type encoderConfig struct {
@@ -225,6 +427,7 @@ type encoderConfig struct {
 	EncodingConfig  avro.API
 	SchemaCache     *avro.SchemaCache
 	SchemaMarshaler func(avro.Schema) ([]byte, error)
+	AppendReader    io.ReadSeeker
 }
 
 // EncoderFunc represents a configuration function for Encoder.
@@ -261,6 +464,15 @@ func WithCompressionLevel(compLvl int) EncoderFunc {
 	}
 }
 
+// WithZStandardLevel sets the compression codec to zstandard and
+// the compression level on the encoder.
+func WithZStandardLevel(lvl zstd.EncoderLevel) EncoderFunc {
+	return func(cfg *encoderConfig) {
+		cfg.CodecName = ZStandard
+		cfg.CodecOptions.ZStandardOptions.EOptions = append(cfg.CodecOptions.ZStandardOptions.EOptions, zstd.WithEncoderLevel(lvl))
+	}
+}
+
 // WithZStandardEncoderOptions sets the options for the ZStandard encoder.
 func WithZStandardEncoderOptions(opts ...zstd.EOption) EncoderFunc {
 	return func(cfg *encoderConfig) {
@@ -269,6 +481,7 @@ func WithZStandardEncoderOptions(opts ...zstd.EOption) EncoderFunc {
 }
 
 // WithMetadata sets the metadata on the encoder header.
+// Keys starting with "avro." are reserved and cannot be set.
 func WithMetadata(meta map[string][]byte) EncoderFunc {
 	return func(cfg *encoderConfig) {
 		cfg.Metadata = meta
@@ -276,7 +489,7 @@ func WithMetadata(meta map[string][]byte) EncoderFunc {
 }
 
 // WithMetadataKeyVal sets a single key-value pair for the metadata on
-// the encoder header.
+// the encoder header. Keys starting with "avro." are reserved and cannot be set.
 func WithMetadataKeyVal(key string, val []byte) EncoderFunc {
 	return func(cfg *encoderConfig) {
 		cfg.Metadata[key] = val
@@ -306,6 +519,17 @@ func WithSyncBlock(sync [16]byte) EncoderFunc {
 	}
 }
 
+// WithAppend appends to the existing ocf file read from r instead of writing
+// a new header. The schema and codec given to the encoder must match those of
+// the existing file, and its sync marker is reused. The writer given to the
+// encoder must write to the end of the existing file. The header of the existing
+// file is kept, so WithAppend cannot be used with WithMetadata or WithMetadataKeyVal.
+func WithAppend(r io.ReadSeeker) EncoderFunc {
+	return func(cfg *encoderConfig) {
+		cfg.AppendReader = r
+	}
+}
+
 // WithEncodingConfig sets the value encoder config on the OCF encoder.
 func WithEncodingConfig(wCfg avro.API) EncoderFunc {
 	return func(cfg *encoderConfig) {
@@ -320,7 +544,7 @@ type Encoder struct {
 	encoder *avro.Encoder
 	sync    [16]byte
 
-	codec Codec
+	codec Compressor
 
 	blockLength int
 	count       int
@@ -349,6 +573,19 @@ func NewEncoderWithSchema(schema avro.Schema, w io.Writer, opts ...EncoderFunc)
 }
 
 func newEncoder(schema avro.Schema, w io.Writer, cfg encoderConfig) (*Encoder, error) {
+	for key := range cfg.Metadata {
+		if strings.HasPrefix(key, reservedKeyPrefix) {
+			return nil, fmt.Errorf("metadata key %q is reserved", key)
+		}
+	}
+
+	if cfg.AppendReader != nil && w != nil {
+		if len(cfg.Metadata) > 0 {
+			return nil, errors.New("metadata cannot be set when appending to an existing file")
+		}
+		return newAppendEncoder(schema, cfg.AppendReader, w, cfg)
+	}
+
 	switch file := w.(type) {
 	case nil:
 		return nil, errors.New("writer cannot be nil")
@@ -368,18 +605,7 @@ func newEncoder(schema avro.Schema, w io.Writer, cfg encoderConfig) (*Encoder, e
 				return nil, err
 			}
 
-			writer := avro.NewWriter(w, 512, avro.WithWriterConfig(cfg.EncodingConfig))
-			buf := &bytes.Buffer{}
-			e := &Encoder{
-				writer:      writer,
-				buf:         buf,
-				encoder:     cfg.EncodingConfig.NewEncoder(h.Schema, buf),
-				sync:        h.Sync,
-				codec:       h.Codec,
-				blockLength: cfg.BlockLength,
-				blockSize:   cfg.BlockSize,
-			}
-			return e, nil
+			return newEncoderFromHeader(h, w, cfg), nil
 		}
 	}
 
@@ -423,6 +649,52 @@ func newEncoder(schema avro.Schema, w io.Writer, cfg encoderConfig) (*Encoder, e
 	return e, nil
 }
 
+func newAppendEncoder(schema avro.Schema, r io.ReadSeeker, w io.Writer, cfg encoderConfig) (*Encoder, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	reader := avro.NewReader(r, 1024)
+	h, err := readHeader(reader, cfg.SchemaCache, cfg.CodecOptions)
+	if err != nil {
+		return nil, err
+	}
+	if h.Schema.Fingerprint() != schema.Fingerprint() {
+		return nil, errors.New("schema does not match the schema of the existing file")
+	}
+	codecName := CodecName(h.Meta[codecKey])
+	if codecName == "" {
+		codecName = Null
+	}
+	if codecName != cfg.CodecName {
+		return nil, fmt.Errorf("codec %s does not match the codec %s of the existing file", cfg.CodecName, codecName)
+	}
+	if err = skipToEnd(reader, h.Sync); err != nil {
+		return nil, err
+	}
+
+	// Leave the reader at the end of the file, in case it is also the writer.
+	if _, err = r.Seek(0, io.SeekEnd); err != nil {
+		return nil, err
+	}
+
+	return newEncoderFromHeader(h, w, cfg), nil
+}
+
+func newEncoderFromHeader(h *ocfHeader, w io.Writer, cfg encoderConfig) *Encoder {
+	writer := avro.NewWriter(w, 512, avro.WithWriterConfig(cfg.EncodingConfig))
+	buf := &bytes.Buffer{}
+	return &Encoder{
+		writer:      writer,
+		buf:         buf,
+		encoder:     cfg.EncodingConfig.NewEncoder(h.Schema, buf),
+		sync:        h.Sync,
+		codec:       h.Codec,
+		blockLength: cfg.BlockLength,
+		blockSize:   cfg.BlockSize,
+	}
+}
+
 func computeEncoderConfig(opts []EncoderFunc) encoderConfig {
 	cfg := encoderConfig{
 		BlockLength: 100,
@@ -502,10 +774,12 @@ func (e *Encoder) Close() error {
 }
 
 func (e *Encoder) writerBlock() error {
-	e.writer.WriteLong(int64(e.count))
-
-	b := e.codec.Encode(e.buf.Bytes())
+	b, err := e.codec.Compress(e.buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("compressing block: %w", err)
+	}
 
+	e.writer.WriteLong(int64(e.count))
 	e.writer.WriteLong(int64(len(b)))
 	_, _ = e.writer.Write(b)
 
@@ -518,7 +792,7 @@ func (e *Encoder) writerBlock() error {
 
 type ocfHeader struct {
 	Schema avro.Schema
-	Codec  Codec
+	Codec  Compressor
 	Meta   map[string][]byte
 	Sync   [16]byte
 }
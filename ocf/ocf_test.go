@@ -199,6 +199,36 @@ func TestDecoder_WithDeflate(t *testing.T) {
 	assert.Equal(t, 1, count)
 }
 
+func TestDecoder_SchemaAndCodec(t *testing.T) {
+	schema := avro.MustParse(`{"type":"record","name":"test","fields":[{"name":"a","type":"long"}]}`)
+
+	tests := []struct {
+		name string
+		opts []ocf.EncoderFunc
+		want ocf.CodecName
+	}{
+		{name: "Null", want: ocf.Null},
+		{name: "Deflate", opts: []ocf.EncoderFunc{ocf.WithCodec(ocf.Deflate)}, want: ocf.Deflate},
+		{name: "Snappy", opts: []ocf.EncoderFunc{ocf.WithCodec(ocf.Snappy)}, want: ocf.Snappy},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			enc, err := ocf.NewEncoderWithSchema(schema, buf, test.opts...)
+			require.NoError(t, err)
+			require.NoError(t, enc.Encode(map[string]any{"a": int64(1)}))
+			require.NoError(t, enc.Close())
+
+			dec, err := ocf.NewDecoder(buf)
+			require.NoError(t, err)
+
+			assert.Equal(t, schema.String(), dec.Schema().String())
+			assert.Equal(t, test.want, dec.Codec())
+		})
+	}
+}
+
 func TestDecoder_InvalidName(t *testing.T) {
 	type record struct {
 		Hello int    `avro:"hello"`
@@ -352,6 +382,26 @@ func TestDecoder_WithSnappyHandlesInvalidCRC(t *testing.T) {
 	assert.Error(t, dec.Error())
 }
 
+func TestDecoder_WithSnappyDetectsCorruptBlock(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(`"string"`, buf, ocf.WithCodec(ocf.Snappy))
+	require.NoError(t, err)
+	err = enc.Encode("some snappy compressed value")
+	require.NoError(t, err)
+	err = enc.Close()
+	require.NoError(t, err)
+
+	// The block ends with its checksum, followed by the 16 byte sync marker.
+	data := buf.Bytes()
+	data[len(data)-17] ^= 0xff
+
+	dec, err := ocf.NewDecoder(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	assert.False(t, dec.HasNext())
+	assert.ErrorContains(t, dec.Error(), "snappy checksum mismatch")
+}
+
 func TestDecoder_WithZStandard(t *testing.T) {
 	unionStr := "union value"
 	want := FullRecord{
@@ -490,6 +540,96 @@ func TestDecoder_DecodeMustCallHasNext(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestDecoder_NextRaw(t *testing.T) {
+	records := make([]FullRecord, 5)
+	for i := range records {
+		str := strings.Repeat("a", i*300)
+		records[i] = FullRecord{
+			Strings:  []string{str, "string2"},
+			Longs:    []int64{int64(i), 2, 3},
+			Enum:     "B",
+			Map:      map[string]int{"key": i},
+			Nullable: &str,
+			Record:   &TestRecord{Long: int64(i), String: str},
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(schema, buf, ocf.WithBlockLength(2), ocf.WithCodec(ocf.Deflate))
+	require.NoError(t, err)
+	for _, record := range records {
+		err = enc.Encode(record)
+		require.NoError(t, err)
+	}
+	err = enc.Close()
+	require.NoError(t, err)
+
+	dec, err := ocf.NewDecoder(buf)
+	require.NoError(t, err)
+
+	var got []FullRecord
+	for dec.HasNext() {
+		raw, err := dec.NextRaw()
+		require.NoError(t, err)
+
+		var record FullRecord
+		err = avro.Unmarshal(dec.Schema(), raw, &record)
+		require.NoError(t, err)
+		got = append(got, record)
+	}
+
+	require.NoError(t, dec.Error())
+	assert.Equal(t, records, got)
+}
+
+func TestDecoder_NextRawInterleavedWithDecode(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(`"string"`, buf)
+	require.NoError(t, err)
+	for _, str := range []string{"foo", "bar", "baz", "qux"} {
+		err = enc.Encode(str)
+		require.NoError(t, err)
+	}
+	err = enc.Close()
+	require.NoError(t, err)
+
+	dec, err := ocf.NewDecoder(buf)
+	require.NoError(t, err)
+
+	var got []string
+	for i := 0; dec.HasNext(); i++ {
+		var str string
+		if i%2 == 0 {
+			err = dec.Decode(&str)
+			require.NoError(t, err)
+		} else {
+			raw, err := dec.NextRaw()
+			require.NoError(t, err)
+			err = avro.Unmarshal(dec.Schema(), raw, &str)
+			require.NoError(t, err)
+		}
+		got = append(got, str)
+	}
+
+	require.NoError(t, dec.Error())
+	assert.Equal(t, []string{"foo", "bar", "baz", "qux"}, got)
+}
+
+func TestDecoder_NextRawMustCallHasNext(t *testing.T) {
+	data := []byte{
+		'O', 'b', 'j', 0x01, 0x01, 0x26, 0x16, 'a', 'v', 'r', 'o', '.', 's', 'c', 'h', 'e', 'm', 'a',
+		0x0c, '"', 'l', 'o', 'n', 'g', '"', 0x00, 0xfb, 0x2b, 0x0f, 0x1a, 0xdd, 0xfd, 0x90, 0x7d, 0x87, 0x12,
+		0x15, 0x29, 0xd7, 0x1d, 0x1c, 0xdd, 0x02, 0x02, 0x02, 0xfb, 0x2b, 0x0f, 0x1a, 0xdd, 0xfd, 0x90, 0x7d,
+		0x87, 0x12, 0x15, 0x29, 0xd7, 0x1d, 0x1c, 0xdd,
+	}
+
+	dec, _ := ocf.NewDecoder(bytes.NewReader(data))
+
+	_, err := dec.NextRaw()
+
+	assert.Error(t, err)
+}
+
 func TestDecoder_InvalidBlock(t *testing.T) {
 	data := []byte{
 		'O', 'b', 'j', 0x01, 0x01, 0x26, 0x16, 'a', 'v', 'r', 'o', '.', 's', 'c', 'h', 'e', 'm', 'a',
@@ -506,6 +646,144 @@ func TestDecoder_InvalidBlock(t *testing.T) {
 	assert.Error(t, dec.Error())
 }
 
+func TestDecoder_WithStrictValidation(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(`"long"`, buf)
+	require.NoError(t, err)
+	for i := range int64(4) {
+		require.NoError(t, enc.Encode(i))
+	}
+	require.NoError(t, enc.Close())
+
+	decodeAll := func(data []byte, opts ...ocf.DecoderFunc) ([]int64, error) {
+		dec, err := ocf.NewDecoder(bytes.NewReader(data), opts...)
+		require.NoError(t, err)
+
+		var got []int64
+		for dec.HasNext() {
+			var v int64
+			if err = dec.Decode(&v); err != nil {
+				return got, err
+			}
+			got = append(got, v)
+		}
+		return got, dec.Error()
+	}
+
+	got, err := decodeAll(buf.Bytes(), ocf.WithStrictValidation())
+	require.NoError(t, err)
+	assert.Equal(t, []int64{0, 1, 2, 3}, got)
+
+	// The block count follows the sync marker ending the header.
+	data := bytes.Clone(buf.Bytes())
+	countIdx := bytes.Index(data, data[len(data)-16:]) + 16
+	require.Equal(t, byte(0x08), data[countIdx])
+
+	// Without strict validation, the values after the count are silently dropped.
+	data[countIdx] = 0x06
+	got, err = decodeAll(data)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{0, 1, 2}, got)
+
+	tests := []struct {
+		name    string
+		count   byte
+		want    []int64
+		wantErr string
+	}{
+		{
+			name:    "count too low",
+			count:   0x06,
+			want:    []int64{0, 1},
+			wantErr: "decoder: block has data left after its 3 values",
+		},
+		{
+			name:    "count too high",
+			count:   0x0a,
+			want:    []int64{0, 1, 2, 3},
+			wantErr: "decoder: block ended before its 5 values were read",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := bytes.Clone(buf.Bytes())
+			data[countIdx] = test.count
+
+			got, err := decodeAll(data, ocf.WithStrictValidation())
+			assert.Equal(t, test.want, got)
+			assert.EqualError(t, err, test.wantErr)
+		})
+	}
+}
+
+func TestDecoder_WithParallelism(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(`"long"`, buf, ocf.WithCodec(ocf.Deflate), ocf.WithBlockLength(7))
+	require.NoError(t, err)
+	for i := range int64(100) {
+		require.NoError(t, enc.Encode(i*i))
+	}
+	require.NoError(t, enc.Close())
+
+	decodeAll := func(opts ...ocf.DecoderFunc) []int64 {
+		dec, err := ocf.NewDecoder(bytes.NewReader(buf.Bytes()), opts...)
+		require.NoError(t, err)
+
+		var got []int64
+		for dec.HasNext() {
+			var v int64
+			require.NoError(t, dec.Decode(&v))
+			got = append(got, v)
+		}
+		require.NoError(t, dec.Error())
+		return got
+	}
+
+	want := decodeAll()
+	require.Len(t, want, 100)
+	assert.Equal(t, want, decodeAll(ocf.WithParallelism(4)))
+	assert.Equal(t, want, decodeAll(ocf.WithParallelism(100)))
+}
+
+func TestDecoder_WithParallelismReportsErrorsInOrder(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(`"long"`, buf, ocf.WithCodec(ocf.Deflate), ocf.WithBlockLength(2))
+	require.NoError(t, err)
+	for i := range int64(6) {
+		require.NoError(t, enc.Encode(i))
+	}
+	require.NoError(t, enc.Close())
+
+	// Corrupt the sync marker of the second block.
+	data := buf.Bytes()
+	sync := bytes.Clone(data[len(data)-16:])
+	var syncs []int
+	for i := 0; ; {
+		j := bytes.Index(data[i:], sync)
+		if j < 0 {
+			break
+		}
+		syncs = append(syncs, i+j)
+		i += j + len(sync)
+	}
+	require.Len(t, syncs, 4) // The header and three blocks.
+	data[syncs[2]] ^= 0xff
+
+	dec, err := ocf.NewDecoder(bytes.NewReader(data), ocf.WithParallelism(3))
+	require.NoError(t, err)
+
+	var got []int64
+	for dec.HasNext() {
+		var v int64
+		require.NoError(t, dec.Decode(&v))
+		got = append(got, v)
+	}
+
+	assert.Equal(t, []int64{0, 1}, got)
+	assert.EqualError(t, dec.Error(), "decoder: invalid block")
+}
+
 func TestDecoder_WithConfig(t *testing.T) {
 	const defaultMax = 1_048_576
 
@@ -730,6 +1008,104 @@ func TestEncoder_ExistingOCF(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
+func TestEncoder_WithAppend(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(`"long"`, buf, ocf.WithCodec(ocf.Deflate))
+	require.NoError(t, err)
+	for _, v := range []int64{1, 2, 3} {
+		err = enc.Encode(v)
+		require.NoError(t, err)
+	}
+	err = enc.Close()
+	require.NoError(t, err)
+
+	existing := bytes.NewReader(bytes.Clone(buf.Bytes()))
+	enc, err = ocf.NewEncoder(`"long"`, buf, ocf.WithCodec(ocf.Deflate), ocf.WithAppend(existing))
+	require.NoError(t, err)
+	for _, v := range []int64{4, 5} {
+		err = enc.Encode(v)
+		require.NoError(t, err)
+	}
+	err = enc.Close()
+	require.NoError(t, err)
+
+	dec, err := ocf.NewDecoder(buf)
+	require.NoError(t, err)
+
+	var got []int64
+	for dec.HasNext() {
+		var v int64
+		err = dec.Decode(&v)
+		require.NoError(t, err)
+		got = append(got, v)
+	}
+
+	require.NoError(t, dec.Error())
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, got)
+}
+
+func TestEncoder_WithAppendValidatesFile(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(`"long"`, buf, ocf.WithCodec(ocf.Deflate))
+	require.NoError(t, err)
+	err = enc.Encode(int64(1))
+	require.NoError(t, err)
+	err = enc.Close()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		schema  string
+		opts    []ocf.EncoderFunc
+		wantErr string
+	}{
+		{
+			name:    "Schema",
+			schema:  `"string"`,
+			opts:    []ocf.EncoderFunc{ocf.WithCodec(ocf.Deflate)},
+			wantErr: "schema does not match",
+		},
+		{
+			name:    "Codec",
+			schema:  `"long"`,
+			opts:    []ocf.EncoderFunc{ocf.WithCodec(ocf.Snappy)},
+			wantErr: "codec snappy does not match the codec deflate",
+		},
+		{
+			name:    "DefaultCodec",
+			schema:  `"long"`,
+			wantErr: "codec null does not match the codec deflate",
+		},
+		{
+			name:    "Metadata",
+			schema:  `"long"`,
+			opts:    []ocf.EncoderFunc{ocf.WithCodec(ocf.Deflate), ocf.WithMetadata(map[string][]byte{"test": []byte("foo")})},
+			wantErr: "metadata cannot be set when appending to an existing file",
+		},
+		{
+			name:    "MetadataKeyVal",
+			schema:  `"long"`,
+			opts:    []ocf.EncoderFunc{ocf.WithCodec(ocf.Deflate), ocf.WithMetadataKeyVal("test", []byte("foo"))},
+			wantErr: "metadata cannot be set when appending to an existing file",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts := append(test.opts, ocf.WithAppend(bytes.NewReader(buf.Bytes())))
+			_, err := ocf.NewEncoder(test.schema, io.Discard, opts...)
+
+			assert.ErrorContains(t, err, test.wantErr)
+		})
+	}
+}
+
+func TestEncoder_WithAppendInvalidFile(t *testing.T) {
+	_, err := ocf.NewEncoder(`"long"`, io.Discard, ocf.WithAppend(strings.NewReader("test")))
+
+	assert.Error(t, err)
+}
+
 func TestEncoder_NilWriter(t *testing.T) {
 	_, err := ocf.NewEncoder(schema, nil)
 
@@ -965,6 +1341,144 @@ func TestEncoder_EncodeCompressesZStandardWithLevel(t *testing.T) {
 	assert.Equal(t, 942, buf.Len())
 }
 
+type xorCompressor struct {
+	key byte
+}
+
+func (c xorCompressor) Compress(b []byte) ([]byte, error) {
+	out := make([]byte, len(b))
+	for i := range b {
+		out[i] = b[i] ^ c.key
+	}
+	return out, nil
+}
+
+func (c xorCompressor) Decompress(b []byte) ([]byte, error) {
+	return c.Compress(b)
+}
+
+type failingCompressor struct{}
+
+func (failingCompressor) Compress([]byte) ([]byte, error) {
+	return nil, errors.New("test")
+}
+
+func (failingCompressor) Decompress(b []byte) ([]byte, error) {
+	return b, nil
+}
+
+func TestEncoder_RegisteredCodec(t *testing.T) {
+	ocf.RegisterCodec("xor", func() ocf.Compressor { return xorCompressor{key: 0x5a} })
+
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(`"string"`, buf, ocf.WithCodec("xor"))
+	require.NoError(t, err)
+
+	want := []string{"first value", "second value"}
+	for _, v := range want {
+		err = enc.Encode(v)
+		require.NoError(t, err)
+	}
+	err = enc.Close()
+	require.NoError(t, err)
+
+	assert.NotContains(t, buf.String(), "first value")
+
+	dec, err := ocf.NewDecoder(buf)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("xor"), dec.Metadata()["avro.codec"])
+
+	var got []string
+	for dec.HasNext() {
+		var v string
+		err = dec.Decode(&v)
+		require.NoError(t, err)
+		got = append(got, v)
+	}
+	require.NoError(t, dec.Error())
+	assert.Equal(t, want, got)
+}
+
+func TestEncoder_RegisteredCodecError(t *testing.T) {
+	ocf.RegisterCodec("failing", func() ocf.Compressor { return failingCompressor{} })
+
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(`"string"`, buf, ocf.WithCodec("failing"))
+	require.NoError(t, err)
+
+	err = enc.Encode("value")
+	require.NoError(t, err)
+	err = enc.Close()
+
+	assert.EqualError(t, err, "compressing block: test")
+}
+
+func TestEncoder_DeflateCompressionError(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(`"string"`, buf, ocf.WithCompressionLevel(42))
+	require.NoError(t, err)
+
+	err = enc.Encode("value")
+	require.NoError(t, err)
+	err = enc.Close()
+
+	assert.EqualError(t, err, "compressing block: flate: invalid compression level 42: want value in range [-2, 9]")
+}
+
+func TestEncoder_ZStandardLevelRoundTrip(t *testing.T) {
+	unionStr := "union value"
+	record := FullRecord{
+		Strings: []string{"string1", "string2", "string3", "string4", "string5"},
+		Longs:   []int64{1, 2, 3, 4, 5},
+		Enum:    "C",
+		Map: map[string]int{
+			"key1": 1,
+			"key2": 2,
+			"key3": 3,
+			"key4": 4,
+			"key5": 5,
+		},
+		Nullable: &unionStr,
+		Fixed:    [16]byte{0x01, 0x02, 0x03, 0x04, 0x01, 0x02, 0x03, 0x04, 0x01, 0x02, 0x03, 0x04, 0x01, 0x02, 0x03, 0x04},
+		Record: &TestRecord{
+			Long:   1925639126735,
+			String: "I am a test record",
+			Int:    666,
+			Float:  7171.17,
+			Double: 916734926348163.01973408746523,
+			Bool:   true,
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(schema, buf, ocf.WithZStandardLevel(zstd.SpeedBestCompression))
+	require.NoError(t, err)
+
+	for range 3 {
+		err = enc.Encode(record)
+		require.NoError(t, err)
+	}
+	err = enc.Close()
+	require.NoError(t, err)
+
+	dec, err := ocf.NewDecoder(buf)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("zstandard"), dec.Metadata()["avro.codec"])
+
+	var count int
+	for dec.HasNext() {
+		count++
+		var got FullRecord
+		err = dec.Decode(&got)
+
+		require.NoError(t, err)
+		assert.Equal(t, record, got)
+	}
+
+	require.NoError(t, dec.Error())
+	assert.Equal(t, 3, count)
+}
+
 func TestEncoder_EncodeError(t *testing.T) {
 	buf := &bytes.Buffer{}
 	enc, err := ocf.NewEncoder(`"long"`, buf)
@@ -1066,6 +1580,55 @@ func TestEncodeDecodeMetadataKeyVal(t *testing.T) {
 	assert.Equal(t, []byte("val2"), dec.Metadata()["key2"])
 }
 
+func TestEncodeDecodeMetadataCustomKey(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(`"long"`, buf, ocf.WithCodec(ocf.Snappy), ocf.WithMetadataKeyVal("source.system", []byte("billing")))
+	require.NoError(t, err)
+
+	err = enc.Encode(int64(1))
+	require.NoError(t, err)
+
+	err = enc.Close()
+	require.NoError(t, err)
+
+	dec, err := ocf.NewDecoder(buf)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"avro.schema":   []byte(`"long"`),
+		"avro.codec":    []byte("snappy"),
+		"source.system": []byte("billing"),
+	}, dec.Metadata())
+}
+
+func TestEncoder_ReservedMetadataKey(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  ocf.EncoderFunc
+	}{
+		{
+			name: "Metadata",
+			opt:  ocf.WithMetadata(map[string][]byte{"avro.codec": []byte("deflate")}),
+		},
+		{
+			name: "MetadataKeyVal",
+			opt:  ocf.WithMetadataKeyVal("avro.schema", []byte(`"string"`)),
+		},
+		{
+			name: "UnknownAvroKey",
+			opt:  ocf.WithMetadataKeyVal("avro.custom", []byte("foo")),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ocf.NewEncoder(`"long"`, &bytes.Buffer{}, test.opt)
+
+			assert.ErrorContains(t, err, "is reserved")
+		})
+	}
+}
+
 func TestEncode_WithSyncBlock(t *testing.T) {
 	buf := &bytes.Buffer{}
 	syncBlock := [16]byte{9, 9, 9, 9, 9, 9, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
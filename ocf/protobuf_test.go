@@ -2,6 +2,7 @@ package ocf_test
 
 import (
 	"bytes"
+	"sync"
 	"testing"
 
 	"github.com/hamba/avro/v2"
@@ -9,6 +10,7 @@ import (
 	testpb "github.com/hamba/avro/v2/testdata/protobuf"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestEncoder_Protobuf(t *testing.T) {
@@ -164,6 +166,92 @@ func TestEncoder_Protobuf_WithCompression(t *testing.T) {
 	assert.Equal(t, original.Score, decoded.Score)
 }
 
+func TestEncoder_Protobuf_Collections(t *testing.T) {
+	listSchema := `{
+		"type": "record",
+		"name": "ListMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "tags", "type": {"type": "array", "items": "string"}},
+			{"name": "numbers", "type": {"type": "array", "items": "int"}}
+		]
+	}`
+	mapSchema := `{
+		"type": "record",
+		"name": "MapMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "labels", "type": {"type": "map", "values": "string"}},
+			{"name": "scores", "type": {"type": "map", "values": "int"}}
+		]
+	}`
+
+	lists := []proto.Message{
+		&testpb.ListMessage{Id: 1, Tags: []string{"a", "b", "c"}, Numbers: []int32{1, -2, 3}},
+		&testpb.ListMessage{Id: 2},
+		&testpb.ListMessage{Id: 3, Tags: []string{""}, Numbers: []int32{0}},
+	}
+	maps := []proto.Message{
+		&testpb.MapMessage{Id: 1, Labels: map[string]string{"env": "prod", "team": "core"}, Scores: map[string]int32{"x": 1, "y": -1}},
+		&testpb.MapMessage{Id: 2},
+		&testpb.MapMessage{Id: 3, Labels: map[string]string{"": ""}, Scores: map[string]int32{"z": 0}},
+	}
+
+	tests := []struct {
+		name     string
+		schema   string
+		messages []proto.Message
+		newMsg   func() proto.Message
+	}{
+		{
+			name:     "list",
+			schema:   listSchema,
+			messages: lists,
+			newMsg:   func() proto.Message { return &testpb.ListMessage{} },
+		},
+		{
+			name:     "map",
+			schema:   mapSchema,
+			messages: maps,
+			newMsg:   func() proto.Message { return &testpb.MapMessage{} },
+		},
+	}
+
+	for _, test := range tests {
+		for _, codec := range []ocf.CodecName{ocf.Null, ocf.Deflate, ocf.Snappy, ocf.ZStandard} {
+			t.Run(test.name+"/"+string(codec), func(t *testing.T) {
+				buf := &bytes.Buffer{}
+				enc, err := ocf.NewEncoder(test.schema, buf, ocf.WithCodec(codec), ocf.WithBlockLength(2))
+				require.NoError(t, err)
+
+				for _, msg := range test.messages {
+					err = enc.Encode(msg)
+					require.NoError(t, err)
+				}
+				err = enc.Close()
+				require.NoError(t, err)
+
+				dec, err := ocf.NewDecoder(buf)
+				require.NoError(t, err)
+
+				var got []proto.Message
+				for dec.HasNext() {
+					msg := test.newMsg()
+					err = dec.Decode(msg)
+					require.NoError(t, err)
+					got = append(got, msg)
+				}
+				require.NoError(t, dec.Error())
+
+				require.Len(t, got, len(test.messages))
+				for i, want := range test.messages {
+					assert.True(t, proto.Equal(want, got[i]), "got %v, want %v", got[i], want)
+				}
+			})
+		}
+	}
+}
+
 func TestEncoder_Protobuf_Oneof(t *testing.T) {
 	schema := `{
 		"type": "record",
@@ -609,3 +697,122 @@ func TestEncoder_Protobuf_OneofWithNestedStructs_Multiple(t *testing.T) {
 	assert.Equal(t, int32(40), user2.User.Id)
 	assert.Equal(t, "User 2", user2.User.Name)
 }
+
+func TestDecoder_DecodeMessageWithPool(t *testing.T) {
+	schema := `{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "name", "type": "string"}
+		]
+	}`
+
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(schema, buf)
+	require.NoError(t, err)
+	for _, msg := range []*testpb.BasicMessage{{Id: 1, Name: "first"}, {Id: 2, Name: "second"}} {
+		err = enc.Encode(msg)
+		require.NoError(t, err)
+	}
+	err = enc.Close()
+	require.NoError(t, err)
+
+	// The pool hands out messages with fields set that are not in the schema, whether
+	// or not a message put back is reused, so they must be cleared by the decoder.
+	pool := &sync.Pool{New: func() any {
+		return &testpb.BasicMessage{Name: "stale", Active: true, Score: 99.5}
+	}}
+	dec, err := ocf.NewDecoder(buf, ocf.WithMessagePool(pool))
+	require.NoError(t, err)
+
+	require.True(t, dec.HasNext())
+	msg, err := dec.DecodeMessage()
+	require.NoError(t, err)
+	first := msg.(*testpb.BasicMessage)
+	assert.Equal(t, int32(1), first.Id)
+	assert.Equal(t, "first", first.Name)
+	assert.False(t, first.Active)
+	assert.Zero(t, first.Score)
+
+	first.Active = true
+	first.Score = 99.5
+	pool.Put(first)
+
+	require.True(t, dec.HasNext())
+	msg, err = dec.DecodeMessage()
+	require.NoError(t, err)
+	second := msg.(*testpb.BasicMessage)
+	assert.Equal(t, int32(2), second.Id)
+	assert.Equal(t, "second", second.Name)
+	assert.False(t, second.Active)
+	assert.Zero(t, second.Score)
+
+	require.False(t, dec.HasNext())
+	require.NoError(t, dec.Error())
+}
+
+func TestDecoder_DecodeMessageWithoutPool(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc, err := ocf.NewEncoder(`"int"`, buf)
+	require.NoError(t, err)
+	err = enc.Encode(1)
+	require.NoError(t, err)
+	err = enc.Close()
+	require.NoError(t, err)
+
+	dec, err := ocf.NewDecoder(buf)
+	require.NoError(t, err)
+
+	require.True(t, dec.HasNext())
+	_, err = dec.DecodeMessage()
+
+	assert.Error(t, err)
+}
+
+func BenchmarkDecoder_DecodeProtobuf(b *testing.B) {
+	schema := `{
+		"type": "record",
+		"name": "BasicMessage",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "name", "type": "string"},
+			{"name": "active", "type": "boolean"},
+			{"name": "score", "type": "double"}
+		]
+	}`
+
+	buf := &bytes.Buffer{}
+	enc, _ := ocf.NewEncoder(schema, buf, ocf.WithBlockLength(1000))
+	for i := range 1000 {
+		_ = enc.Encode(&testpb.BasicMessage{Id: int32(i), Name: "test", Active: true, Score: 99.5})
+	}
+	_ = enc.Close()
+	data := buf.Bytes()
+
+	b.Run("New", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			dec, _ := ocf.NewDecoder(bytes.NewReader(data))
+			for dec.HasNext() {
+				msg := &testpb.BasicMessage{}
+				_ = dec.Decode(msg)
+			}
+		}
+	})
+
+	b.Run("Pool", func(b *testing.B) {
+		pool := &sync.Pool{New: func() any { return &testpb.BasicMessage{} }}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			dec, _ := ocf.NewDecoder(bytes.NewReader(data), ocf.WithMessagePool(pool))
+			for dec.HasNext() {
+				msg, _ := dec.DecodeMessage()
+				pool.Put(msg)
+			}
+		}
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
+	"strconv"
 	"strings"
 	"unsafe"
 )
@@ -19,7 +21,7 @@ type ReaderFunc func(r *Reader)
 // WithReaderConfig specifies the configuration to use with a reader.
 func WithReaderConfig(cfg API) ReaderFunc {
 	return func(r *Reader) {
-		r.cfg = cfg.(*frozenConfig)
+		r.cfg = mustFrozenConfig(cfg)
 	}
 }
 
@@ -31,13 +33,19 @@ type Reader struct {
 	buf    []byte
 	head   int
 	tail   int
+	offset int64 // The number of bytes consumed before the current buffer.
 	Error  error
+
+	// raw holds the bytes captured by ReadRaw from previously loaded buffers.
+	raw       []byte
+	rawStart  int
+	capturing bool
 }
 
 // NewReader creates a new Reader.
 func NewReader(r io.Reader, bufSize int, opts ...ReaderFunc) *Reader {
 	reader := &Reader{
-		cfg:    DefaultConfig.(*frozenConfig),
+		cfg:    mustFrozenConfig(DefaultConfig),
 		reader: r,
 		buf:    make([]byte, bufSize),
 		head:   0,
@@ -52,21 +60,71 @@ func NewReader(r io.Reader, bufSize int, opts ...ReaderFunc) *Reader {
 }
 
 // Reset resets a Reader with a new byte array attached.
+// Any error is cleared.
 func (r *Reader) Reset(b []byte) *Reader {
 	r.reader = nil
 	r.buf = b
 	r.head = 0
 	r.tail = len(b)
+	r.offset = 0
+	r.Error = nil
+	return r
+}
+
+// ResetReader resets a Reader with a new io.Reader attached, reusing its buffer.
+// A Reader last reset with a byte array has no buffer of its own, and allocates one.
+// Any error is cleared.
+func (r *Reader) ResetReader(rd io.Reader) *Reader {
+	if r.reader == nil {
+		r.buf = make([]byte, 512)
+	}
+	r.reader = rd
+	r.head = 0
+	r.tail = 0
+	r.offset = 0
+	r.Error = nil
 	return r
 }
 
+// ReadError describes an error reading a value, as reported by ReportError.
+type ReadError struct {
+	// Op is the operation that failed (e.g. `ReadString` or `protobufCodec`).
+	Op string
+	// Path is the path of the value from the root schema, with record fields separated
+	// by dots (e.g. `author.name`). It is empty if it is not known.
+	Path string
+	// Offset is the number of bytes read before the error was reported.
+	Offset int64
+	// Err is the reason reading failed.
+	Err error
+}
+
+// Error returns the error message.
+func (e *ReadError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("avro: %s: %v", e.Op, e.Err)
+	}
+	return fmt.Sprintf("avro: %s: %s: %v", e.Op, e.Path, e.Err)
+}
+
+// Unwrap returns the reason reading failed.
+func (e *ReadError) Unwrap() error {
+	return e.Err
+}
+
 // ReportError record an error in iterator instance with current position.
 func (r *Reader) ReportError(operation, msg string) {
+	r.reportError(operation, "", errors.New(msg))
+}
+
+// reportError records a *ReadError for the value at path, unless the Reader
+// already has an error other than io.EOF.
+func (r *Reader) reportError(operation, path string, err error) {
 	if r.Error != nil && !errors.Is(r.Error, io.EOF) {
 		return
 	}
 
-	r.Error = fmt.Errorf("avro: %s: %s", operation, msg)
+	r.Error = &ReadError{Op: operation, Path: path, Offset: r.BytesRead(), Err: err}
 }
 
 func (r *Reader) loadMore() bool {
@@ -78,6 +136,11 @@ func (r *Reader) loadMore() bool {
 		return false
 	}
 
+	if r.capturing {
+		r.raw = append(r.raw, r.buf[r.rawStart:r.tail]...)
+		r.rawStart = 0
+	}
+
 	for {
 		n, err := r.reader.Read(r.buf)
 		if n == 0 {
@@ -90,6 +153,7 @@ func (r *Reader) loadMore() bool {
 			continue
 		}
 
+		r.offset += int64(r.tail)
 		r.head = 0
 		r.tail = n
 		return true
@@ -121,6 +185,72 @@ func (r *Reader) Peek() byte {
 	return r.buf[r.head]
 }
 
+// More reports whether more data is available, reading more of the input if the
+// buffer is empty. It returns false at the end of the input, which does not set the
+// Reader Error, or if the Reader has an error. This allows a stream of concatenated
+// values to be read until it is exhausted.
+func (r *Reader) More() bool {
+	if r.Error != nil {
+		return false
+	}
+	if r.head < r.tail {
+		return true
+	}
+	if r.reader == nil || !r.loadMore() {
+		if errors.Is(r.Error, io.EOF) {
+			r.Error = nil
+		}
+		return false
+	}
+	return true
+}
+
+// PeekN returns the next n bytes without advancing the Reader, reading more
+// of the input if needed. The bytes are only valid until the next read.
+// If fewer than n bytes remain, they are returned with the error that ended
+// the input, io.EOF when reading from a byte array. Unlike Peek, the Reader
+// Error is not set.
+func (r *Reader) PeekN(n int) ([]byte, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("avro: invalid peek length %d", n)
+	}
+	if r.tail-r.head >= n {
+		return r.buf[r.head : r.head+n], nil
+	}
+	if r.reader == nil {
+		return r.buf[r.head:r.tail], io.EOF
+	}
+
+	// Move the unread bytes to the start of the buffer to make room.
+	if r.capturing {
+		r.raw = append(r.raw, r.buf[r.rawStart:r.head]...)
+		r.rawStart = 0
+	}
+	buf := r.buf
+	if len(buf) < n {
+		buf = make([]byte, n)
+	}
+	r.offset += int64(r.head)
+	r.tail = copy(buf, r.buf[r.head:r.tail])
+	r.head = 0
+	r.buf = buf
+
+	for r.tail < n {
+		m, err := r.reader.Read(r.buf[r.tail:])
+		r.tail += m
+		if err != nil && r.tail < n {
+			return r.buf[:r.tail], err
+		}
+	}
+	return r.buf[:n], nil
+}
+
+// BytesRead returns the number of bytes consumed from the input since the
+// Reader was created or last reset.
+func (r *Reader) BytesRead() int64 {
+	return r.offset + int64(r.head)
+}
+
 // Read reads data into the given bytes.
 func (r *Reader) Read(b []byte) {
 	size := len(b)
@@ -140,6 +270,10 @@ func (r *Reader) Read(b []byte) {
 	}
 }
 
+// ReadNull reads a Null from the Reader. Null is encoded as zero bytes, so nothing
+// is read; it makes the layout of values read by hand explicit.
+func (r *Reader) ReadNull() {}
+
 // ReadBool reads a Bool from the Reader.
 func (r *Reader) ReadBool() bool {
 	b := r.readByte()
@@ -264,12 +398,18 @@ func (r *Reader) ReadDouble() float64 {
 
 // ReadBytes reads Bytes from the Reader.
 func (r *Reader) ReadBytes() []byte {
-	return r.readBytes("bytes")
+	return r.readBytes("bytes", false)
+}
+
+// readBytesNoCopy reads Bytes from the Reader. When the Reader is attached to a
+// byte slice, the returned bytes alias it instead of being copied.
+func (r *Reader) readBytesNoCopy() []byte {
+	return r.readBytes("bytes", r.reader == nil)
 }
 
 // ReadString reads a String from the Reader.
 func (r *Reader) ReadString() string {
-	b := r.readBytes("string")
+	b := r.readBytes("string", false)
 	if len(b) == 0 {
 		return ""
 	}
@@ -277,7 +417,7 @@ func (r *Reader) ReadString() string {
 	return *(*string)(unsafe.Pointer(&b))
 }
 
-func (r *Reader) readBytes(op string) []byte {
+func (r *Reader) readBytes(op string, alias bool) []byte {
 	size := int(r.ReadLong())
 	if size < 0 {
 		fnName := "Read" + strings.ToTitle(op)
@@ -293,6 +433,14 @@ func (r *Reader) readBytes(op string) []byte {
 		return nil
 	}
 
+	// The bytes are entirely in a buffer owned by the caller.
+	// Return a capped slice of it.
+	if alias && r.head+size <= r.tail {
+		dst := r.buf[r.head : r.head+size : r.head+size]
+		r.head += size
+		return dst
+	}
+
 	// The bytes are entirely in the buffer and of a reasonable size.
 	// Use the byte slab.
 	if r.head+size <= r.tail && size <= 1024 {
@@ -311,6 +459,50 @@ func (r *Reader) readBytes(op string) []byte {
 	return buf
 }
 
+// ReadDecimal reads a decimal from Avro bytes holding its unscaled value, as described by
+// the bytes decimal logical type with the given scale.
+func (r *Reader) ReadDecimal(scale int) *big.Rat {
+	return ratFromBytes(r.ReadBytes(), scale)
+}
+
+// ReadUnionIndex reads a union index from the Reader.
+func (r *Reader) ReadUnionIndex() int {
+	return int(r.ReadLong())
+}
+
+// ReadNullableIndex reads the union index of a nullable union whose null
+// type comes first, such as ["null", "string"], and reports if the value is null.
+// The value itself must be read by the caller when it is not null.
+func (r *Reader) ReadNullableIndex() bool {
+	switch idx := r.ReadLong(); idx {
+	case 0:
+		return true
+	case 1:
+		return false
+	default:
+		r.ReportError("ReadNullableIndex", "invalid nullable union index "+strconv.FormatInt(idx, 10))
+		return true
+	}
+}
+
+// ReadRaw reads the next value of the schema from the Reader, returning its
+// Avro encoding without decoding it.
+func (r *Reader) ReadRaw(schema Schema) []byte {
+	r.raw = nil
+	r.rawStart = r.head
+	r.capturing = true
+
+	createSkipDecoder(schema).Decode(nil, r)
+
+	r.capturing = false
+	raw := append(r.raw, r.buf[r.rawStart:r.head]...)
+	r.raw = nil
+	if r.Error != nil {
+		return nil
+	}
+	return raw
+}
+
 // ReadBlockHeader reads a Block Header from the Reader.
 func (r *Reader) ReadBlockHeader() (int64, int64) {
 	length := r.ReadLong()
@@ -322,3 +514,39 @@ func (r *Reader) ReadBlockHeader() (int64, int64) {
 
 	return length, 0
 }
+
+// ReadArrayCallback reads an array, calling fn to read each item. All the blocks
+// of the array are read, including blocks with a negative count, which are followed
+// by their size in bytes. Reading stops at the first error, either returned by fn or
+// set on the Reader, which is returned.
+func (r *Reader) ReadArrayCallback(fn func(*Reader) error) error {
+	for {
+		l, _ := r.ReadBlockHeader()
+		if r.Error != nil {
+			return r.Error
+		}
+		if l == 0 {
+			return nil
+		}
+		for range l {
+			if err := fn(r); err != nil {
+				return err
+			}
+			if r.Error != nil {
+				return r.Error
+			}
+		}
+	}
+}
+
+// ReadMapCallback reads a map, calling fn with each key to read its value. It
+// handles blocks and errors like ReadArrayCallback.
+func (r *Reader) ReadMapCallback(fn func(r *Reader, key string) error) error {
+	return r.ReadArrayCallback(func(r *Reader) error {
+		key := r.ReadString()
+		if r.Error != nil {
+			return r.Error
+		}
+		return fn(r, key)
+	})
+}
@@ -218,3 +218,18 @@ func TestReader_ReadNextUnsupportedType(t *testing.T) {
 
 	assert.Error(t, r.Error)
 }
+
+func TestReader_ReadNextLargerThanMaxByteSliceSize(t *testing.T) {
+	api := avro.Config{MaxByteSliceSize: 16}.Freeze()
+	// A length of 2^40, with no data following it.
+	data := []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x40}
+
+	for _, typ := range []avro.Type{avro.String, avro.Bytes} {
+		r := avro.NewReader(bytes.NewReader(data), 10, avro.WithReaderConfig(api))
+
+		got := r.ReadNext(avro.NewPrimitiveSchema(typ, nil))
+
+		assert.ErrorContains(t, r.Error, "size is greater than `Config.MaxByteSliceSize`")
+		assert.Empty(t, got)
+	}
+}
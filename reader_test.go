@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"errors"
 	"io"
+	"math/big"
 	"strconv"
 	"testing"
+	"testing/iotest"
 
 	"github.com/hamba/avro/v2"
 	"github.com/stretchr/testify/assert"
@@ -26,6 +28,39 @@ func TestReader_Reset(t *testing.T) {
 	assert.True(t, r.ReadBool())
 }
 
+func TestReader_ResetClearsError(t *testing.T) {
+	r := &avro.Reader{}
+	r.Error = errors.New("test")
+
+	r.Reset([]byte{0x01})
+
+	assert.NoError(t, r.Error)
+	assert.True(t, r.ReadBool())
+}
+
+func TestReader_ResetReader(t *testing.T) {
+	r := avro.NewReader(bytes.NewReader([]byte{0x36}), 10)
+	assert.Equal(t, int32(27), r.ReadInt())
+	_ = r.ReadInt()
+	require.Error(t, r.Error)
+
+	r.ResetReader(bytes.NewReader([]byte{0x1a}))
+
+	assert.NoError(t, r.Error)
+	assert.Equal(t, int32(13), r.ReadInt())
+}
+
+func TestReader_ResetReaderAfterReset(t *testing.T) {
+	data := []byte{0x36}
+	r := (&avro.Reader{}).Reset(data)
+
+	r.ResetReader(bytes.NewReader([]byte{0x1a, 0x02}))
+
+	assert.Equal(t, int32(13), r.ReadInt())
+	assert.Equal(t, int32(1), r.ReadInt())
+	assert.Equal(t, []byte{0x36}, data)
+}
+
 func TestReader_ReportError(t *testing.T) {
 	r := &avro.Reader{}
 
@@ -45,6 +80,20 @@ func TestReader_ReportErrorExistingError(t *testing.T) {
 	assert.Equal(t, err, r.Error)
 }
 
+func TestReader_ReportErrorReadError(t *testing.T) {
+	r := (&avro.Reader{}).Reset([]byte{0x02, 0x04})
+	r.ReadInt()
+
+	r.ReportError("test", "bar")
+
+	var readErr *avro.ReadError
+	require.ErrorAs(t, r.Error, &readErr)
+	assert.Equal(t, "test", readErr.Op)
+	assert.Equal(t, "", readErr.Path)
+	assert.Equal(t, int64(1), readErr.Offset)
+	assert.EqualError(t, readErr.Err, "bar")
+}
+
 func TestReader_Peek(t *testing.T) {
 	r := (&avro.Reader{}).Reset([]byte{0x36})
 
@@ -67,6 +116,155 @@ func TestReader_PeekNoData(t *testing.T) {
 	assert.ErrorIs(t, r.Error, io.EOF)
 }
 
+func TestReader_More(t *testing.T) {
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "test",
+		"fields": [
+			{"name": "a", "type": "long"},
+			{"name": "b", "type": "string"}
+		]
+	}`)
+	type record struct {
+		A int64  `avro:"a"`
+		B string `avro:"b"`
+	}
+	want := []record{{A: 1, B: "foo"}, {A: 2, B: "bar"}, {A: 3, B: "baz"}}
+
+	var data []byte
+	for _, v := range want {
+		b, err := avro.Marshal(schema, v)
+		require.NoError(t, err)
+		data = append(data, b...)
+	}
+
+	tests := []struct {
+		name string
+		r    *avro.Reader
+	}{
+		{name: "bytes", r: avro.NewReader(nil, 0).Reset(data)},
+		{name: "reader", r: avro.NewReader(iotest.OneByteReader(bytes.NewReader(data)), 2)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []record
+			for test.r.More() {
+				var v record
+				test.r.ReadVal(schema, &v)
+				require.NoError(t, test.r.Error)
+				got = append(got, v)
+			}
+
+			assert.NoError(t, test.r.Error)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestReader_MoreError(t *testing.T) {
+	r := avro.NewReader(iotest.ErrReader(errors.New("test")), 10)
+
+	assert.False(t, r.More())
+	assert.EqualError(t, r.Error, "test")
+}
+
+func TestReader_PeekN(t *testing.T) {
+	r := avro.NewReader(nil, 0).Reset([]byte{0x36, 0x06, 0x66, 0x6f, 0x6f})
+
+	b, err := r.PeekN(2)
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x36, 0x06}, b)
+	assert.Equal(t, int64(0), r.BytesRead())
+	assert.Equal(t, int32(27), r.ReadInt())
+	assert.Equal(t, "foo", r.ReadString())
+	assert.Equal(t, int64(5), r.BytesRead())
+}
+
+func TestReader_PeekNShort(t *testing.T) {
+	r := (&avro.Reader{}).Reset([]byte{0x36, 0x06})
+	_ = r.ReadInt()
+
+	b, err := r.PeekN(2)
+
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, []byte{0x06}, b)
+	assert.NoError(t, r.Error)
+}
+
+func TestReader_PeekNAcrossRefill(t *testing.T) {
+	data := []byte{0x02, 0x04, 0x06, 0x08, 0x0a, 0x0c, 0x0e}
+	r := avro.NewReader(bytes.NewReader(data), 4)
+
+	assert.Equal(t, int32(1), r.ReadInt())
+	assert.Equal(t, int32(2), r.ReadInt())
+	assert.Equal(t, int32(3), r.ReadInt())
+
+	// Only one byte is left in the buffer.
+	b, err := r.PeekN(3)
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x08, 0x0a, 0x0c}, b)
+	assert.Equal(t, int64(3), r.BytesRead())
+	for _, want := range []int32{4, 5, 6, 7} {
+		assert.Equal(t, want, r.ReadInt())
+	}
+	require.NoError(t, r.Error)
+	assert.Equal(t, int64(7), r.BytesRead())
+}
+
+func TestReader_PeekNLargerThanBuffer(t *testing.T) {
+	data := []byte{0x02, 0x04, 0x06, 0x08, 0x0a, 0x0c, 0x0e}
+	r := avro.NewReader(iotest.OneByteReader(bytes.NewReader(data)), 2)
+	_ = r.ReadInt()
+
+	b, err := r.PeekN(5)
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x04, 0x06, 0x08, 0x0a, 0x0c}, b)
+	for _, want := range []int32{2, 3, 4, 5, 6, 7} {
+		assert.Equal(t, want, r.ReadInt())
+	}
+	require.NoError(t, r.Error)
+	assert.Equal(t, int64(7), r.BytesRead())
+}
+
+func TestReader_PeekNPastEnd(t *testing.T) {
+	r := avro.NewReader(bytes.NewReader([]byte{0x02, 0x04, 0x06}), 2)
+	_ = r.ReadInt()
+
+	b, err := r.PeekN(3)
+
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, []byte{0x04, 0x06}, b)
+	assert.Equal(t, int32(2), r.ReadInt())
+	assert.Equal(t, int32(3), r.ReadInt())
+	assert.NoError(t, r.Error)
+}
+
+func TestReader_PeekNNegative(t *testing.T) {
+	r := avro.NewReader(bytes.NewReader([]byte{0x02, 0x04}), 2)
+
+	b, err := r.PeekN(-1)
+
+	assert.EqualError(t, err, "avro: invalid peek length -1")
+	assert.Nil(t, b)
+	assert.Equal(t, int32(1), r.ReadInt())
+	assert.NoError(t, r.Error)
+}
+
+func TestReader_BytesReadAfterReset(t *testing.T) {
+	r := avro.NewReader(iotest.OneByteReader(bytes.NewReader([]byte{0x02, 0x04})), 1)
+	_ = r.ReadInt()
+	_ = r.ReadInt()
+	require.Equal(t, int64(2), r.BytesRead())
+
+	r.Reset([]byte{0x02})
+
+	assert.Equal(t, int64(0), r.BytesRead())
+}
+
 func TestReader_ReadPastBuffer(t *testing.T) {
 	r := (&avro.Reader{}).Reset([]byte{0xE2})
 
@@ -708,6 +906,173 @@ func TestReader_ReadBlockHeader(t *testing.T) {
 	}
 }
 
+func TestReader_ReadArrayCallback(t *testing.T) {
+	// A block of 2 items, then a block of 1 item with its size in bytes.
+	data := []byte{0x04, 0x02, 0x04, 0x01, 0x02, 0x06, 0x00}
+	r := avro.NewReader(bytes.NewReader(data), 10)
+
+	var got []int32
+	err := r.ReadArrayCallback(func(r *avro.Reader) error {
+		got = append(got, r.ReadInt())
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int32{1, 2, 3}, got)
+}
+
+func TestReader_ReadArrayCallbackError(t *testing.T) {
+	data := []byte{0x04, 0x02, 0x04, 0x00}
+	r := avro.NewReader(bytes.NewReader(data), 10)
+
+	calls := 0
+	err := r.ReadArrayCallback(func(r *avro.Reader) error {
+		calls++
+		return errors.New("test")
+	})
+
+	assert.EqualError(t, err, "test")
+	assert.Equal(t, 1, calls)
+}
+
+func TestReader_ReadArrayCallbackShortData(t *testing.T) {
+	data := []byte{0x04, 0x02}
+	r := avro.NewReader(bytes.NewReader(data), 10)
+
+	err := r.ReadArrayCallback(func(r *avro.Reader) error {
+		r.ReadInt()
+		return nil
+	})
+
+	assert.Error(t, err)
+}
+
+func TestReader_ReadMapCallback(t *testing.T) {
+	data := []byte{0x03, 0x0c, 0x02, 0x61, 0x02, 0x02, 0x62, 0x04, 0x00}
+	r := avro.NewReader(bytes.NewReader(data), 10)
+
+	got := map[string]int32{}
+	err := r.ReadMapCallback(func(r *avro.Reader, key string) error {
+		got[key] = r.ReadInt()
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int32{"a": 1, "b": 2}, got)
+}
+
+func TestReader_ReadUnionIndex(t *testing.T) {
+	r := avro.NewReader(bytes.NewReader([]byte{0x04}), 10)
+
+	got := r.ReadUnionIndex()
+
+	require.NoError(t, r.Error)
+	assert.Equal(t, 2, got)
+}
+
+func TestReader_ReadNullableIndex(t *testing.T) {
+	r := avro.NewReader(bytes.NewReader([]byte{0x00, 0x02}), 10)
+
+	isNull := r.ReadNullableIndex()
+	require.NoError(t, r.Error)
+	assert.True(t, isNull)
+
+	isNull = r.ReadNullableIndex()
+	require.NoError(t, r.Error)
+	assert.False(t, isNull)
+}
+
+func TestReader_ReadNullableIndexInvalidIndex(t *testing.T) {
+	r := avro.NewReader(bytes.NewReader([]byte{0x04}), 10)
+
+	_ = r.ReadNullableIndex()
+
+	assert.Error(t, r.Error)
+}
+
+func TestReader_ReadNull(t *testing.T) {
+	r := (&avro.Reader{}).Reset([]byte{0x02})
+
+	r.ReadNull()
+
+	require.NoError(t, r.Error)
+	assert.Equal(t, int64(0), r.BytesRead())
+}
+
+func TestReader_ReadDecimal(t *testing.T) {
+	r := avro.NewReader(bytes.NewReader([]byte{0x02, 0x85, 0x04, 0x3a, 0x98}), 10)
+
+	got := r.ReadDecimal(2)
+	require.NoError(t, r.Error)
+	assert.Equal(t, big.NewRat(-123, 100), got)
+
+	got = r.ReadDecimal(4)
+	require.NoError(t, r.Error)
+	assert.Equal(t, big.NewRat(3, 2), got)
+}
+
+func TestReader_ReadDecimalMatchesCodec(t *testing.T) {
+	defer ConfigTeardown()
+
+	schema := avro.MustParse(`{"type": "bytes", "logicalType": "decimal", "precision": 6, "scale": 3}`)
+	w := avro.NewWriter(nil, 50)
+	w.WriteDecimal(big.NewRat(-98765, 1000), 3, 6)
+	require.NoError(t, w.Error)
+
+	var got *big.Rat
+	err := avro.Unmarshal(schema, w.Buffer(), &got)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewRat(-98765, 1000), got)
+
+	data, err := avro.Marshal(schema, big.NewRat(-98765, 1000))
+	require.NoError(t, err)
+	r := avro.NewReader(bytes.NewReader(data), 10)
+	assert.Equal(t, big.NewRat(-98765, 1000), r.ReadDecimal(3))
+}
+
+func TestReader_ReadRaw(t *testing.T) {
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "test",
+		"fields": [
+			{"name": "a", "type": "long"},
+			{"name": "b", "type": "string"}
+		]
+	}`)
+	data := []byte{0x36, 0x06, 0x66, 0x6f, 0x6f, 0x02, 0x02, 0x61}
+
+	r := (&avro.Reader{}).Reset(data)
+
+	got := r.ReadRaw(schema)
+	require.NoError(t, r.Error)
+	assert.Equal(t, []byte{0x36, 0x06, 0x66, 0x6f, 0x6f}, got)
+
+	got = r.ReadRaw(schema)
+	require.NoError(t, r.Error)
+	assert.Equal(t, []byte{0x02, 0x02, 0x61}, got)
+}
+
+func TestReader_ReadRawAcrossBufferBoundary(t *testing.T) {
+	schema := avro.MustParse(`{"type": "array", "items": "string"}`)
+	data := []byte{0x04, 0x06, 0x66, 0x6f, 0x6f, 0x06, 0x62, 0x61, 0x72, 0x00, 0x36}
+
+	r := avro.NewReader(bytes.NewReader(data), 3)
+
+	got := r.ReadRaw(schema)
+	require.NoError(t, r.Error)
+	assert.Equal(t, data[:10], got)
+	assert.Equal(t, int64(27), r.ReadLong())
+}
+
+func TestReader_ReadRawError(t *testing.T) {
+	r := (&avro.Reader{}).Reset([]byte{0x06, 0x66})
+
+	got := r.ReadRaw(avro.MustParse(`"string"`))
+
+	assert.Error(t, r.Error)
+	assert.Nil(t, got)
+}
+
 type delayedReader struct {
 	count int
 	b     []byte
@@ -0,0 +1,84 @@
+package avro
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CanonicalForm returns the Parsing Canonical Form of the schema, as defined by the Avro
+// specification, so that logically equal schemas have the same form. Unlike String, which
+// keeps logical types, only the attributes needed to read data are kept: docs, defaults,
+// aliases, logical types and properties are dropped, names are replaced by full names,
+// and named types are inlined on their first use only.
+func CanonicalForm(schema Schema) string {
+	var b strings.Builder
+	writeCanonicalForm(&b, schema, map[string]struct{}{})
+	return b.String()
+}
+
+func writeCanonicalForm(b *strings.Builder, schema Schema, seen map[string]struct{}) {
+	if ref, ok := schema.(*RefSchema); ok {
+		schema = ref.Schema()
+	}
+	if named, ok := schema.(NamedSchema); ok {
+		if _, ok = seen[named.FullName()]; ok {
+			b.WriteString(`"` + named.FullName() + `"`)
+			return
+		}
+		seen[named.FullName()] = struct{}{}
+	}
+
+	switch s := schema.(type) {
+	case *RecordSchema:
+		typ := "record"
+		if s.IsError() {
+			typ = "error"
+		}
+		b.WriteString(`{"name":"` + s.FullName() + `","type":"` + typ + `","fields":[`)
+		for i, f := range s.Fields() {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(`{"name":"` + f.Name() + `","type":`)
+			writeCanonicalForm(b, f.Type(), seen)
+			b.WriteByte('}')
+		}
+		b.WriteString(`]}`)
+
+	case *EnumSchema:
+		b.WriteString(`{"name":"` + s.FullName() + `","type":"enum","symbols":[`)
+		for i, sym := range s.Symbols() {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(`"` + sym + `"`)
+		}
+		b.WriteString(`]}`)
+
+	case *FixedSchema:
+		b.WriteString(`{"name":"` + s.FullName() + `","type":"fixed","size":` + strconv.Itoa(s.Size()) + `}`)
+
+	case *ArraySchema:
+		b.WriteString(`{"type":"array","items":`)
+		writeCanonicalForm(b, s.Items(), seen)
+		b.WriteByte('}')
+
+	case *MapSchema:
+		b.WriteString(`{"type":"map","values":`)
+		writeCanonicalForm(b, s.Values(), seen)
+		b.WriteByte('}')
+
+	case *UnionSchema:
+		b.WriteByte('[')
+		for i, t := range s.Types() {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeCanonicalForm(b, t, seen)
+		}
+		b.WriteByte(']')
+
+	default:
+		b.WriteString(`"` + string(schema.Type()) + `"`)
+	}
+}
@@ -278,3 +278,84 @@ func TestSchema_Canonical(t *testing.T) {
 		})
 	}
 }
+
+func TestCanonicalForm(t *testing.T) {
+	tests := []struct {
+		name      string
+		schemas   []string
+		canonical string
+	}{
+		{
+			name: "record",
+			schemas: []string{
+				`{
+					"type": "record",
+					"name": "Order",
+					"namespace": "org.hamba.avro",
+					"doc": "An order",
+					"aliases": ["Purchase"],
+					"fields": [
+						{"name": "id", "type": {"type": "long", "logicalType": "timestamp-millis"}, "doc": "The id"},
+						{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["OPEN", "CLOSED"], "default": "OPEN"}},
+						{"name": "note", "type": ["null", "string"], "default": null},
+						{"name": "previous", "type": "Status"}
+					]
+				}`,
+				`{"fields":[{"type":"long","name":"id"},{"name":"status","type":{"symbols":["OPEN","CLOSED"],"name":"org.hamba.avro.Status","type":"enum"}},{"type":["null",{"type":"string"}],"name":"note"},{"name":"previous","type":"org.hamba.avro.Status"}],"type":"record","name":"org.hamba.avro.Order"}`,
+			},
+			canonical: `{"name":"org.hamba.avro.Order","type":"record","fields":[{"name":"id","type":"long"},{"name":"status","type":{"name":"org.hamba.avro.Status","type":"enum","symbols":["OPEN","CLOSED"]}},{"name":"note","type":["null","string"]},{"name":"previous","type":"org.hamba.avro.Status"}]}`,
+		},
+		{
+			name: "fixed decimal",
+			schemas: []string{
+				`{"type": "fixed", "name": "Money", "namespace": "a", "size": 8, "logicalType": "decimal", "precision": 10, "scale": 2}`,
+				`{"size":8,"type":"fixed","name":"a.Money"}`,
+			},
+			canonical: `{"name":"a.Money","type":"fixed","size":8}`,
+		},
+		{
+			name: "collections",
+			schemas: []string{
+				`{"type": "map", "values": {"type": "array", "items": {"type": "bytes", "logicalType": "decimal", "precision": 4}}}`,
+				`{"values":{"items":"bytes","type":"array"},"type":"map"}`,
+			},
+			canonical: `{"type":"map","values":{"type":"array","items":"bytes"}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, schema := range test.schemas {
+				s, err := avro.ParseWithCache(schema, "", &avro.SchemaCache{})
+				require.NoError(t, err)
+
+				assert.Equal(t, test.canonical, avro.CanonicalForm(s))
+			}
+		})
+	}
+}
+
+func TestCanonicalForm_InlinesNamedTypesOnce(t *testing.T) {
+	point, err := avro.NewRecordSchema("Point", "geo", []*avro.Field{
+		mustNewField(t, "x", avro.NewPrimitiveSchema(avro.Int, nil)),
+	})
+	require.NoError(t, err)
+	line, err := avro.NewRecordSchema("Line", "geo", []*avro.Field{
+		mustNewField(t, "from", point),
+		mustNewField(t, "to", point),
+	})
+	require.NoError(t, err)
+
+	got := avro.CanonicalForm(line)
+
+	want := `{"name":"geo.Line","type":"record","fields":[{"name":"from","type":{"name":"geo.Point","type":"record","fields":[{"name":"x","type":"int"}]}},{"name":"to","type":"geo.Point"}]}`
+	assert.Equal(t, want, got)
+}
+
+func mustNewField(t *testing.T, name string, typ avro.Schema) *avro.Field {
+	t.Helper()
+
+	f, err := avro.NewField(name, typ)
+	require.NoError(t, err)
+	return f
+}
@@ -307,3 +307,25 @@ func TestSchema_JSON(t *testing.T) {
 		})
 	}
 }
+
+func TestSchema_MarshalJSONIndent(t *testing.T) {
+	schema := avro.MustParse(`{"type":"record","name":"X","fields":[{"name":"a","type":{"type":"array","items":"int"}}]}`)
+
+	b, err := json.MarshalIndent(schema, "", "  ")
+
+	require.NoError(t, err)
+	want := `{
+  "name": "X",
+  "type": "record",
+  "fields": [
+    {
+      "name": "a",
+      "type": {
+        "type": "array",
+        "items": "int"
+      }
+    }
+  ]
+}`
+	assert.Equal(t, want, string(b))
+}
@@ -1,6 +1,7 @@
 package avro_test
 
 import (
+	"encoding/binary"
 	"encoding/json"
 	"strings"
 	"sync"
@@ -1310,6 +1311,56 @@ func TestSchema_FingerprintUsing(t *testing.T) {
 	}
 }
 
+func TestSchema_FingerprintUsingSpecVectors(t *testing.T) {
+	// Vectors from the Avro specification test suite (share/test/data/schema-tests.txt),
+	// where the CRC-64-AVRO fingerprint is given as a signed 64-bit integer.
+	tests := []struct {
+		schema    string
+		canonical string
+		want      int64
+	}{
+		{schema: `"null"`, canonical: `"null"`, want: 7195948357588979594},
+		{schema: `{"type":"boolean"}`, canonical: `"boolean"`, want: -6970731678124411036},
+		{schema: `"int"`, canonical: `"int"`, want: 8247732601305521295},
+		{schema: `"long"`, canonical: `"long"`, want: -3434872931120570953},
+		{schema: `"float"`, canonical: `"float"`, want: 5583340709985441680},
+		{schema: `"double"`, canonical: `"double"`, want: -8181574048448539266},
+		{schema: `"bytes"`, canonical: `"bytes"`, want: 5746618253357095269},
+		{schema: `"string"`, canonical: `"string"`, want: -8142146995180207161},
+		{
+			schema:    `{"type":"fixed","name":"foo","size":15}`,
+			canonical: `{"name":"foo","type":"fixed","size":15}`,
+			want:      1756455273707447556,
+		},
+		{
+			schema:    `{"type":"enum","name":"foo","symbols":["A1"],"doc":"docs"}`,
+			canonical: `{"name":"foo","type":"enum","symbols":["A1"]}`,
+			want:      -6342190197741309591,
+		},
+		{
+			schema:    `{"type":"record","name":"foo","aliases":["bar"],"fields":[{"name":"f1","type":"boolean","default":true,"doc":"docs"}]}`,
+			canonical: `{"name":"foo","type":"record","fields":[{"name":"f1","type":"boolean"}]}`,
+			want:      7843277075252814651,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.canonical, func(t *testing.T) {
+			t.Parallel()
+
+			schema, err := avro.ParseWithCache(test.schema, "", &avro.SchemaCache{})
+			require.NoError(t, err)
+
+			got, err := schema.FingerprintUsing(avro.CRC64Avro)
+
+			require.NoError(t, err)
+			assert.Equal(t, test.canonical, schema.String())
+			assert.Equal(t, test.want, int64(binary.BigEndian.Uint64(got)))
+		})
+	}
+}
+
 func TestSchema_FingerprintUsingReference(t *testing.T) {
 	schema := avro.MustParse(`
 {
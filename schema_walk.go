@@ -1,5 +1,7 @@
 package avro
 
+import "strconv"
+
 func walkSchema(schema Schema, fn func(Schema) Schema) Schema {
 	schema = fn(schema)
 
@@ -19,3 +21,43 @@ func walkSchema(schema Schema, fn func(Schema) Schema) Schema {
 	}
 	return schema
 }
+
+// Walk traverses the schema depth first, calling visit for the schema and each schema nested
+// in it: record fields, union branches, array items and map values. The path is the dotted
+// path of field names from the root, which has an empty path, with "[]" appended for array
+// items, "{}" for map values and "[i]" for the i-th union branch (e.g. "user.tags[]" or
+// "user.address[1].city"). References to named types are visited but not descended into,
+// so recursive schemas terminate. Walking stops at the first error returned by visit.
+func Walk(schema Schema, visit func(path string, s Schema) error) error {
+	return walk("", schema, visit)
+}
+
+func walk(path string, schema Schema, visit func(path string, s Schema) error) error {
+	if err := visit(path, schema); err != nil {
+		return err
+	}
+
+	switch s := schema.(type) {
+	case *RecordSchema:
+		for _, f := range s.Fields() {
+			fieldPath := f.Name()
+			if path != "" {
+				fieldPath = path + "." + fieldPath
+			}
+			if err := walk(fieldPath, f.Type(), visit); err != nil {
+				return err
+			}
+		}
+	case *ArraySchema:
+		return walk(path+"[]", s.Items(), visit)
+	case *MapSchema:
+		return walk(path+"{}", s.Values(), visit)
+	case *UnionSchema:
+		for i, st := range s.Types() {
+			if err := walk(path+"["+strconv.Itoa(i)+"]", st, visit); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
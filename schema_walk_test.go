@@ -0,0 +1,104 @@
+package avro_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalk(t *testing.T) {
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "User",
+		"namespace": "org.example",
+		"fields": [
+			{"name": "id", "type": "long"},
+			{"name": "tags", "type": {"type": "array", "items": "string"}},
+			{"name": "address", "type": ["null", {
+				"type": "record",
+				"name": "Address",
+				"fields": [
+					{"name": "city", "type": "string"},
+					{"name": "lines", "type": {"type": "map", "values": {"type": "array", "items": "string"}}}
+				]
+			}]},
+			{"name": "previous", "type": ["null", "Address"]}
+		]
+	}`)
+
+	type visit struct {
+		path string
+		typ  avro.Type
+	}
+	var got []visit
+	err := avro.Walk(schema, func(path string, s avro.Schema) error {
+		got = append(got, visit{path: path, typ: s.Type()})
+		return nil
+	})
+
+	require.NoError(t, err)
+	want := []visit{
+		{path: "", typ: avro.Record},
+		{path: "id", typ: avro.Long},
+		{path: "tags", typ: avro.Array},
+		{path: "tags[]", typ: avro.String},
+		{path: "address", typ: avro.Union},
+		{path: "address[0]", typ: avro.Null},
+		{path: "address[1]", typ: avro.Record},
+		{path: "address[1].city", typ: avro.String},
+		{path: "address[1].lines", typ: avro.Map},
+		{path: "address[1].lines{}", typ: avro.Array},
+		{path: "address[1].lines{}[]", typ: avro.String},
+		{path: "previous", typ: avro.Union},
+		{path: "previous[0]", typ: avro.Null},
+		{path: "previous[1]", typ: avro.Ref},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestWalk_RecursiveSchema(t *testing.T) {
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "Node",
+		"fields": [
+			{"name": "children", "type": {"type": "array", "items": "Node"}}
+		]
+	}`)
+
+	var paths []string
+	err := avro.Walk(schema, func(path string, s avro.Schema) error {
+		paths = append(paths, path)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", "children", "children[]"}, paths)
+}
+
+func TestWalk_StopsOnError(t *testing.T) {
+	schema := avro.MustParse(`{
+		"type": "record",
+		"name": "User",
+		"fields": [
+			{"name": "id", "type": "long"},
+			{"name": "name", "type": "string"},
+			{"name": "email", "type": "string"}
+		]
+	}`)
+	errStop := errors.New("test")
+
+	var paths []string
+	err := avro.Walk(schema, func(path string, s avro.Schema) error {
+		paths = append(paths, path)
+		if path == "name" {
+			return errStop
+		}
+		return nil
+	})
+
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, []string{"", "id", "name"}, paths)
+}
@@ -0,0 +1,116 @@
+package soe
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/hamba/avro/v2"
+)
+
+// Encoder writes SOE-framed Avro values to an output stream.
+type Encoder struct {
+	schema avro.Schema
+	header []byte
+	w      *avro.Writer
+}
+
+// NewEncoder returns a new Encoder that writes to w using schema and the
+// default config.
+func NewEncoder(schema avro.Schema, w io.Writer) (*Encoder, error) {
+	return NewEncoderWithAPI(schema, w, avro.DefaultConfig)
+}
+
+// NewEncoderWithAPI returns a new Encoder that writes to w using schema and
+// an API.
+func NewEncoderWithAPI(schema avro.Schema, w io.Writer, api avro.API) (*Encoder, error) {
+	header, err := BuildHeader(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Encoder{
+		schema: schema,
+		header: header,
+		w:      avro.NewWriter(w, 512, avro.WithWriterConfig(api)),
+	}, nil
+}
+
+// Encode writes the SOE header followed by the Avro encoding of v to the
+// stream.
+func (e *Encoder) Encode(v any) error {
+	_, _ = e.w.Write(e.header)
+	e.w.WriteVal(e.schema, v)
+	_ = e.w.Flush()
+	return e.w.Error
+}
+
+// Decoder reads SOE-framed Avro values from an input stream.
+type Decoder struct {
+	schema      avro.Schema
+	expected    []byte
+	fingerprint []byte
+	r           *avro.Reader
+}
+
+// NewDecoder returns a new Decoder that reads from r using schema and the
+// default config.
+func NewDecoder(schema avro.Schema, r io.Reader) (*Decoder, error) {
+	return NewDecoderWithAPI(schema, r, avro.DefaultConfig)
+}
+
+// NewDecoderWithAPI returns a new Decoder that reads from r using schema and
+// an API.
+func NewDecoderWithAPI(schema avro.Schema, r io.Reader, api avro.API) (*Decoder, error) {
+	expected, err := ComputeFingerprint(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Decoder{
+		schema:   schema,
+		expected: expected,
+		r:        avro.NewReader(r, 1024, avro.WithReaderConfig(api)),
+	}, nil
+}
+
+// Decode reads the next SOE-framed value from its input and stores it in the
+// value pointed to by v. It fails if the schema fingerprint doesn't match the
+// held schema, after which the stream cannot be read further. It returns
+// io.EOF when there are no more values.
+func (d *Decoder) Decode(v any) error {
+	_ = d.r.Peek()
+	if d.r.Error != nil {
+		return d.r.Error
+	}
+
+	var header [10]byte
+	d.r.Read(header[:])
+	if d.r.Error != nil {
+		return d.r.Error
+	}
+
+	fingerprint, _, err := ParseHeader(header[:])
+	if err != nil {
+		d.r.Error = err
+		return err
+	}
+	d.fingerprint = fingerprint
+	if !bytes.Equal(fingerprint, d.expected) {
+		d.r.Error = fmt.Errorf("bad fingerprint %x, expected %x", fingerprint, d.expected)
+		return d.r.Error
+	}
+
+	d.r.ReadVal(d.schema, v)
+
+	//nolint:errorlint // Only direct EOF errors should be discarded.
+	if d.r.Error == io.EOF {
+		return nil
+	}
+	return d.r.Error
+}
+
+// Fingerprint returns the schema fingerprint of the last value read.
+func (d *Decoder) Fingerprint() []byte {
+	return d.fingerprint
+}
@@ -0,0 +1,130 @@
+package soe_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/soe"
+	"github.com/hamba/avro/v2/soe/internal/testdata"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncoder_ByteLayout(t *testing.T) {
+	fingerprint, err := testdata.StringIntSchema.FingerprintUsing(avro.CRC64AvroLE)
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	enc, err := soe.NewEncoder(testdata.StringIntSchema, buf)
+	require.NoError(t, err)
+
+	err = enc.Encode(testdata.StringInt{StringVal: "abc", IntVal: 123})
+	require.NoError(t, err)
+
+	// Magic, then the little-endian CRC-64-AVRO fingerprint, then the body.
+	want := []byte{0xc3, 0x01}
+	want = append(want, fingerprint...)
+	want = append(want, 0x06, 'a', 'b', 'c', 0xf6, 0x01)
+	require.Equal(t, want, buf.Bytes())
+}
+
+func TestDecoder_ByteLayout(t *testing.T) {
+	fingerprint, err := testdata.StringIntSchema.FingerprintUsing(avro.CRC64AvroLE)
+	require.NoError(t, err)
+
+	data := []byte{0xc3, 0x01}
+	data = append(data, fingerprint...)
+	data = append(data, 0x06, 'a', 'b', 'c', 0xf6, 0x01)
+
+	dec, err := soe.NewDecoder(testdata.StringIntSchema, bytes.NewReader(data))
+	require.NoError(t, err)
+
+	var v testdata.StringInt
+	err = dec.Decode(&v)
+
+	require.NoError(t, err)
+	require.Equal(t, testdata.StringInt{StringVal: "abc", IntVal: 123}, v)
+	require.Equal(t, fingerprint, dec.Fingerprint())
+}
+
+func TestEncoderDecoder_Roundtrip(t *testing.T) {
+	values := []testdata.StringInt{
+		{StringVal: "abc", IntVal: 123},
+		{StringVal: "def", IntVal: 456},
+	}
+
+	buf := &bytes.Buffer{}
+	enc, err := soe.NewEncoder(testdata.StringIntSchema, buf)
+	require.NoError(t, err)
+	for _, v := range values {
+		err = enc.Encode(v)
+		require.NoError(t, err)
+	}
+
+	dec, err := soe.NewDecoder(testdata.StringIntSchema, buf)
+	require.NoError(t, err)
+
+	var got []testdata.StringInt
+	for {
+		var v testdata.StringInt
+		err = dec.Decode(&v)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		got = append(got, v)
+	}
+
+	require.Equal(t, values, got)
+}
+
+func TestDecoder_BadMagic(t *testing.T) {
+	data := []byte{
+		// Invalid magic
+		0x00, 0x00,
+		// Faux schema ID
+		0x05, 0x05, 0x05, 0x05, 0x05, 0x05, 0x05, 0x05,
+	}
+
+	dec, err := soe.NewDecoder(testdata.StringIntSchema, bytes.NewReader(data))
+	require.NoError(t, err)
+
+	var v testdata.StringInt
+	err = dec.Decode(&v)
+
+	require.ErrorContains(t, err, "invalid magic")
+}
+
+func TestDecoder_BadFingerprint(t *testing.T) {
+	data := []byte{
+		// Good magic
+		0xc3, 0x01,
+		// Faux schema ID
+		0x05, 0x05, 0x05, 0x05, 0x05, 0x05, 0x05, 0x05,
+		// Payload
+		0x06, 'a', 'b', 'c', 0xf6, 0x01,
+	}
+
+	dec, err := soe.NewDecoder(testdata.StringIntSchema, bytes.NewReader(data))
+	require.NoError(t, err)
+
+	var v testdata.StringInt
+	err = dec.Decode(&v)
+
+	require.ErrorContains(t, err, "bad fingerprint")
+	require.Equal(t, data[2:10], dec.Fingerprint())
+}
+
+func TestDecoder_ShortHeader(t *testing.T) {
+	data := []byte{0xc3, 0x01, 0x05}
+
+	dec, err := soe.NewDecoder(testdata.StringIntSchema, bytes.NewReader(data))
+	require.NoError(t, err)
+
+	var v testdata.StringInt
+	err = dec.Decode(&v)
+
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
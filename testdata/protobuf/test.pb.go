@@ -1,14 +1,18 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.36.9
+// 	protoc-gen-go v1.36.10
 // 	protoc        v6.32.1
 // source: test.proto
 
 package testpb
 
 import (
+	_ "github.com/hamba/avro/v2/avropb"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	anypb "google.golang.org/protobuf/types/known/anypb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -868,12 +872,1394 @@ func (*OneofWithMessageMessage_User) isOneofWithMessageMessage_Data() {}
 
 func (*OneofWithMessageMessage_Profile) isOneofWithMessageMessage_Data() {}
 
+// EnumMapMessage contains a map with enum values
+type EnumMapMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Statuses      map[string]Status      `protobuf:"bytes,2,rep,name=statuses,proto3" json:"statuses,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value,enum=testpb.Status"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnumMapMessage) Reset() {
+	*x = EnumMapMessage{}
+	mi := &file_test_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnumMapMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnumMapMessage) ProtoMessage() {}
+
+func (x *EnumMapMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnumMapMessage.ProtoReflect.Descriptor instead.
+func (*EnumMapMessage) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *EnumMapMessage) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *EnumMapMessage) GetStatuses() map[string]Status {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+// IntMapMessage contains maps with non-string keys
+type IntMapMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Counts        map[int64]int32        `protobuf:"bytes,2,rep,name=counts,proto3" json:"counts,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Names         map[int32]string       `protobuf:"bytes,3,rep,name=names,proto3" json:"names,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Codes         map[uint32]string      `protobuf:"bytes,4,rep,name=codes,proto3" json:"codes,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Flags         map[bool]string        `protobuf:"bytes,5,rep,name=flags,proto3" json:"flags,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IntMapMessage) Reset() {
+	*x = IntMapMessage{}
+	mi := &file_test_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IntMapMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IntMapMessage) ProtoMessage() {}
+
+func (x *IntMapMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IntMapMessage.ProtoReflect.Descriptor instead.
+func (*IntMapMessage) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *IntMapMessage) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *IntMapMessage) GetCounts() map[int64]int32 {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+func (x *IntMapMessage) GetNames() map[int32]string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+func (x *IntMapMessage) GetCodes() map[uint32]string {
+	if x != nil {
+		return x.Codes
+	}
+	return nil
+}
+
+func (x *IntMapMessage) GetFlags() map[bool]string {
+	if x != nil {
+		return x.Flags
+	}
+	return nil
+}
+
+// TreeNode is a self-referential message
+type TreeNode struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         int32                  `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	Children      []*TreeNode            `protobuf:"bytes,2,rep,name=children,proto3" json:"children,omitempty"`
+	Left          *TreeNode              `protobuf:"bytes,3,opt,name=left,proto3" json:"left,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TreeNode) Reset() {
+	*x = TreeNode{}
+	mi := &file_test_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TreeNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TreeNode) ProtoMessage() {}
+
+func (x *TreeNode) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TreeNode.ProtoReflect.Descriptor instead.
+func (*TreeNode) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *TreeNode) GetValue() int32 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *TreeNode) GetChildren() []*TreeNode {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+func (x *TreeNode) GetLeft() *TreeNode {
+	if x != nil {
+		return x.Left
+	}
+	return nil
+}
+
+// IdentifierMessage contains fixed-size bytes fields
+type IdentifierMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Uuid  []byte                 `protobuf:"bytes,2,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	// Types that are valid to be assigned to Key:
+	//
+	//	*IdentifierMessage_Name
+	//	*IdentifierMessage_Hash
+	Key           isIdentifierMessage_Key `protobuf_oneof:"key"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IdentifierMessage) Reset() {
+	*x = IdentifierMessage{}
+	mi := &file_test_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IdentifierMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IdentifierMessage) ProtoMessage() {}
+
+func (x *IdentifierMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IdentifierMessage.ProtoReflect.Descriptor instead.
+func (*IdentifierMessage) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *IdentifierMessage) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *IdentifierMessage) GetUuid() []byte {
+	if x != nil {
+		return x.Uuid
+	}
+	return nil
+}
+
+func (x *IdentifierMessage) GetKey() isIdentifierMessage_Key {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *IdentifierMessage) GetName() string {
+	if x != nil {
+		if x, ok := x.Key.(*IdentifierMessage_Name); ok {
+			return x.Name
+		}
+	}
+	return ""
+}
+
+func (x *IdentifierMessage) GetHash() []byte {
+	if x != nil {
+		if x, ok := x.Key.(*IdentifierMessage_Hash); ok {
+			return x.Hash
+		}
+	}
+	return nil
+}
+
+type isIdentifierMessage_Key interface {
+	isIdentifierMessage_Key()
+}
+
+type IdentifierMessage_Name struct {
+	Name string `protobuf:"bytes,3,opt,name=name,proto3,oneof"`
+}
+
+type IdentifierMessage_Hash struct {
+	Hash []byte `protobuf:"bytes,4,opt,name=hash,proto3,oneof"`
+}
+
+func (*IdentifierMessage_Name) isIdentifierMessage_Key() {}
+
+func (*IdentifierMessage_Hash) isIdentifierMessage_Key() {}
+
+// EventMessage contains timestamp fields
+type EventMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LocalMillis   int64                  `protobuf:"varint,3,opt,name=local_millis,json=localMillis,proto3" json:"local_millis,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventMessage) Reset() {
+	*x = EventMessage{}
+	mi := &file_test_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventMessage) ProtoMessage() {}
+
+func (x *EventMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventMessage.ProtoReflect.Descriptor instead.
+func (*EventMessage) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *EventMessage) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *EventMessage) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *EventMessage) GetLocalMillis() int64 {
+	if x != nil {
+		return x.LocalMillis
+	}
+	return 0
+}
+
+func (x *EventMessage) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// LineItem is a record nested in GroupedItemsMessage lists
+type LineItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sku           string                 `protobuf:"bytes,1,opt,name=sku,proto3" json:"sku,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LineItem) Reset() {
+	*x = LineItem{}
+	mi := &file_test_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LineItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LineItem) ProtoMessage() {}
+
+func (x *LineItem) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LineItem.ProtoReflect.Descriptor instead.
+func (*LineItem) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *LineItem) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *LineItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+// LineItemList wraps a list of line items
+type LineItemList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*LineItem            `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LineItemList) Reset() {
+	*x = LineItemList{}
+	mi := &file_test_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LineItemList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LineItemList) ProtoMessage() {}
+
+func (x *LineItemList) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LineItemList.ProtoReflect.Descriptor instead.
+func (*LineItemList) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *LineItemList) GetItems() []*LineItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// GroupedItemsMessage contains a map of wrapped lists of messages
+type GroupedItemsMessage struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Id            int32                    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Groups        map[string]*LineItemList `protobuf:"bytes,2,rep,name=groups,proto3" json:"groups,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GroupedItemsMessage) Reset() {
+	*x = GroupedItemsMessage{}
+	mi := &file_test_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupedItemsMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupedItemsMessage) ProtoMessage() {}
+
+func (x *GroupedItemsMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupedItemsMessage.ProtoReflect.Descriptor instead.
+func (*GroupedItemsMessage) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GroupedItemsMessage) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GroupedItemsMessage) GetGroups() map[string]*LineItemList {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+// PriceMessage contains decimal values
+type PriceMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Amount        string                 `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	RawAmount     []byte                 `protobuf:"bytes,3,opt,name=raw_amount,json=rawAmount,proto3" json:"raw_amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceMessage) Reset() {
+	*x = PriceMessage{}
+	mi := &file_test_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceMessage) ProtoMessage() {}
+
+func (x *PriceMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceMessage.ProtoReflect.Descriptor instead.
+func (*PriceMessage) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PriceMessage) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PriceMessage) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *PriceMessage) GetRawAmount() []byte {
+	if x != nil {
+		return x.RawAmount
+	}
+	return nil
+}
+
+// LinkMessage contains a oneof with members of the same type
+type LinkMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Types that are valid to be assigned to Target:
+	//
+	//	*LinkMessage_Text
+	//	*LinkMessage_Url
+	Target        isLinkMessage_Target `protobuf_oneof:"target"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkMessage) Reset() {
+	*x = LinkMessage{}
+	mi := &file_test_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkMessage) ProtoMessage() {}
+
+func (x *LinkMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkMessage.ProtoReflect.Descriptor instead.
+func (*LinkMessage) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *LinkMessage) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *LinkMessage) GetTarget() isLinkMessage_Target {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *LinkMessage) GetText() string {
+	if x != nil {
+		if x, ok := x.Target.(*LinkMessage_Text); ok {
+			return x.Text
+		}
+	}
+	return ""
+}
+
+func (x *LinkMessage) GetUrl() string {
+	if x != nil {
+		if x, ok := x.Target.(*LinkMessage_Url); ok {
+			return x.Url
+		}
+	}
+	return ""
+}
+
+type isLinkMessage_Target interface {
+	isLinkMessage_Target()
+}
+
+type LinkMessage_Text struct {
+	Text string `protobuf:"bytes,2,opt,name=text,proto3,oneof"`
+}
+
+type LinkMessage_Url struct {
+	Url string `protobuf:"bytes,3,opt,name=url,proto3,oneof"`
+}
+
+func (*LinkMessage_Text) isLinkMessage_Target() {}
+
+func (*LinkMessage_Url) isLinkMessage_Target() {}
+
+// AnyMessage contains a field holding any message
+type AnyMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Payload       *anypb.Any             `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnyMessage) Reset() {
+	*x = AnyMessage{}
+	mi := &file_test_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnyMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnyMessage) ProtoMessage() {}
+
+func (x *AnyMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnyMessage.ProtoReflect.Descriptor instead.
+func (*AnyMessage) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *AnyMessage) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AnyMessage) GetPayload() *anypb.Any {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+// DeepMessage contains messages nested five levels deep
+type DeepMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Child         *DeepLevel2            `protobuf:"bytes,2,opt,name=child,proto3" json:"child,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeepMessage) Reset() {
+	*x = DeepMessage{}
+	mi := &file_test_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeepMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeepMessage) ProtoMessage() {}
+
+func (x *DeepMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeepMessage.ProtoReflect.Descriptor instead.
+func (*DeepMessage) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *DeepMessage) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeepMessage) GetChild() *DeepLevel2 {
+	if x != nil {
+		return x.Child
+	}
+	return nil
+}
+
+// DeepLevel2 is the second level of DeepMessage
+type DeepLevel2 struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Child         *DeepLevel3            `protobuf:"bytes,2,opt,name=child,proto3" json:"child,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeepLevel2) Reset() {
+	*x = DeepLevel2{}
+	mi := &file_test_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeepLevel2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeepLevel2) ProtoMessage() {}
+
+func (x *DeepLevel2) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeepLevel2.ProtoReflect.Descriptor instead.
+func (*DeepLevel2) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *DeepLevel2) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeepLevel2) GetChild() *DeepLevel3 {
+	if x != nil {
+		return x.Child
+	}
+	return nil
+}
+
+// DeepLevel3 is the third level of DeepMessage
+type DeepLevel3 struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Child         *DeepLevel4            `protobuf:"bytes,2,opt,name=child,proto3" json:"child,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeepLevel3) Reset() {
+	*x = DeepLevel3{}
+	mi := &file_test_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeepLevel3) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeepLevel3) ProtoMessage() {}
+
+func (x *DeepLevel3) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeepLevel3.ProtoReflect.Descriptor instead.
+func (*DeepLevel3) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *DeepLevel3) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeepLevel3) GetChild() *DeepLevel4 {
+	if x != nil {
+		return x.Child
+	}
+	return nil
+}
+
+// DeepLevel4 is the fourth level of DeepMessage
+type DeepLevel4 struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Child         *DeepLevel5            `protobuf:"bytes,2,opt,name=child,proto3" json:"child,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeepLevel4) Reset() {
+	*x = DeepLevel4{}
+	mi := &file_test_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeepLevel4) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeepLevel4) ProtoMessage() {}
+
+func (x *DeepLevel4) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeepLevel4.ProtoReflect.Descriptor instead.
+func (*DeepLevel4) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *DeepLevel4) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeepLevel4) GetChild() *DeepLevel5 {
+	if x != nil {
+		return x.Child
+	}
+	return nil
+}
+
+// DeepLevel5 is the fifth level of DeepMessage
+type DeepLevel5 struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeepLevel5) Reset() {
+	*x = DeepLevel5{}
+	mi := &file_test_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeepLevel5) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeepLevel5) ProtoMessage() {}
+
+func (x *DeepLevel5) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeepLevel5.ProtoReflect.Descriptor instead.
+func (*DeepLevel5) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *DeepLevel5) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeepLevel5) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// OptionalAuthorMessage contains an optional message field
+type OptionalAuthorMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Author        *BasicMessage          `protobuf:"bytes,2,opt,name=author,proto3,oneof" json:"author,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OptionalAuthorMessage) Reset() {
+	*x = OptionalAuthorMessage{}
+	mi := &file_test_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OptionalAuthorMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OptionalAuthorMessage) ProtoMessage() {}
+
+func (x *OptionalAuthorMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OptionalAuthorMessage.ProtoReflect.Descriptor instead.
+func (*OptionalAuthorMessage) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *OptionalAuthorMessage) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *OptionalAuthorMessage) GetAuthor() *BasicMessage {
+	if x != nil {
+		return x.Author
+	}
+	return nil
+}
+
+// ScalarValue holds one of several scalar values, like google.protobuf.Value
+type ScalarValue struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Kind:
+	//
+	//	*ScalarValue_StringValue
+	//	*ScalarValue_IntValue
+	Kind          isScalarValue_Kind `protobuf_oneof:"kind"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScalarValue) Reset() {
+	*x = ScalarValue{}
+	mi := &file_test_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScalarValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScalarValue) ProtoMessage() {}
+
+func (x *ScalarValue) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScalarValue.ProtoReflect.Descriptor instead.
+func (*ScalarValue) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ScalarValue) GetKind() isScalarValue_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return nil
+}
+
+func (x *ScalarValue) GetStringValue() string {
+	if x != nil {
+		if x, ok := x.Kind.(*ScalarValue_StringValue); ok {
+			return x.StringValue
+		}
+	}
+	return ""
+}
+
+func (x *ScalarValue) GetIntValue() int32 {
+	if x != nil {
+		if x, ok := x.Kind.(*ScalarValue_IntValue); ok {
+			return x.IntValue
+		}
+	}
+	return 0
+}
+
+type isScalarValue_Kind interface {
+	isScalarValue_Kind()
+}
+
+type ScalarValue_StringValue struct {
+	StringValue string `protobuf:"bytes,1,opt,name=string_value,json=stringValue,proto3,oneof"`
+}
+
+type ScalarValue_IntValue struct {
+	IntValue int32 `protobuf:"varint,2,opt,name=int_value,json=intValue,proto3,oneof"`
+}
+
+func (*ScalarValue_StringValue) isScalarValue_Kind() {}
+
+func (*ScalarValue_IntValue) isScalarValue_Kind() {}
+
+// ScalarValueList contains repeated and map fields of scalar values
+type ScalarValueList struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Values        []*ScalarValue          `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	Attributes    map[string]*ScalarValue `protobuf:"bytes,2,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Names         []string                `protobuf:"bytes,3,rep,name=names,proto3" json:"names,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScalarValueList) Reset() {
+	*x = ScalarValueList{}
+	mi := &file_test_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScalarValueList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScalarValueList) ProtoMessage() {}
+
+func (x *ScalarValueList) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScalarValueList.ProtoReflect.Descriptor instead.
+func (*ScalarValueList) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ScalarValueList) GetValues() []*ScalarValue {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *ScalarValueList) GetAttributes() map[string]*ScalarValue {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *ScalarValueList) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+// IgnoredFieldMessage contains a field excluded from Avro
+type IgnoredFieldMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Secret        string                 `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IgnoredFieldMessage) Reset() {
+	*x = IgnoredFieldMessage{}
+	mi := &file_test_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IgnoredFieldMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IgnoredFieldMessage) ProtoMessage() {}
+
+func (x *IgnoredFieldMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IgnoredFieldMessage.ProtoReflect.Descriptor instead.
+func (*IgnoredFieldMessage) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *IgnoredFieldMessage) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *IgnoredFieldMessage) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+// StructMessage contains dynamic JSON-like fields
+type StructMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Attributes    *structpb.Struct       `protobuf:"bytes,2,opt,name=attributes,proto3" json:"attributes,omitempty"`
+	Value         *structpb.Value        `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Tags          *structpb.ListValue    `protobuf:"bytes,4,opt,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StructMessage) Reset() {
+	*x = StructMessage{}
+	mi := &file_test_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StructMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StructMessage) ProtoMessage() {}
+
+func (x *StructMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StructMessage.ProtoReflect.Descriptor instead.
+func (*StructMessage) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *StructMessage) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *StructMessage) GetAttributes() *structpb.Struct {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *StructMessage) GetValue() *structpb.Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *StructMessage) GetTags() *structpb.ListValue {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+// CounterMessage contains a oneof with a 64-bit integer member
+type CounterMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Value:
+	//
+	//	*CounterMessage_Count
+	//	*CounterMessage_Unset
+	Value         isCounterMessage_Value `protobuf_oneof:"value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CounterMessage) Reset() {
+	*x = CounterMessage{}
+	mi := &file_test_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CounterMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CounterMessage) ProtoMessage() {}
+
+func (x *CounterMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CounterMessage.ProtoReflect.Descriptor instead.
+func (*CounterMessage) Descriptor() ([]byte, []int) {
+	return file_test_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *CounterMessage) GetValue() isCounterMessage_Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *CounterMessage) GetCount() int64 {
+	if x != nil {
+		if x, ok := x.Value.(*CounterMessage_Count); ok {
+			return x.Count
+		}
+	}
+	return 0
+}
+
+func (x *CounterMessage) GetUnset() bool {
+	if x != nil {
+		if x, ok := x.Value.(*CounterMessage_Unset); ok {
+			return x.Unset
+		}
+	}
+	return false
+}
+
+type isCounterMessage_Value interface {
+	isCounterMessage_Value()
+}
+
+type CounterMessage_Count struct {
+	Count int64 `protobuf:"varint,1,opt,name=count,proto3,oneof"`
+}
+
+type CounterMessage_Unset struct {
+	Unset bool `protobuf:"varint,2,opt,name=unset,proto3,oneof"`
+}
+
+func (*CounterMessage_Count) isCounterMessage_Value() {}
+
+func (*CounterMessage_Unset) isCounterMessage_Value() {}
+
 var File_test_proto protoreflect.FileDescriptor
 
 const file_test_proto_rawDesc = "" +
 	"\n" +
 	"\n" +
-	"test.proto\x12\x06testpb\"`\n" +
+	"test.proto\x12\x06testpb\x1a\x19google/protobuf/any.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x14avropb/options.proto\"`\n" +
 	"\fBasicMessage\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
@@ -944,7 +2330,126 @@ const file_test_proto_rawDesc = "" +
 	"\vdescription\x18\x02 \x01(\tH\x00R\vdescription\x12*\n" +
 	"\x04user\x18\x03 \x01(\v2\x14.testpb.BasicMessageH\x00R\x04user\x121\n" +
 	"\aprofile\x18\x04 \x01(\v2\x15.testpb.SimpleProfileH\x00R\aprofileB\x06\n" +
-	"\x04data*H\n" +
+	"\x04data\"\xaf\x01\n" +
+	"\x0eEnumMapMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12@\n" +
+	"\bstatuses\x18\x02 \x03(\v2$.testpb.EnumMapMessage.StatusesEntryR\bstatuses\x1aK\n" +
+	"\rStatusesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12$\n" +
+	"\x05value\x18\x02 \x01(\x0e2\x0e.testpb.StatusR\x05value:\x028\x01\"\xeb\x03\n" +
+	"\rIntMapMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x129\n" +
+	"\x06counts\x18\x02 \x03(\v2!.testpb.IntMapMessage.CountsEntryR\x06counts\x126\n" +
+	"\x05names\x18\x03 \x03(\v2 .testpb.IntMapMessage.NamesEntryR\x05names\x126\n" +
+	"\x05codes\x18\x04 \x03(\v2 .testpb.IntMapMessage.CodesEntryR\x05codes\x126\n" +
+	"\x05flags\x18\x05 \x03(\v2 .testpb.IntMapMessage.FlagsEntryR\x05flags\x1a9\n" +
+	"\vCountsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x03R\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1a8\n" +
+	"\n" +
+	"NamesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x05R\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a8\n" +
+	"\n" +
+	"CodesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\rR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a8\n" +
+	"\n" +
+	"FlagsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\bR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"t\n" +
+	"\bTreeNode\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\x05R\x05value\x12,\n" +
+	"\bchildren\x18\x02 \x03(\v2\x10.testpb.TreeNodeR\bchildren\x12$\n" +
+	"\x04left\x18\x03 \x01(\v2\x10.testpb.TreeNodeR\x04left\"j\n" +
+	"\x11IdentifierMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04uuid\x18\x02 \x01(\fR\x04uuid\x12\x14\n" +
+	"\x04name\x18\x03 \x01(\tH\x00R\x04name\x12\x14\n" +
+	"\x04hash\x18\x04 \x01(\fH\x00R\x04hashB\x05\n" +
+	"\x03key\"\xb7\x01\n" +
+	"\fEventMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x129\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12!\n" +
+	"\flocal_millis\x18\x03 \x01(\x03R\vlocalMillis\x129\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"8\n" +
+	"\bLineItem\x12\x10\n" +
+	"\x03sku\x18\x01 \x01(\tR\x03sku\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\"6\n" +
+	"\fLineItemList\x12&\n" +
+	"\x05items\x18\x01 \x03(\v2\x10.testpb.LineItemR\x05items\"\xb7\x01\n" +
+	"\x13GroupedItemsMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12?\n" +
+	"\x06groups\x18\x02 \x03(\v2'.testpb.GroupedItemsMessage.GroupsEntryR\x06groups\x1aO\n" +
+	"\vGroupsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12*\n" +
+	"\x05value\x18\x02 \x01(\v2\x14.testpb.LineItemListR\x05value:\x028\x01\"U\n" +
+	"\fPriceMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\tR\x06amount\x12\x1d\n" +
+	"\n" +
+	"raw_amount\x18\x03 \x01(\fR\trawAmount\"Q\n" +
+	"\vLinkMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
+	"\x04text\x18\x02 \x01(\tH\x00R\x04text\x12\x12\n" +
+	"\x03url\x18\x03 \x01(\tH\x00R\x03urlB\b\n" +
+	"\x06target\"L\n" +
+	"\n" +
+	"AnyMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12.\n" +
+	"\apayload\x18\x02 \x01(\v2\x14.google.protobuf.AnyR\apayload\"G\n" +
+	"\vDeepMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12(\n" +
+	"\x05child\x18\x02 \x01(\v2\x12.testpb.DeepLevel2R\x05child\"F\n" +
+	"\n" +
+	"DeepLevel2\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12(\n" +
+	"\x05child\x18\x02 \x01(\v2\x12.testpb.DeepLevel3R\x05child\"F\n" +
+	"\n" +
+	"DeepLevel3\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12(\n" +
+	"\x05child\x18\x02 \x01(\v2\x12.testpb.DeepLevel4R\x05child\"F\n" +
+	"\n" +
+	"DeepLevel4\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12(\n" +
+	"\x05child\x18\x02 \x01(\v2\x12.testpb.DeepLevel5R\x05child\"0\n" +
+	"\n" +
+	"DeepLevel5\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"e\n" +
+	"\x15OptionalAuthorMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x121\n" +
+	"\x06author\x18\x02 \x01(\v2\x14.testpb.BasicMessageH\x00R\x06author\x88\x01\x01B\t\n" +
+	"\a_author\"Y\n" +
+	"\vScalarValue\x12#\n" +
+	"\fstring_value\x18\x01 \x01(\tH\x00R\vstringValue\x12\x1d\n" +
+	"\tint_value\x18\x02 \x01(\x05H\x00R\bintValueB\x06\n" +
+	"\x04kind\"\xf1\x01\n" +
+	"\x0fScalarValueList\x12+\n" +
+	"\x06values\x18\x01 \x03(\v2\x13.testpb.ScalarValueR\x06values\x12G\n" +
+	"\n" +
+	"attributes\x18\x02 \x03(\v2'.testpb.ScalarValueList.AttributesEntryR\n" +
+	"attributes\x12\x14\n" +
+	"\x05names\x18\x03 \x03(\tR\x05names\x1aR\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12)\n" +
+	"\x05value\x18\x02 \x01(\v2\x13.testpb.ScalarValueR\x05value:\x028\x01\"C\n" +
+	"\x13IgnoredFieldMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x1c\n" +
+	"\x06secret\x18\x02 \x01(\tB\x04\x80\x80\x19\x01R\x06secret\"\xb6\x01\n" +
+	"\rStructMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x127\n" +
+	"\n" +
+	"attributes\x18\x02 \x01(\v2\x17.google.protobuf.StructR\n" +
+	"attributes\x12,\n" +
+	"\x05value\x18\x03 \x01(\v2\x16.google.protobuf.ValueR\x05value\x12.\n" +
+	"\x04tags\x18\x04 \x01(\v2\x1a.google.protobuf.ListValueR\x04tags\"I\n" +
+	"\x0eCounterMessage\x12\x16\n" +
+	"\x05count\x18\x01 \x01(\x03H\x00R\x05count\x12\x16\n" +
+	"\x05unset\x18\x02 \x01(\bH\x00R\x05unsetB\a\n" +
+	"\x05value*H\n" +
 	"\x06Status\x12\x16\n" +
 	"\x12STATUS_UNSPECIFIED\x10\x00\x12\x11\n" +
 	"\rSTATUS_ACTIVE\x10\x01\x12\x13\n" +
@@ -963,7 +2468,7 @@ func file_test_proto_rawDescGZIP() []byte {
 }
 
 var file_test_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_test_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_test_proto_msgTypes = make([]protoimpl.MessageInfo, 41)
 var file_test_proto_goTypes = []any{
 	(Status)(0),                     // 0: testpb.Status
 	(*BasicMessage)(nil),            // 1: testpb.BasicMessage
@@ -976,21 +2481,80 @@ var file_test_proto_goTypes = []any{
 	(*EnumMessage)(nil),             // 8: testpb.EnumMessage
 	(*OneofMessage)(nil),            // 9: testpb.OneofMessage
 	(*OneofWithMessageMessage)(nil), // 10: testpb.OneofWithMessageMessage
-	nil,                             // 11: testpb.MapMessage.LabelsEntry
-	nil,                             // 12: testpb.MapMessage.ScoresEntry
+	(*EnumMapMessage)(nil),          // 11: testpb.EnumMapMessage
+	(*IntMapMessage)(nil),           // 12: testpb.IntMapMessage
+	(*TreeNode)(nil),                // 13: testpb.TreeNode
+	(*IdentifierMessage)(nil),       // 14: testpb.IdentifierMessage
+	(*EventMessage)(nil),            // 15: testpb.EventMessage
+	(*LineItem)(nil),                // 16: testpb.LineItem
+	(*LineItemList)(nil),            // 17: testpb.LineItemList
+	(*GroupedItemsMessage)(nil),     // 18: testpb.GroupedItemsMessage
+	(*PriceMessage)(nil),            // 19: testpb.PriceMessage
+	(*LinkMessage)(nil),             // 20: testpb.LinkMessage
+	(*AnyMessage)(nil),              // 21: testpb.AnyMessage
+	(*DeepMessage)(nil),             // 22: testpb.DeepMessage
+	(*DeepLevel2)(nil),              // 23: testpb.DeepLevel2
+	(*DeepLevel3)(nil),              // 24: testpb.DeepLevel3
+	(*DeepLevel4)(nil),              // 25: testpb.DeepLevel4
+	(*DeepLevel5)(nil),              // 26: testpb.DeepLevel5
+	(*OptionalAuthorMessage)(nil),   // 27: testpb.OptionalAuthorMessage
+	(*ScalarValue)(nil),             // 28: testpb.ScalarValue
+	(*ScalarValueList)(nil),         // 29: testpb.ScalarValueList
+	(*IgnoredFieldMessage)(nil),     // 30: testpb.IgnoredFieldMessage
+	(*StructMessage)(nil),           // 31: testpb.StructMessage
+	(*CounterMessage)(nil),          // 32: testpb.CounterMessage
+	nil,                             // 33: testpb.MapMessage.LabelsEntry
+	nil,                             // 34: testpb.MapMessage.ScoresEntry
+	nil,                             // 35: testpb.EnumMapMessage.StatusesEntry
+	nil,                             // 36: testpb.IntMapMessage.CountsEntry
+	nil,                             // 37: testpb.IntMapMessage.NamesEntry
+	nil,                             // 38: testpb.IntMapMessage.CodesEntry
+	nil,                             // 39: testpb.IntMapMessage.FlagsEntry
+	nil,                             // 40: testpb.GroupedItemsMessage.GroupsEntry
+	nil,                             // 41: testpb.ScalarValueList.AttributesEntry
+	(*timestamppb.Timestamp)(nil),   // 42: google.protobuf.Timestamp
+	(*anypb.Any)(nil),               // 43: google.protobuf.Any
+	(*structpb.Struct)(nil),         // 44: google.protobuf.Struct
+	(*structpb.Value)(nil),          // 45: google.protobuf.Value
+	(*structpb.ListValue)(nil),      // 46: google.protobuf.ListValue
 }
 var file_test_proto_depIdxs = []int32{
 	1,  // 0: testpb.NestedMessage.author:type_name -> testpb.BasicMessage
-	11, // 1: testpb.MapMessage.labels:type_name -> testpb.MapMessage.LabelsEntry
-	12, // 2: testpb.MapMessage.scores:type_name -> testpb.MapMessage.ScoresEntry
+	33, // 1: testpb.MapMessage.labels:type_name -> testpb.MapMessage.LabelsEntry
+	34, // 2: testpb.MapMessage.scores:type_name -> testpb.MapMessage.ScoresEntry
 	0,  // 3: testpb.EnumMessage.status:type_name -> testpb.Status
 	1,  // 4: testpb.OneofWithMessageMessage.user:type_name -> testpb.BasicMessage
 	7,  // 5: testpb.OneofWithMessageMessage.profile:type_name -> testpb.SimpleProfile
-	6,  // [6:6] is the sub-list for method output_type
-	6,  // [6:6] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	35, // 6: testpb.EnumMapMessage.statuses:type_name -> testpb.EnumMapMessage.StatusesEntry
+	36, // 7: testpb.IntMapMessage.counts:type_name -> testpb.IntMapMessage.CountsEntry
+	37, // 8: testpb.IntMapMessage.names:type_name -> testpb.IntMapMessage.NamesEntry
+	38, // 9: testpb.IntMapMessage.codes:type_name -> testpb.IntMapMessage.CodesEntry
+	39, // 10: testpb.IntMapMessage.flags:type_name -> testpb.IntMapMessage.FlagsEntry
+	13, // 11: testpb.TreeNode.children:type_name -> testpb.TreeNode
+	13, // 12: testpb.TreeNode.left:type_name -> testpb.TreeNode
+	42, // 13: testpb.EventMessage.created_at:type_name -> google.protobuf.Timestamp
+	42, // 14: testpb.EventMessage.updated_at:type_name -> google.protobuf.Timestamp
+	16, // 15: testpb.LineItemList.items:type_name -> testpb.LineItem
+	40, // 16: testpb.GroupedItemsMessage.groups:type_name -> testpb.GroupedItemsMessage.GroupsEntry
+	43, // 17: testpb.AnyMessage.payload:type_name -> google.protobuf.Any
+	23, // 18: testpb.DeepMessage.child:type_name -> testpb.DeepLevel2
+	24, // 19: testpb.DeepLevel2.child:type_name -> testpb.DeepLevel3
+	25, // 20: testpb.DeepLevel3.child:type_name -> testpb.DeepLevel4
+	26, // 21: testpb.DeepLevel4.child:type_name -> testpb.DeepLevel5
+	1,  // 22: testpb.OptionalAuthorMessage.author:type_name -> testpb.BasicMessage
+	28, // 23: testpb.ScalarValueList.values:type_name -> testpb.ScalarValue
+	41, // 24: testpb.ScalarValueList.attributes:type_name -> testpb.ScalarValueList.AttributesEntry
+	44, // 25: testpb.StructMessage.attributes:type_name -> google.protobuf.Struct
+	45, // 26: testpb.StructMessage.value:type_name -> google.protobuf.Value
+	46, // 27: testpb.StructMessage.tags:type_name -> google.protobuf.ListValue
+	0,  // 28: testpb.EnumMapMessage.StatusesEntry.value:type_name -> testpb.Status
+	17, // 29: testpb.GroupedItemsMessage.GroupsEntry.value:type_name -> testpb.LineItemList
+	28, // 30: testpb.ScalarValueList.AttributesEntry.value:type_name -> testpb.ScalarValue
+	31, // [31:31] is the sub-list for method output_type
+	31, // [31:31] is the sub-list for method input_type
+	31, // [31:31] is the sub-list for extension type_name
+	31, // [31:31] is the sub-list for extension extendee
+	0,  // [0:31] is the sub-list for field type_name
 }
 
 func init() { file_test_proto_init() }
@@ -1009,13 +2573,30 @@ func file_test_proto_init() {
 		(*OneofWithMessageMessage_User)(nil),
 		(*OneofWithMessageMessage_Profile)(nil),
 	}
+	file_test_proto_msgTypes[13].OneofWrappers = []any{
+		(*IdentifierMessage_Name)(nil),
+		(*IdentifierMessage_Hash)(nil),
+	}
+	file_test_proto_msgTypes[19].OneofWrappers = []any{
+		(*LinkMessage_Text)(nil),
+		(*LinkMessage_Url)(nil),
+	}
+	file_test_proto_msgTypes[26].OneofWrappers = []any{}
+	file_test_proto_msgTypes[27].OneofWrappers = []any{
+		(*ScalarValue_StringValue)(nil),
+		(*ScalarValue_IntValue)(nil),
+	}
+	file_test_proto_msgTypes[31].OneofWrappers = []any{
+		(*CounterMessage_Count)(nil),
+		(*CounterMessage_Unset)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_test_proto_rawDesc), len(file_test_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   12,
+			NumMessages:   41,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
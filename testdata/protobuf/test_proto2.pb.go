@@ -0,0 +1,204 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.32.1
+// source: test_proto2.proto
+
+package testpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Proto2Message contains proto2 required, defaulted and group fields
+type Proto2Message struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          *string                `protobuf:"bytes,1,req,name=name" json:"name,omitempty"`
+	Count         *int32                 `protobuf:"varint,2,opt,name=count,def=7" json:"count,omitempty"`
+	Result        *Proto2Message_Result  `protobuf:"group,3,opt,name=Result,json=result" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+// Default values for Proto2Message fields.
+const (
+	Default_Proto2Message_Count = int32(7)
+)
+
+func (x *Proto2Message) Reset() {
+	*x = Proto2Message{}
+	mi := &file_test_proto2_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Proto2Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Proto2Message) ProtoMessage() {}
+
+func (x *Proto2Message) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto2_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Proto2Message.ProtoReflect.Descriptor instead.
+func (*Proto2Message) Descriptor() ([]byte, []int) {
+	return file_test_proto2_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Proto2Message) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *Proto2Message) GetCount() int32 {
+	if x != nil && x.Count != nil {
+		return *x.Count
+	}
+	return Default_Proto2Message_Count
+}
+
+func (x *Proto2Message) GetResult() *Proto2Message_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type Proto2Message_Result struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           *string                `protobuf:"bytes,4,opt,name=url" json:"url,omitempty"`
+	Rank          *int32                 `protobuf:"varint,5,opt,name=rank" json:"rank,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Proto2Message_Result) Reset() {
+	*x = Proto2Message_Result{}
+	mi := &file_test_proto2_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Proto2Message_Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Proto2Message_Result) ProtoMessage() {}
+
+func (x *Proto2Message_Result) ProtoReflect() protoreflect.Message {
+	mi := &file_test_proto2_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Proto2Message_Result.ProtoReflect.Descriptor instead.
+func (*Proto2Message_Result) Descriptor() ([]byte, []int) {
+	return file_test_proto2_proto_rawDescGZIP(), []int{0, 0}
+}
+
+func (x *Proto2Message_Result) GetUrl() string {
+	if x != nil && x.Url != nil {
+		return *x.Url
+	}
+	return ""
+}
+
+func (x *Proto2Message_Result) GetRank() int32 {
+	if x != nil && x.Rank != nil {
+		return *x.Rank
+	}
+	return 0
+}
+
+var File_test_proto2_proto protoreflect.FileDescriptor
+
+const file_test_proto2_proto_rawDesc = "" +
+	"\n" +
+	"\x11test_proto2.proto\x12\x06testpb\"\xa2\x01\n" +
+	"\rProto2Message\x12\x12\n" +
+	"\x04name\x18\x01 \x02(\tR\x04name\x12\x17\n" +
+	"\x05count\x18\x02 \x01(\x05:\x017R\x05count\x124\n" +
+	"\x06result\x18\x03 \x01(\n" +
+	"2\x1c.testpb.Proto2Message.ResultR\x06result\x1a.\n" +
+	"\x06Result\x12\x10\n" +
+	"\x03url\x18\x04 \x01(\tR\x03url\x12\x12\n" +
+	"\x04rank\x18\x05 \x01(\x05R\x04rankB3Z1github.com/hamba/avro/v2/testdata/protobuf;testpb"
+
+var (
+	file_test_proto2_proto_rawDescOnce sync.Once
+	file_test_proto2_proto_rawDescData []byte
+)
+
+func file_test_proto2_proto_rawDescGZIP() []byte {
+	file_test_proto2_proto_rawDescOnce.Do(func() {
+		file_test_proto2_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_test_proto2_proto_rawDesc), len(file_test_proto2_proto_rawDesc)))
+	})
+	return file_test_proto2_proto_rawDescData
+}
+
+var file_test_proto2_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_test_proto2_proto_goTypes = []any{
+	(*Proto2Message)(nil),        // 0: testpb.Proto2Message
+	(*Proto2Message_Result)(nil), // 1: testpb.Proto2Message.Result
+}
+var file_test_proto2_proto_depIdxs = []int32{
+	1, // 0: testpb.Proto2Message.result:type_name -> testpb.Proto2Message.Result
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_test_proto2_proto_init() }
+func file_test_proto2_proto_init() {
+	if File_test_proto2_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_test_proto2_proto_rawDesc), len(file_test_proto2_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_test_proto2_proto_goTypes,
+		DependencyIndexes: file_test_proto2_proto_depIdxs,
+		MessageInfos:      file_test_proto2_proto_msgTypes,
+	}.Build()
+	File_test_proto2_proto = out.File
+	file_test_proto2_proto_goTypes = nil
+	file_test_proto2_proto_depIdxs = nil
+}
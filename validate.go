@@ -0,0 +1,208 @@
+package avro
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf8"
+)
+
+// ValidationError describes the first value of Avro data that does not conform to its schema.
+type ValidationError struct {
+	// Offset is the offset in the data of the invalid value.
+	Offset int64
+	// Path is the path of the invalid value from the root schema, with record fields
+	// separated by dots and array items and map values in brackets (e.g. `items[2].name`).
+	// It is empty for the root value.
+	Path string
+	// Err is the reason the value is invalid. It is io.ErrUnexpectedEOF if the data is truncated.
+	Err error
+}
+
+// Error returns the error message.
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("avro: invalid data at offset %d: %v", e.Offset, e.Err)
+	}
+	return fmt.Sprintf("avro: invalid data at offset %d in %s: %v", e.Offset, e.Path, e.Err)
+}
+
+// Unwrap returns the reason the value is invalid.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Valid checks that data holds exactly one value of schema, without decoding it into
+// a Go value. The first violation, such as truncated data, an out of range union index
+// or enum symbol, an invalid boolean or string, or bytes left after the value, is
+// returned as a *ValidationError.
+func Valid(schema Schema, data []byte) error {
+	v := &validator{r: NewReader(nil, 0).Reset(data)}
+	if err := v.validate(schema, ""); err != nil {
+		return err
+	}
+	if n := v.r.BytesRead(); n < int64(len(data)) {
+		return &ValidationError{Offset: n, Err: fmt.Errorf("%d trailing bytes", int64(len(data))-n)}
+	}
+	return nil
+}
+
+type validator struct {
+	r *Reader
+}
+
+func (v *validator) validate(schema Schema, path string) error {
+	offset := v.r.BytesRead()
+	invalid := func(err error) error {
+		return &ValidationError{Offset: offset, Path: path, Err: err}
+	}
+
+	switch schema.Type() {
+	case Null:
+		return nil
+
+	case Boolean:
+		if b := v.r.readByte(); v.r.Error == nil && b > 1 {
+			return invalid(fmt.Errorf("invalid boolean %d", b))
+		}
+
+	case Int, Enum:
+		i := v.r.ReadInt()
+		if v.r.Error != nil {
+			break
+		}
+		if enum, ok := schema.(*EnumSchema); ok && (i < 0 || int(i) >= len(enum.Symbols())) {
+			return invalid(fmt.Errorf("enum index %d out of range", i))
+		}
+
+	case Long:
+		v.r.ReadLong()
+
+	case Float:
+		v.skip(4)
+
+	case Double:
+		v.skip(8)
+
+	case String, Bytes:
+		size := v.r.ReadLong()
+		if v.r.Error != nil {
+			break
+		}
+		if size < 0 {
+			return invalid(fmt.Errorf("invalid %s length %d", schema.Type(), size))
+		}
+		start := v.r.head
+		v.skip(size)
+		if v.r.Error == nil && schema.Type() == String && !utf8.Valid(v.r.buf[start:v.r.head]) {
+			return invalid(errors.New("string is not valid UTF-8"))
+		}
+
+	case Fixed:
+		v.skip(int64(schema.(*FixedSchema).Size()))
+
+	case Record:
+		for _, f := range schema.(*RecordSchema).Fields() {
+			if err := v.validate(f.Type(), joinValidationPath(path, f.Name())); err != nil {
+				return err
+			}
+		}
+
+	case Ref:
+		return v.validate(schema.(*RefSchema).Schema(), path)
+
+	case Array:
+		items := schema.(*ArraySchema).Items()
+		i := 0
+		return v.validateBlocks(path, func() error {
+			err := v.validate(items, path+"["+strconv.Itoa(i)+"]")
+			i++
+			return err
+		})
+
+	case Map:
+		values := schema.(*MapSchema).Values()
+		return v.validateBlocks(path, func() error {
+			keyOffset := v.r.BytesRead()
+			key := v.r.ReadString()
+			if v.r.Error != nil {
+				return v.readError(keyOffset, path)
+			}
+			return v.validate(values, path+"["+key+"]")
+		})
+
+	case Union:
+		types := schema.(*UnionSchema).Types()
+		idx := v.r.ReadLong()
+		if v.r.Error != nil {
+			break
+		}
+		if idx < 0 || idx >= int64(len(types)) {
+			return invalid(fmt.Errorf("union index %d out of range", idx))
+		}
+		return v.validate(types[idx], path)
+
+	default:
+		return invalid(fmt.Errorf("schema type %s is unsupported", schema.Type()))
+	}
+
+	if v.r.Error != nil {
+		return v.readError(offset, path)
+	}
+	return nil
+}
+
+// validateBlocks validates the blocks of an array or map, calling fn for each item.
+func (v *validator) validateBlocks(path string, fn func() error) error {
+	for {
+		offset := v.r.BytesRead()
+		l, size := v.r.ReadBlockHeader()
+		if v.r.Error != nil {
+			return v.readError(offset, path)
+		}
+		if l == 0 {
+			return nil
+		}
+		if size < 0 {
+			return &ValidationError{Offset: offset, Path: path, Err: fmt.Errorf("invalid block size %d", size)}
+		}
+		for range l {
+			start := v.r.BytesRead()
+			if err := fn(); err != nil {
+				return err
+			}
+			if v.r.BytesRead() == start {
+				// Items encoded in zero bytes (e.g. nulls) are all valid.
+				break
+			}
+		}
+	}
+}
+
+// skip skips n bytes, failing with io.ErrUnexpectedEOF if there are fewer left.
+func (v *validator) skip(n int64) {
+	if n > int64(v.r.tail-v.r.head) {
+		v.r.head = v.r.tail
+		v.r.Error = io.ErrUnexpectedEOF
+		return
+	}
+	v.r.head += int(n)
+}
+
+// readError returns the error of the Reader as a *ValidationError. Running out of
+// data is reported as io.ErrUnexpectedEOF.
+func (v *validator) readError(offset int64, path string) error {
+	err := v.r.Error
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	return &ValidationError{Offset: offset, Path: path, Err: err}
+}
+
+func joinValidationPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
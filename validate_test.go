@@ -0,0 +1,164 @@
+package avro_test
+
+import (
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var validSchema = avro.MustParse(`{
+	"type": "record",
+	"name": "Order",
+	"fields": [
+		{"name": "id", "type": "long"},
+		{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["OPEN", "CLOSED"]}},
+		{"name": "items", "type": {"type": "array", "items": {
+			"type": "record",
+			"name": "Item",
+			"fields": [
+				{"name": "name", "type": "string"},
+				{"name": "note", "type": ["null", "string"]}
+			]
+		}}},
+		{"name": "tags", "type": {"type": "map", "values": "boolean"}},
+		{"name": "price", "type": "double"}
+	]
+}`)
+
+func validOrder() map[string]any {
+	return map[string]any{
+		"id":     int64(1),
+		"status": "OPEN",
+		"items": []any{
+			map[string]any{"name": "pen", "note": nil},
+			map[string]any{"name": "ink", "note": map[string]any{"string": "blue"}},
+		},
+		"tags":  map[string]any{"gift": true},
+		"price": 9.5,
+	}
+}
+
+func TestValid(t *testing.T) {
+	data, err := avro.Marshal(validSchema, validOrder())
+	require.NoError(t, err)
+
+	err = avro.Valid(validSchema, data)
+
+	assert.NoError(t, err)
+}
+
+func TestValid_Truncated(t *testing.T) {
+	data, err := avro.Marshal(validSchema, validOrder())
+	require.NoError(t, err)
+
+	err = avro.Valid(validSchema, data[:len(data)-3])
+
+	var validErr *avro.ValidationError
+	require.ErrorAs(t, err, &validErr)
+	assert.Equal(t, "price", validErr.Path)
+	assert.Equal(t, int64(len(data)-8), validErr.Offset)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestValid_TruncatedString(t *testing.T) {
+	schema := avro.MustParse(`"string"`)
+
+	err := avro.Valid(schema, []byte{0x06, 'a', 'b'})
+
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.EqualError(t, err, "avro: invalid data at offset 0: unexpected EOF")
+}
+
+func TestValid_TrailingBytes(t *testing.T) {
+	data, err := avro.Marshal(validSchema, validOrder())
+	require.NoError(t, err)
+
+	err = avro.Valid(validSchema, append(data, 0x01, 0x02))
+
+	var validErr *avro.ValidationError
+	require.ErrorAs(t, err, &validErr)
+	assert.Equal(t, "", validErr.Path)
+	assert.Equal(t, int64(len(data)), validErr.Offset)
+	assert.EqualError(t, err, "avro: invalid data at offset "+strconv.Itoa(len(data))+": 2 trailing bytes")
+}
+
+func TestValid_UnionIndexOutOfRange(t *testing.T) {
+	data := []byte{
+		0x02,                // id
+		0x00,                // status
+		0x02,                // items block of 1
+		0x06, 'p', 'e', 'n', // name
+		0x04, // note union index 2
+	}
+
+	err := avro.Valid(validSchema, data)
+
+	var validErr *avro.ValidationError
+	require.ErrorAs(t, err, &validErr)
+	assert.Equal(t, "items[0].note", validErr.Path)
+	assert.Equal(t, int64(7), validErr.Offset)
+	assert.EqualError(t, err, "avro: invalid data at offset 7 in items[0].note: union index 2 out of range")
+}
+
+func TestValid_InvalidValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		data    []byte
+		wantErr string
+	}{
+		{
+			name:    "enum index",
+			schema:  `{"type": "enum", "name": "Status", "symbols": ["OPEN", "CLOSED"]}`,
+			data:    []byte{0x04},
+			wantErr: "avro: invalid data at offset 0: enum index 2 out of range",
+		},
+		{
+			name:    "boolean",
+			schema:  `"boolean"`,
+			data:    []byte{0x02},
+			wantErr: "avro: invalid data at offset 0: invalid boolean 2",
+		},
+		{
+			name:    "negative length",
+			schema:  `"bytes"`,
+			data:    []byte{0x01},
+			wantErr: "avro: invalid data at offset 0: invalid bytes length -1",
+		},
+		{
+			name:    "invalid utf8",
+			schema:  `"string"`,
+			data:    []byte{0x02, 0xff},
+			wantErr: "avro: invalid data at offset 0: string is not valid UTF-8",
+		},
+		{
+			name:    "map value",
+			schema:  `{"type": "map", "values": "boolean"}`,
+			data:    []byte{0x02, 0x02, 'a', 0x05, 0x00},
+			wantErr: "avro: invalid data at offset 3 in [a]: invalid boolean 5",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schema := avro.MustParse(test.schema)
+
+			err := avro.Valid(schema, test.data)
+
+			assert.EqualError(t, err, test.wantErr)
+		})
+	}
+}
+
+func TestValid_ZeroSizeItems(t *testing.T) {
+	schema := avro.MustParse(`{"type": "array", "items": "null"}`)
+
+	// A block of a huge number of nulls.
+	err := avro.Valid(schema, []byte{0xfe, 0xff, 0xff, 0xff, 0x0f, 0x00})
+
+	assert.NoError(t, err)
+}
@@ -2,8 +2,10 @@ package avro
 
 import (
 	"encoding/binary"
+	"fmt"
 	"io"
 	"math"
+	"math/big"
 )
 
 // WriterFunc is a function used to customize the Writer.
@@ -12,7 +14,7 @@ type WriterFunc func(w *Writer)
 // WithWriterConfig specifies the configuration to use with a writer.
 func WithWriterConfig(cfg API) WriterFunc {
 	return func(w *Writer) {
-		w.cfg = cfg.(*frozenConfig)
+		w.cfg = mustFrozenConfig(cfg)
 	}
 }
 
@@ -27,7 +29,7 @@ type Writer struct {
 // NewWriter creates a new Writer.
 func NewWriter(out io.Writer, bufSize int, opts ...WriterFunc) *Writer {
 	writer := &Writer{
-		cfg:   DefaultConfig.(*frozenConfig),
+		cfg:   mustFrozenConfig(DefaultConfig),
 		out:   out,
 		buf:   make([]byte, 0, bufSize),
 		Error: nil,
@@ -40,10 +42,12 @@ func NewWriter(out io.Writer, bufSize int, opts ...WriterFunc) *Writer {
 	return writer
 }
 
-// Reset resets the Writer with a new io.Writer attached.
+// Reset resets the Writer with a new io.Writer attached, reusing its buffer.
+// Any error is cleared.
 func (w *Writer) Reset(out io.Writer) {
 	w.out = out
 	w.buf = w.buf[:0]
+	w.Error = nil
 }
 
 // Buffered returns the number of buffered bytes.
@@ -51,7 +55,8 @@ func (w *Writer) Buffered() int {
 	return len(w.buf)
 }
 
-// Buffer gets the Writer buffer.
+// Buffer gets the Writer buffer, holding the bytes written since the last flush.
+// The buffer is only valid until the next write or reset.
 func (w *Writer) Buffer() []byte {
 	return w.buf
 }
@@ -91,6 +96,10 @@ func (w *Writer) Write(b []byte) (int, error) {
 	return len(b), nil
 }
 
+// WriteNull writes a Null to the Writer. Null is encoded as zero bytes, so nothing
+// is written; it makes the layout of values written by hand explicit.
+func (w *Writer) WriteNull() {}
+
 // WriteBool writes a Bool to the Writer.
 func (w *Writer) WriteBool(b bool) {
 	if b {
@@ -157,6 +166,67 @@ func (w *Writer) WriteString(s string) {
 	w.buf = append(w.buf, s...)
 }
 
+// WriteDecimal writes a decimal as Avro bytes holding its unscaled value, as described by
+// the bytes decimal logical type with the given scale and precision. An error is set if
+// the value has more fractional digits than the scale or more digits than the precision.
+func (w *Writer) WriteDecimal(value *big.Rat, scale, precision int) {
+	i := new(big.Int).Mul(value.Num(), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil))
+	i, rem := i.QuoRem(i, value.Denom(), new(big.Int))
+	if rem.Sign() != 0 {
+		w.Error = fmt.Errorf("avro: cannot write decimal %s with scale=%d without truncation", value.RatString(), scale)
+		return
+	}
+	if numDigits, ok := checkDecimalPrecision(i, precision); !ok {
+		w.Error = fmt.Errorf(
+			"avro: cannot write decimal %s with precision=%d, has %d significant digits",
+			value.FloatString(scale),
+			precision,
+			numDigits,
+		)
+		return
+	}
+	w.WriteBytes(bytesDecimalBytes(i))
+}
+
+// WriteNullableIndex writes the union index of a nullable union whose null
+// type comes first, such as ["null", "string"]. Index 0 is written when isNull
+// is true, otherwise index 1.
+func (w *Writer) WriteNullableIndex(isNull bool) {
+	if isNull {
+		w.WriteLong(0)
+		return
+	}
+	w.WriteLong(1)
+}
+
+// WriteArrayStart starts a block of n array items, which must be written next.
+// An array can be written as several blocks, and must be terminated with
+// WriteArrayEnd. No block is started when n is 0, as an empty block ends the array.
+func (w *Writer) WriteArrayStart(n int) {
+	if n > 0 {
+		w.WriteLong(int64(n))
+	}
+}
+
+// WriteArrayEnd ends an array, writing the terminating empty block.
+func (w *Writer) WriteArrayEnd() {
+	w.WriteLong(0)
+}
+
+// WriteMapStart starts a block of n map entries, each a string key followed by
+// its value, which must be written next. A map can be written as several blocks,
+// and must be terminated with WriteMapEnd. No block is started when n is 0.
+func (w *Writer) WriteMapStart(n int) {
+	if n > 0 {
+		w.WriteLong(int64(n))
+	}
+}
+
+// WriteMapEnd ends a map, writing the terminating empty block.
+func (w *Writer) WriteMapEnd() {
+	w.WriteLong(0)
+}
+
 // WriteBlockHeader writes a Block Header to the Writer.
 func (w *Writer) WriteBlockHeader(l, s int64) {
 	if s > 0 && !w.cfg.config.DisableBlockSizeHeader {
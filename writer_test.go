@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"io"
+	"math/big"
 	"testing"
 
 	"github.com/hamba/avro/v2"
@@ -23,6 +24,17 @@ func TestWriter_Reset(t *testing.T) {
 	assert.Equal(t, []byte("test"), buf.Bytes())
 }
 
+func TestWriter_ResetClearsError(t *testing.T) {
+	w := avro.NewWriter(nil, 10)
+	w.Error = errors.New("test")
+	_, _ = w.Write([]byte("test"))
+
+	w.Reset(nil)
+
+	assert.NoError(t, w.Error)
+	assert.Equal(t, 0, w.Buffered())
+}
+
 func TestWriter_Buffer(t *testing.T) {
 	w := avro.NewWriter(nil, 10)
 	_, _ = w.Write([]byte("test"))
@@ -460,6 +472,106 @@ func TestWriter_WriteBlockHeader(t *testing.T) {
 	}
 }
 
+func TestWriter_WriteNullableIndex(t *testing.T) {
+	w := avro.NewWriter(nil, 50)
+
+	w.WriteNullableIndex(true)
+	w.WriteNullableIndex(false)
+
+	assert.Equal(t, []byte{0x00, 0x02}, w.Buffer())
+}
+
+func TestWriter_WriteNull(t *testing.T) {
+	w := avro.NewWriter(nil, 50)
+
+	w.WriteNull()
+
+	assert.Empty(t, w.Buffer())
+}
+
+func TestWriter_WriteArrayAndMapBlocks(t *testing.T) {
+	w := avro.NewWriter(nil, 50)
+
+	w.WriteArrayStart(2)
+	w.WriteInt(1)
+	w.WriteInt(2)
+	w.WriteArrayStart(0)
+	w.WriteArrayStart(1)
+	w.WriteInt(3)
+	w.WriteArrayEnd()
+	w.WriteMapStart(0)
+	w.WriteMapEnd()
+	w.WriteMapStart(1)
+	w.WriteString("a")
+	w.WriteInt(4)
+	w.WriteMapEnd()
+
+	want := []byte{0x04, 0x02, 0x04, 0x02, 0x06, 0x00, 0x00, 0x02, 0x02, 0x61, 0x08, 0x00}
+	assert.Equal(t, want, w.Buffer())
+}
+
+func TestWriter_WriteDecimal(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     *big.Rat
+		scale     int
+		precision int
+		want      []byte
+	}{
+		{name: "Zero", value: big.NewRat(0, 1), scale: 2, precision: 4, want: []byte{0x02, 0x00}},
+		{name: "Positive", value: big.NewRat(123, 100), scale: 2, precision: 4, want: []byte{0x02, 0x7b}},
+		{name: "Negative", value: big.NewRat(-123, 100), scale: 2, precision: 4, want: []byte{0x02, 0x85}},
+		{name: "Scale Padding", value: big.NewRat(3, 2), scale: 4, precision: 6, want: []byte{0x04, 0x3a, 0x98}},
+		{name: "Multi Byte", value: big.NewRat(1280, 1), scale: 2, precision: 6, want: []byte{0x06, 0x01, 0xf4, 0x00}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := avro.NewWriter(nil, 50)
+
+			w.WriteDecimal(test.value, test.scale, test.precision)
+
+			require.NoError(t, w.Error)
+			assert.Equal(t, test.want, w.Buffer())
+		})
+	}
+}
+
+func TestWriter_WriteDecimalError(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     *big.Rat
+		scale     int
+		precision int
+		wantErr   string
+	}{
+		{
+			name:      "Truncation",
+			value:     big.NewRat(1234, 1000),
+			scale:     2,
+			precision: 4,
+			wantErr:   "avro: cannot write decimal 617/500 with scale=2 without truncation",
+		},
+		{
+			name:      "Precision",
+			value:     big.NewRat(-12345, 1),
+			scale:     0,
+			precision: 4,
+			wantErr:   "avro: cannot write decimal -12345 with precision=4, has 5 significant digits",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := avro.NewWriter(nil, 50)
+
+			w.WriteDecimal(test.value, test.scale, test.precision)
+
+			assert.EqualError(t, w.Error, test.wantErr)
+		})
+	}
+}
+
 func TestWriter_WriteBlockCB(t *testing.T) {
 	tests := []struct {
 		disableSize   bool